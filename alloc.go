@@ -0,0 +1,180 @@
+package sc
+
+import (
+	"fmt"
+	"sync"
+)
+
+// allocator allocates contiguous blocks of indices (e.g. bus channels or buffer numbers).
+// It is safe for concurrent use.
+type allocator struct {
+	mu     sync.Mutex
+	name   string
+	offset int32  // offset is the first index that can be allocated
+	used   []bool // used reports which indices (relative to offset) are in use
+}
+
+// newAllocator creates an allocator for the indices in [offset, offset+size).
+func newAllocator(name string, offset, size int) *allocator {
+	if size < 0 {
+		size = 0
+	}
+	return &allocator{
+		name:   name,
+		offset: int32(offset),
+		used:   make([]bool, size),
+	}
+}
+
+// alloc allocates n contiguous indices and returns the first one.
+func (a *allocator) alloc(n int) (int32, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("can not allocate %d %s", n, a.name)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for start := 0; start+n <= len(a.used); start++ {
+		free := true
+		for i := start; i < start+n; i++ {
+			if a.used[i] {
+				free = false
+				start = i // Skip past the used index.
+				break
+			}
+		}
+		if !free {
+			continue
+		}
+		for i := start; i < start+n; i++ {
+			a.used[i] = true
+		}
+		return a.offset + int32(start), nil
+	}
+	return 0, fmt.Errorf("could not allocate %d %s: only %d available", n, a.name, len(a.used))
+}
+
+// reserve marks n indices starting at idx as used.
+// It returns an error if any of them is already in use.
+func (a *allocator) reserve(idx int32, n int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := int(idx - a.offset)
+	if start < 0 || n < 0 || start+n > len(a.used) {
+		return fmt.Errorf("can not reserve %d %s starting at %d: out of range", n, a.name, idx)
+	}
+	for i := start; i < start+n; i++ {
+		if a.used[i] {
+			return fmt.Errorf("can not reserve %d %s starting at %d: %d is in use", n, a.name, idx, a.offset+int32(i))
+		}
+	}
+	for i := start; i < start+n; i++ {
+		a.used[i] = true
+	}
+	return nil
+}
+
+// free frees n indices starting at idx.
+func (a *allocator) free(idx int32, n int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := int(idx - a.offset)
+	if start < 0 || n < 0 || start+n > len(a.used) {
+		return fmt.Errorf("can not free %d %s starting at %d: out of range", n, a.name, idx)
+	}
+	for i := start; i < start+n; i++ {
+		a.used[i] = false
+	}
+	return nil
+}
+
+// allocators are the bus and buffer allocators of a client.
+type allocators struct {
+	audioBuses   *allocator
+	controlBuses *allocator
+	buffers      *allocator
+}
+
+// newAllocators creates allocators that are sized to match the provided server options.
+// Audio buses that are used for hardware input and output are never allocated.
+func newAllocators(opts ServerOptions) allocators {
+	hw := opts.numInputBusChannels() + opts.numOutputBusChannels()
+	return allocators{
+		audioBuses:   newAllocator("audio bus channels", hw, opts.numAudioBusChannels()-hw),
+		controlBuses: newAllocator("control bus channels", 0, opts.numControlBusChannels()),
+		buffers:      newAllocator("buffers", 0, opts.numBuffers()),
+	}
+}
+
+// SetOptions tells the client which options scsynth was started with,
+// so the client's bus and buffer allocators match the server.
+// It should be called before anything is allocated,
+// since it resets the allocators.
+func (c *Client) SetOptions(opts ServerOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	c.optionsMu.Lock()
+	c.options = opts
+	c.allocators = newAllocators(opts)
+	c.optionsMu.Unlock()
+	return nil
+}
+
+// Options returns the server options that were set with SetOptions.
+func (c *Client) Options() ServerOptions {
+	c.optionsMu.RLock()
+	defer c.optionsMu.RUnlock()
+	return c.options
+}
+
+// getAllocators returns the client's allocators.
+func (c *Client) getAllocators() allocators {
+	c.optionsMu.RLock()
+	defer c.optionsMu.RUnlock()
+	return c.allocators
+}
+
+// AllocAudioBus allocates numChannels contiguous audio bus channels
+// and returns the index of the first one.
+// Hardware input and output channels are never allocated.
+func (c *Client) AllocAudioBus(numChannels int) (int32, error) {
+	return c.getAllocators().audioBuses.alloc(numChannels)
+}
+
+// FreeAudioBus frees audio bus channels that were allocated with AllocAudioBus.
+func (c *Client) FreeAudioBus(idx int32, numChannels int) error {
+	return c.getAllocators().audioBuses.free(idx, numChannels)
+}
+
+// AllocControlBus allocates numChannels contiguous control bus channels
+// and returns the index of the first one.
+func (c *Client) AllocControlBus(numChannels int) (int32, error) {
+	return c.getAllocators().controlBuses.alloc(numChannels)
+}
+
+// FreeControlBus frees control bus channels that were allocated with AllocControlBus.
+func (c *Client) FreeControlBus(idx int32, numChannels int) error {
+	return c.getAllocators().controlBuses.free(idx, numChannels)
+}
+
+// AllocBufferNum allocates a buffer number.
+// AllocBuffer uses this to choose the number of the buffer it allocates.
+func (c *Client) AllocBufferNum() (int32, error) {
+	return c.getAllocators().buffers.alloc(1)
+}
+
+// ReserveBufferNum marks a buffer number that was chosen by the caller as used,
+// so that AllocBufferNum doesn't return it.
+// It returns an error if the buffer number is already in use.
+func (c *Client) ReserveBufferNum(num int32) error {
+	return c.getAllocators().buffers.reserve(num, 1)
+}
+
+// FreeBufferNum frees a buffer number that was allocated with AllocBufferNum
+// or reserved with ReserveBufferNum.
+func (c *Client) FreeBufferNum(num int32) error {
+	return c.getAllocators().buffers.free(num, 1)
+}
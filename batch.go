@@ -0,0 +1,182 @@
+package sc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+// DefaultBatchMTU is the default maximum size in bytes of a batched bundle.
+// It is the largest UDP payload that fits in a single ethernet frame.
+const DefaultBatchMTU = 1472
+
+// bundleHeaderSize is the size of "#bundle" plus the time tag.
+const bundleHeaderSize = 16
+
+// replyAddresses are the addresses of commands that scsynth replies to.
+// Sending one of these commands flushes the batch immediately,
+// so the client doesn't have to wait for the batch window to get a reply.
+var replyAddresses = map[string]struct{}{
+	bufferAllocAddress:       {},
+	bufferCloseAddress:       {},
+	bufferFreeAddress:        {},
+	bufferGenAddress:         {},
+	bufferQueryAddress:       {},
+	bufferReadAddress:        {},
+	bufferReadChannelAddress: {},
+	bufferWriteAddress:       {},
+	groupQueryTreeAddress:    {},
+	notifyAddress:            {},
+	statusAddress:            {},
+	syncAddress:              {},
+	synthdefReceiveAddress:   {},
+}
+
+// batch collects outgoing messages so they can be sent in a single bundle.
+type batch struct {
+	mu      sync.Mutex
+	window  time.Duration
+	mtu     int
+	pending []osc.Message
+	timer   *time.Timer
+	err     error // err is the error from the last flush that was triggered by the timer
+}
+
+// ControlPeriod returns the duration of one control period (one block of samples).
+// This is a good batch window for clients that update controls every block.
+func ControlPeriod(blockSize int, sampleRate float64) time.Duration {
+	return time.Duration(float64(blockSize) / sampleRate * float64(time.Second))
+}
+
+// EnableBatching makes the client collect outgoing messages for the duration
+// of window and send them to scsynth as a single bundle.
+// Bundles are split so that none of them is larger than mtu bytes
+// (if mtu <= 0 then DefaultBatchMTU is used).
+// Message order is always preserved.
+// Messages that scsynth replies to (e.g. /status and /d_recv) are sent immediately
+// along with any pending messages.
+// If batching was already enabled, pending messages are flushed first.
+func (c *Client) EnableBatching(window time.Duration, mtu int) error {
+	if mtu <= 0 {
+		mtu = DefaultBatchMTU
+	}
+	err := c.Flush()
+
+	c.batchMu.Lock()
+	c.batch = &batch{window: window, mtu: mtu}
+	c.batchMu.Unlock()
+
+	return err
+}
+
+// DisableBatching flushes any pending messages and makes the client
+// send every message immediately.
+func (c *Client) DisableBatching() error {
+	err := c.Flush()
+
+	c.batchMu.Lock()
+	c.batch = nil
+	c.batchMu.Unlock()
+
+	return err
+}
+
+// Flush immediately sends any messages that have been collected
+// since batching was enabled with EnableBatching.
+// It does nothing if batching is not enabled.
+func (c *Client) Flush() error {
+	b := c.getBatch()
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return c.flush(b)
+}
+
+// flush sends the pending messages in b.
+// The caller must hold b.mu.
+func (c *Client) flush(b *batch) error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	// Report the error from the last timed flush, if there was one.
+	timedErr := b.err
+	b.err = nil
+
+	pending := b.pending
+	b.pending = nil
+
+	for _, msgs := range splitBatch(pending, b.mtu) {
+		if len(msgs) == 1 {
+			if err := c.sendNow(msgs[0]); err != nil {
+				return err
+			}
+			continue
+		}
+		bun := osc.Bundle{Packets: make([]osc.Packet, len(msgs))}
+		for i, msg := range msgs {
+			bun.Packets[i] = msg
+		}
+		if err := c.sendNow(bun); err != nil {
+			return err
+		}
+	}
+	return timedErr
+}
+
+// enqueue adds a message to a batch.
+func (c *Client) enqueue(b *batch, msg osc.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, msg)
+
+	if _, ok := replyAddresses[msg.Address]; ok {
+		return c.flush(b)
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			if err := c.flush(b); err != nil {
+				b.err = err
+			}
+			b.mu.Unlock()
+		})
+	}
+	return nil
+}
+
+// getBatch returns the client's batch, which is nil if batching is not enabled.
+func (c *Client) getBatch() *batch {
+	c.batchMu.RLock()
+	defer c.batchMu.RUnlock()
+	return c.batch
+}
+
+// splitBatch splits a list of messages into groups that each fit in a bundle
+// that is no larger than mtu bytes.
+// A message that is too large to fit in a bundle by itself gets its own group.
+func splitBatch(msgs []osc.Message, mtu int) [][]osc.Message {
+	var (
+		groups = [][]osc.Message{}
+		group  = []osc.Message{}
+		size   = bundleHeaderSize
+	)
+	for _, msg := range msgs {
+		msgSize := 4 + len(msg.Bytes()) // Bundle elements are prefixed with their size.
+		if len(group) > 0 && size+msgSize > mtu {
+			groups = append(groups, group)
+			group, size = []osc.Message{}, bundleHeaderSize
+		}
+		group = append(group, msg)
+		size += msgSize
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
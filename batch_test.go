@@ -0,0 +1,114 @@
+package sc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestSplitBatch(t *testing.T) {
+	msg := osc.Message{
+		Address:   nodeSetAddress,
+		Arguments: osc.Arguments{osc.Int(1000), osc.String("freq"), osc.Float(440)},
+	}
+	msgSize := 4 + len(msg.Bytes())
+
+	msgs := make([]osc.Message, 10)
+	for i := range msgs {
+		msgs[i] = msg
+	}
+	groups := splitBatch(msgs, bundleHeaderSize+3*msgSize)
+	if expected, got := 4, len(groups); expected != got {
+		t.Fatalf("expected %d groups, got %d", expected, got)
+	}
+	for i, expected := range []int{3, 3, 3, 1} {
+		if got := len(groups[i]); expected != got {
+			t.Fatalf("expected group %d to have %d messages, got %d", i, expected, got)
+		}
+	}
+	// Messages that are larger than the MTU get their own group.
+	if expected, got := 2, len(splitBatch(msgs[:2], 8)); expected != got {
+		t.Fatalf("expected %d groups, got %d", expected, got)
+	}
+}
+
+func TestControlPeriod(t *testing.T) {
+	if expected, got := 1333333*time.Nanosecond, ControlPeriod(64, 48000); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestClientBatching(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	if err := client.EnableBatching(time.Hour, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := client.NodeSet(1000, map[string]float32{"freq": float32(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := len(fs.Received()); got != 0 {
+		t.Fatalf("expected messages to be batched, but the server received %d", got)
+	}
+	// Status requests flush the batch.
+	if _, err := client.Status(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	received := fs.Received()
+	if expected, got := 6, len(received); expected != got {
+		t.Fatalf("expected the server to receive %d messages, got %d", expected, got)
+	}
+	for i, msg := range received[:5] {
+		freq, err := msg.Arguments[2].ReadFloat32()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if float32(i) != freq {
+			t.Fatalf("expected message %d to set freq to %d, got %f", i, i, freq)
+		}
+	}
+	if expected, got := statusAddress, received[5].Address; expected != got {
+		t.Fatalf("expected last message to be %s, got %s", expected, got)
+	}
+	// Messages are sent after the batch window.
+	if err := client.EnableBatching(10*time.Millisecond, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.FreeAll(DefaultGroupID); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if expected, got := 7, len(fs.Received()); expected != got {
+		t.Fatalf("expected the server to receive %d messages, got %d", expected, got)
+	}
+	if err := client.DisableBatching(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestClientBatchingLogin checks that /notify is not held back by the batch window.
+func TestClientBatchingLogin(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	if err := client.EnableBatching(time.Hour, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.NodeSet(1000, map[string]float32{"freq": 440}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Login(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	received := fs.Received()
+	if expected, got := 2, len(received); expected != got {
+		t.Fatalf("expected the server to receive %d messages, got %d", expected, got)
+	}
+	if expected, got := notifyAddress, received[1].Address; expected != got {
+		t.Fatalf("expected last message to be %s, got %s", expected, got)
+	}
+}
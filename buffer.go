@@ -71,6 +71,64 @@ func (buffer *Buffer) Gen(routine string, flags int, args ...float32) error {
 	return nil
 }
 
+// Write writes the buffer to a sound file.
+// headerFormat should be one of the Header constants and sampleFormat
+// should be one of the Sample constants.
+// If frames is -1 the whole buffer is written, starting at startFrame.
+// If leaveOpen is true the file is left open so that it can be written
+// to by a DiskOut ugen, and Close must be called when writing is done.
+func (buffer *Buffer) Write(path, headerFormat, sampleFormat string, frames, startFrame int, leaveOpen bool) error {
+	if err := validateNRTFormats(headerFormat, sampleFormat); err != nil {
+		return err
+	}
+	open := int32(0)
+	if leaveOpen {
+		open = 1
+	}
+	if err := buffer.client.send(osc.Message{
+		Address: bufferWriteAddress,
+		Arguments: osc.Arguments{
+			osc.Int(buffer.Num),
+			osc.String(path),
+			osc.String(headerFormat),
+			osc.String(sampleFormat),
+			osc.Int(int32(frames)),
+			osc.Int(int32(startFrame)),
+			osc.Int(open),
+		},
+	}); err != nil {
+		return err
+	}
+	return buffer.client.awaitDone(bufferWriteAddress, buffer.Num)
+}
+
+// Close closes the sound file that was left open by Write.
+func (buffer *Buffer) Close() error {
+	if err := buffer.client.send(osc.Message{
+		Address:   bufferCloseAddress,
+		Arguments: osc.Arguments{osc.Int(buffer.Num)},
+	}); err != nil {
+		return err
+	}
+	return buffer.client.awaitDone(bufferCloseAddress, buffer.Num)
+}
+
+// Free frees the buffer on the server and releases its buffer number.
+func (buffer *Buffer) Free() error {
+	if err := buffer.client.send(osc.Message{
+		Address:   bufferFreeAddress,
+		Arguments: osc.Arguments{osc.Int(buffer.Num)},
+	}); err != nil {
+		return err
+	}
+	if err := buffer.client.awaitDone(bufferFreeAddress, buffer.Num); err != nil {
+		return err
+	}
+	forgetReadBuffer(buffer)
+
+	return buffer.client.FreeBufferNum(buffer.Num)
+}
+
 // sendGenMsg sends a /b_gen command.
 func (buffer *Buffer) sendGenMsg(routine string, flags int, args ...float32) error {
 	msg := osc.Message{
@@ -84,7 +142,7 @@ func (buffer *Buffer) sendGenMsg(routine string, flags int, args ...float32) err
 	for _, arg := range args {
 		msg.Arguments = append(msg.Arguments, osc.Float(arg))
 	}
-	if err := buffer.client.oscConn.Send(msg); err != nil {
+	if err := buffer.client.send(msg); err != nil {
 		return err
 	}
 	return nil
@@ -143,29 +201,51 @@ func checkBufferGenFlags(flags int) error {
 	return nil
 }
 
-// global buffer map (keys are paths to audio files on disk)
+// readBufferKey identifies a buffer that was read from an audio file on a particular server.
+type readBufferKey struct {
+	client *Client
+	path   string
+}
+
+// global buffer map (keys are clients and paths to audio files on disk)
 var buffers = struct {
 	sync.RWMutex
-	m map[string]*Buffer
-}{m: make(map[string]*Buffer)}
+	m map[readBufferKey]*Buffer
+}{m: make(map[readBufferKey]*Buffer)}
+
+// newReadBuffer creates a new buffer for /b_allocRead.
+// If the file has already been read on the client's server the existing
+// buffer is returned, otherwise num is reserved in the client's allocator.
+// The second return value is true if the buffer is new.
+func newReadBuffer(path string, num int32, c *Client) (*Buffer, bool, error) {
+	key := readBufferKey{client: c, path: path}
+
+	buffers.Lock()
+	defer buffers.Unlock()
 
-// newReadBuffer creates a new buffer for /b_allocRead
-func newReadBuffer(path string, num int32, c *Client) *Buffer {
-	buffers.RLock()
 	// return the existing buffer if there is one
-	if existing, exists := buffers.m[path]; exists {
-		buffers.RUnlock()
-		return existing
+	if existing, exists := buffers.m[key]; exists {
+		return existing, false, nil
 	}
-	buffers.RUnlock()
-
-	// make a new one
+	if err := c.ReserveBufferNum(num); err != nil {
+		return nil, false, err
+	}
+	// make a new one and add it to the global map
 	b := &Buffer{Num: num, client: c}
+	buffers.m[key] = b
+
+	return b, true, nil
+}
 
-	// add it to the global map
+// forgetReadBuffer removes a buffer from the global map of buffers
+// that were read from audio files.
+func forgetReadBuffer(buffer *Buffer) {
 	buffers.Lock()
-	buffers.m[path] = b
-	buffers.Unlock()
+	defer buffers.Unlock()
 
-	return b
+	for key, b := range buffers.m {
+		if b == buffer {
+			delete(buffers.m, key)
+		}
+	}
 }
@@ -12,17 +12,57 @@ func TestBuffer(t *testing.T) {
 	}
 	defer func() { _ = c.Close() }() // Best effort.
 
-	buf := newReadBuffer("foo", 0, c)
+	buf, _, err := newReadBuffer("foo", 0, c)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if buf.Num != 0 {
 		t.Fatalf("expected 0, but got %d", buf.Num)
 	}
-	buf = newReadBuffer("bar", 1, c)
+	buf, _, err = newReadBuffer("bar", 1, c)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if buf.Num != 1 {
 		t.Fatalf("expected 1, but got %d", buf.Num)
 	}
 	// should return the first buffer
-	newBuf := newReadBuffer("foo", 0, c)
-	if newBuf.Num != 0 {
-		t.Fatalf("expected 0, but got %d", newBuf.Num)
+	newBuf, isNew, err := newReadBuffer("foo", 0, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBuf.Num != 0 || isNew {
+		t.Fatalf("expected the existing buffer 0, but got %d", newBuf.Num)
+	}
+	// the number is taken by "bar"
+	if _, _, err := newReadBuffer("baz", 1, c); err == nil {
+		t.Fatal("expected an error for a buffer number that is in use")
+	}
+}
+
+// TestReadBufferNum checks that ReadBuffer and AllocBuffer don't use the same buffer number.
+func TestReadBufferNum(t *testing.T) {
+	c, _ := newFakeClient(t)
+
+	read, err := c.ReadBuffer("read_buffer_num.wav", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alloced, err := c.AllocBuffer(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Num == alloced.Num {
+		t.Fatalf("expected different buffer numbers, both are %d", read.Num)
+	}
+	if _, err := c.ReadBuffer("read_buffer_num_2.wav", alloced.Num); err == nil {
+		t.Fatalf("expected an error reading into buffer %d which is in use", alloced.Num)
+	}
+	// Freeing the buffer releases its number.
+	if err := read.Free(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadBuffer("read_buffer_num_2.wav", read.Num); err != nil {
+		t.Fatal(err)
 	}
 }
@@ -15,13 +15,18 @@ import (
 // See http://doc.sccode.org/Reference/Server-Command-Reference.html.
 const (
 	bufferAllocAddress         = "/b_alloc"
+	bufferCloseAddress         = "/b_close"
+	bufferFreeAddress          = "/b_free"
 	bufferGenAddress           = "/b_gen"
 	bufferInfoAddress          = "/b_info"
 	bufferQueryAddress         = "/b_query"
 	bufferReadAddress          = "/b_allocRead"
 	bufferReadChannelAddress   = "/b_allocReadChannel"
+	bufferWriteAddress         = "/b_write"
+	controlBusSetAddress       = "/c_set"
 	doneOscAddress             = "/done"
 	dumpOscAddress             = "/dumpOSC"
+	failAddress                = "/fail"
 	groupDeepFreeAddress       = "/g_deepFree"
 	groupDumpTreeAddress       = "/g_dumpTree"
 	groupFreeAllAddress        = "/g_freeAll"
@@ -39,10 +44,16 @@ const (
 	nodeRunAddress             = "/n_run"
 	nodeSetAddress             = "/n_set"
 	nodeSetnAddress            = "/n_setn"
+	notifyAddress              = "/notify"
+	parGroupNewAddress         = "/p_new"
+	pluginCommandAddress       = "/cmd"
+	quitAddress                = "/quit"
 	statusAddress              = "/status"
 	statusReplyAddress         = "/status.reply"
 	synthNewAddress            = "/s_new"
 	synthdefReceiveAddress     = "/d_recv"
+	syncAddress                = "/sync"
+	ugenCommandAddress         = "/u_cmd"
 )
 
 // Arguments to dumpOSC command.
@@ -79,6 +90,10 @@ const (
 
 	// DefaultConnectTimeout is the default timeout for connecting to scsynth.
 	DefaultConnectTimeout = time.Second
+
+	// DefaultDoneTimeout is how long the client waits for scsynth to reply
+	// with /done to commands that take a while (e.g. writing a sound file).
+	DefaultDoneTimeout = 5 * time.Second
 )
 
 // Common errors.
@@ -96,14 +111,43 @@ type Client struct {
 	closed     int32
 
 	addr    *net.UDPAddr
-	oscConn osc.Conn
+	oscConn conn
+	tcp     *tcpConn // tcp is the connection to scsynth if the client uses TCP
 
 	bufferInfoChan chan osc.Message // bufferInfoChan relays /b_info messages
 	doneChan       chan osc.Message // doneChan relays /done messages
+	notifyFailChan chan osc.Message // notifyFailChan relays /fail /notify messages
 	statusChan     chan osc.Message // statusChan relays /status.reply messages
 	gqueryTreeChan chan osc.Message // gqueryTreeChan relays /done messages
 
 	nextSynthID int32 // next synth node ID
+
+	batchMu sync.RWMutex
+	batch   *batch // batch collects outgoing messages if batching is enabled
+
+	recorderMu sync.RWMutex
+	recorder   *TrafficRecorder // recorder records OSC traffic if it is not nil
+
+	statusMu     sync.RWMutex
+	lastStatus   *StatusSnapshot // lastStatus is the most recent snapshot collected by WatchStatus
+	statusErrors uint64          // statusErrors counts failed status requests made by WatchStatus
+
+	groupsMu sync.Mutex
+	groups   map[int32]clientGroup // groups are the groups created with Group and ParGroup
+
+	optionsMu  sync.RWMutex
+	options    ServerOptions // options are the options scsynth was started with
+	allocators allocators    // allocators allocate buses and buffer numbers
+
+	loginMu sync.RWMutex
+	login   *Login // login is set by Login and cleared by Logout
+}
+
+// conn is a connection to scsynth.
+type conn interface {
+	Close() error
+	Send(osc.Packet) error
+	Serve(int, osc.Dispatcher) error
 }
 
 // number of concurrent handlers for /done messages.
@@ -112,24 +156,36 @@ const numDoneHandlers = 8
 // NewClient creates a new SuperCollider client.
 // The client will bind to the provided address and port
 // to receive messages from scsynth.
+// If network is "tcp" the local address is not used,
+// see NewTCPClient.
 func NewClient(network, local, scsynth string, timeout time.Duration) (*Client, error) {
+	if network == "tcp" {
+		return NewTCPClient(scsynth, "", timeout)
+	}
 	addr, err := net.ResolveUDPAddr(network, local)
 	if err != nil {
 		return nil, err
 	}
-	c := &Client{
+	c := newClient(addr)
+	if err := c.Connect(scsynth, timeout); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newClient creates a client that is not connected.
+func newClient(addr *net.UDPAddr) *Client {
+	return &Client{
 		errChan:        make(chan error),
 		bufferInfoChan: make(chan osc.Message),
 		doneChan:       make(chan osc.Message, numDoneHandlers),
 		gqueryTreeChan: make(chan osc.Message),
+		notifyFailChan: make(chan osc.Message, 1),
 		statusChan:     make(chan osc.Message),
 		addr:           addr,
 		nextSynthID:    1000,
+		allocators:     newAllocators(ServerOptions{}),
 	}
-	if err := c.Connect(scsynth, timeout); err != nil {
-		return nil, err
-	}
-	return c, nil
 }
 
 var (
@@ -209,7 +265,7 @@ func (c *Client) Connect(addr string, timeout time.Duration) error {
 // DumpOSC sends a /dumpOSC message to scsynth
 // level should be DumpOff, DumpParsed, DumpContents, DumpAll
 func (c *Client) DumpOSC(level int32) error {
-	return c.oscConn.Send(osc.Message{
+	return c.send(osc.Message{
 		Address: dumpOscAddress,
 		Arguments: osc.Arguments{
 			osc.Int(level),
@@ -219,31 +275,102 @@ func (c *Client) DumpOSC(level int32) error {
 
 // FreeAll frees all nodes in a group
 func (c *Client) FreeAll(gids ...int32) error {
+	for _, gid := range gids {
+		c.forgetGroups(gid, false)
+	}
 	msg := osc.Message{
 		Address: groupFreeAllAddress,
 	}
 	for _, gid := range gids {
 		msg.Arguments = append(msg.Arguments, osc.Int(gid))
 	}
-	return c.oscConn.Send(msg)
+	return c.send(msg)
 }
 
 // Group creates a group.
 func (c *Client) Group(id, action, target int32) (*GroupNode, error) {
-	msg := osc.Message{
-		Address: groupNewAddress,
-		Arguments: osc.Arguments{
-			osc.Int(id),
-			osc.Int(action),
-			osc.Int(target),
-		},
-	}
-	if err := c.oscConn.Send(msg); err != nil {
+	if err := c.send(groupNewMsg(id, action, target)); err != nil {
 		return nil, err
 	}
+	c.addGroup(id, action, target, false)
+
 	return newGroup(c, id), nil
 }
 
+// ParGroup creates a new parallel group (supernova only).
+// The nodes in a parallel group may be evaluated in any order,
+// and supernova may evaluate them concurrently on several threads.
+func (c *Client) ParGroup(id, action, target int32) (*GroupNode, error) {
+	if err := c.send(parGroupNewMsg(id, action, target)); err != nil {
+		return nil, err
+	}
+	c.addGroup(id, action, target, true)
+
+	g := newGroup(c, id)
+	g.parallel = true
+	return g, nil
+}
+
+// clientGroup is a group that was created by a client.
+type clientGroup struct {
+	parent   int32 // parent is the ID of the parent group, or -1 if it is not known
+	parallel bool  // parallel says whether the group was created with ParGroup
+}
+
+// addGroup remembers a group that was created with /g_new or /p_new.
+// The parent is only known if the group was added to a group,
+// or was placed next to a group that was created by the client.
+func (c *Client) addGroup(id, action, target int32, parallel bool) {
+	c.groupsMu.Lock()
+	defer c.groupsMu.Unlock()
+
+	if c.groups == nil {
+		c.groups = map[int32]clientGroup{}
+	}
+	parent := target
+	if action != AddToHead && action != AddToTail {
+		parent = -1
+		if g, ok := c.groups[target]; ok {
+			parent = g.parent
+		}
+	}
+	if action == AddReplace {
+		c.forgetGroupsLocked(target, true)
+	}
+	c.groups[id] = clientGroup{parent: parent, parallel: parallel}
+}
+
+// forgetGroups forgets the groups inside the group with the provided ID,
+// and the group itself if self is true.
+// It is called when nodes are freed, so the groups of a long-running
+// client don't pile up. /g_deepFree only frees synths, so it doesn't call it.
+func (c *Client) forgetGroups(id int32, self bool) {
+	c.groupsMu.Lock()
+	defer c.groupsMu.Unlock()
+
+	c.forgetGroupsLocked(id, self)
+}
+
+// forgetGroupsLocked is forgetGroups for callers that hold groupsMu.
+func (c *Client) forgetGroupsLocked(id int32, self bool) {
+	if self {
+		delete(c.groups, id)
+	}
+	for gid, g := range c.groups {
+		if g.parent == id {
+			delete(c.groups, gid) // Delete before recursing so reused IDs can't loop.
+			c.forgetGroupsLocked(gid, false)
+		}
+	}
+}
+
+// isParGroup returns true if the client created a group with ParGroup.
+func (c *Client) isParGroup(id int32) bool {
+	c.groupsMu.Lock()
+	defer c.groupsMu.Unlock()
+	return c.groups[id].parallel
+}
+
 // NextSynthID gets the next available ID for creating a synth
 func (c *Client) NextSynthID() int32 {
 	return atomic.AddInt32(&c.nextSynthID, 1)
@@ -252,10 +379,9 @@ func (c *Client) NextSynthID() int32 {
 // NodeFree stops a node abruptly, removes it from its group, and frees its memory.
 // Using this method can cause a click if the node is not silent at the time it is freed.
 func (c *Client) NodeFree(id int32) error {
-	return c.oscConn.Send(osc.Message{
-		Address:   nodeFreeAddress,
-		Arguments: osc.Arguments{osc.Int(id)},
-	})
+	c.forgetGroups(id, true)
+
+	return c.send(nodeFreeMsg(id))
 }
 
 // NodeMap causes controls of a node to be read from a control bus.
@@ -272,7 +398,7 @@ func (c *Client) NodeMap(id int32, m map[string]int32) error {
 		msg.Arguments = append(msg.Arguments, osc.String(k))
 		msg.Arguments = append(msg.Arguments, osc.Int(v))
 	}
-	return c.oscConn.Send(msg)
+	return c.send(msg)
 }
 
 // NodeMapa causes controls of a node to be read from an audio bus.
@@ -289,27 +415,44 @@ func (c *Client) NodeMapa(id int32, m map[string]int32) error {
 		msg.Arguments = append(msg.Arguments, osc.String(k))
 		msg.Arguments = append(msg.Arguments, osc.Int(v))
 	}
-	return c.oscConn.Send(msg)
+	return c.send(msg)
+}
+
+// NodeRun turns a node on or off.
+// A node that is off is not evaluated until it is turned on again.
+func (c *Client) NodeRun(id int32, run bool) error {
+	flag := int32(0)
+	if run {
+		flag = 1
+	}
+	return c.send(osc.Message{
+		Address:   nodeRunAddress,
+		Arguments: osc.Arguments{osc.Int(id), osc.Int(flag)},
+	})
 }
 
 // NodeSet sets a control value on a node.
 func (c *Client) NodeSet(id int32, ctls map[string]float32) error {
+	return c.send(nodeSetMsg(id, ctls))
+}
+
+// PluginCommand sends a /cmd message to scsynth.
+// Plugin commands are defined by ugen plugins and are used
+// to communicate with a plugin without addressing a particular synth.
+func (c *Client) PluginCommand(name string, args ...osc.Argument) error {
 	msg := osc.Message{
-		Address: nodeSetAddress,
+		Address: pluginCommandAddress,
 		Arguments: osc.Arguments{
-			osc.Int(id),
+			osc.String(name),
 		},
 	}
-	for k, v := range ctls {
-		msg.Arguments = append(msg.Arguments, osc.String(k))
-		msg.Arguments = append(msg.Arguments, osc.Float(v))
-	}
-	return c.oscConn.Send(msg)
+	msg.Arguments = append(msg.Arguments, args...)
+	return c.send(msg)
 }
 
 // QueryGroup g_queryTree for a particular group.
 func (c *Client) QueryGroup(id int32) (*GroupNode, error) {
-	if err := c.oscConn.Send(osc.Message{
+	if err := c.send(osc.Message{
 		Address: groupQueryTreeAddress,
 		Arguments: osc.Arguments{
 			osc.Int(id),
@@ -359,17 +502,18 @@ func (c *Client) SendAllDefs() error {
 // This method blocks until a /done message is received
 // indicating that the synthdef was loaded
 func (c *Client) SendDef(def *Synthdef) error {
-	db, err := def.Bytes()
+	return c.SendDefs(def)
+}
+
+// SendDefs sends several synthdefs to scsynth in one /d_recv message.
+// This method blocks until a /done message is received
+// indicating that the synthdefs were loaded.
+func (c *Client) SendDefs(defs ...*Synthdef) error {
+	msg, err := synthdefReceiveMsg(defs...)
 	if err != nil {
 		return err
 	}
-	msg := osc.Message{
-		Address: synthdefReceiveAddress,
-		Arguments: osc.Arguments{
-			osc.Blob(db),
-		},
-	}
-	if err := c.oscConn.Send(msg); err != nil {
+	if err := c.send(msg); err != nil {
 		return err
 	}
 	var done osc.Message
@@ -402,7 +546,7 @@ func (c *Client) Status(timeout time.Duration) (*ServerStatus, error) {
 	statusReq := osc.Message{
 		Address: statusAddress,
 	}
-	if err := c.oscConn.Send(statusReq); err != nil {
+	if err := c.send(statusReq); err != nil {
 		return nil, err
 	}
 
@@ -420,22 +564,7 @@ func (c *Client) Status(timeout time.Duration) (*ServerStatus, error) {
 
 // Synth creates a synth node.
 func (c *Client) Synth(defName string, id, action, target int32, ctls map[string]float32) (*Synth, error) {
-	msg := osc.Message{
-		Address: synthNewAddress,
-		Arguments: osc.Arguments{
-			osc.String(defName),
-			osc.Int(id),
-			osc.Int(action),
-			osc.Int(target),
-		},
-	}
-	if ctls != nil {
-		for k, v := range ctls {
-			msg.Arguments = append(msg.Arguments, osc.String(k))
-			msg.Arguments = append(msg.Arguments, osc.Float(v))
-		}
-	}
-	if err := c.oscConn.Send(msg); err != nil {
+	if err := c.send(synthNewMsg(defName, id, action, target, ctls)); err != nil {
 		return nil, err
 	}
 	return newSynth(c, defName, id), nil
@@ -456,27 +585,39 @@ func (c *Client) Synths(args []SynthArgs) error {
 		Packets: make([]osc.Packet, len(args)),
 	}
 	for i, arg := range args {
-		msg := osc.Message{
-			Address: synthNewAddress,
-			Arguments: osc.Arguments{
-				osc.String(arg.DefName),
-				osc.Int(arg.ID),
-				osc.Int(arg.Action),
-				osc.Int(arg.Target),
-			},
+		bun.Packets[i] = synthNewMsg(arg.DefName, arg.ID, arg.Action, arg.Target, arg.Ctls)
+	}
+	return c.send(bun)
+}
+
+// send sends an OSC packet to scsynth.
+// Every packet sent by the client should go through this method.
+// If batching is enabled messages are added to the current batch.
+func (c *Client) send(p osc.Packet) error {
+	if b := c.getBatch(); b != nil {
+		if msg, ok := p.(osc.Message); ok {
+			return c.enqueue(b, msg)
 		}
-		for k, v := range arg.Ctls {
-			msg.Arguments = append(msg.Arguments, osc.String(k))
-			msg.Arguments = append(msg.Arguments, osc.Float(v))
+		// Flush pending messages first so that order is preserved.
+		if err := c.Flush(); err != nil {
+			return err
 		}
-		bun.Packets[i] = msg
 	}
-	return c.oscConn.Send(bun)
+	return c.sendNow(p)
+}
+
+// sendNow sends an OSC packet to scsynth immediately.
+func (c *Client) sendNow(p osc.Packet) error {
+	if err := c.oscConn.Send(p); err != nil {
+		return err
+	}
+	c.record(TrafficOut, p)
+	return nil
 }
 
 // addOscHandlers adds OSC handlers
 func (c *Client) oscHandlers() osc.Dispatcher {
-	return map[string]osc.MessageHandler{
+	handlers := map[string]osc.MessageHandler{
 		bufferInfoAddress: osc.Method(func(msg osc.Message) error {
 			c.bufferInfoChan <- msg
 			return nil
@@ -489,11 +630,36 @@ func (c *Client) oscHandlers() osc.Dispatcher {
 			c.doneChan <- msg
 			return nil
 		}),
+		failAddress: osc.Method(func(msg osc.Message) error {
+			if len(msg.Arguments) == 0 {
+				return nil
+			}
+			if addr, err := msg.Arguments[0].ReadString(); err == nil && addr == notifyAddress {
+				select {
+				case c.notifyFailChan <- msg:
+				default:
+				}
+			}
+			return nil
+		}),
 		groupQueryTreeReplyAddress: osc.Method(func(msg osc.Message) error {
 			c.gqueryTreeChan <- msg
 			return nil
 		}),
 	}
+	// Notifications from scsynth are only handled so that they can be recorded.
+	for _, addr := range notificationAddresses {
+		if _, ok := handlers[addr]; ok {
+			continue
+		}
+		handlers[addr] = osc.Method(func(msg osc.Message) error {
+			return nil
+		})
+	}
+	for addr, h := range handlers {
+		handlers[addr] = c.recordingHandler(h)
+	}
+	return handlers
 }
 
 // PlayDef plays a synthdef by sending the synthdef using
@@ -1,6 +1,8 @@
 package sc
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/scgolang/osc"
 )
@@ -47,6 +49,33 @@ func (c *Client) awaitBufAllocReply(buf *Buffer) error {
 	return nil
 }
 
+// awaitDone waits for a /done reply to a buffer command.
+func (c *Client) awaitDone(address string, bufnum int32) error {
+	var done osc.Message
+	select {
+	case done = <-c.doneChan:
+	case err := <-c.errChan:
+		return err
+	case <-time.After(DefaultDoneTimeout):
+		return errors.Wrapf(ErrTimeout, "waiting for /done %s", address)
+	}
+	if numargs := len(done.Arguments); numargs != 2 {
+		return errors.Errorf("expected two arguments to /done message, got %d", numargs)
+	}
+	addr, err := done.Arguments[0].ReadString()
+	if err != nil {
+		return err
+	}
+	num, err := done.Arguments[1].ReadInt32()
+	if err != nil {
+		return err
+	}
+	if addr != address || num != bufnum {
+		return errors.Errorf("expected /done %s %d, got /done %s %d", address, bufnum, addr, num)
+	}
+	return nil
+}
+
 // awaitBufReadReply waits for a reply to /b_allocRead
 func (c *Client) awaitBufReadReply(buf *Buffer) error {
 	var done osc.Message
@@ -79,7 +108,7 @@ func (c *Client) awaitBufReadReply(buf *Buffer) error {
 
 // QueryBuffer gets information about a buffer from scsynth.
 func (c *Client) QueryBuffer(num int32) (*Buffer, error) {
-	if err := c.oscConn.Send(osc.Message{
+	if err := c.send(osc.Message{
 		Address: bufferQueryAddress,
 		Arguments: osc.Arguments{
 			osc.Int(num),
@@ -127,6 +156,9 @@ func (c *Client) awaitBufInfoReply() (*Buffer, error) {
 }
 
 // ReadBuffer tells the server to read an audio file and load it into a buffer.
+// num is reserved so that AllocBuffer doesn't use it, and ReadBuffer returns
+// an error if it is already in use. If the file has already been read then
+// the existing buffer is returned.
 func (c *Client) ReadBuffer(path string, num int32, channels ...int) (*Buffer, error) {
 	buf, err := c.sendBufReadMsg(path, num, channels...)
 	if err != nil {
@@ -140,16 +172,13 @@ func (c *Client) ReadBuffer(path string, num int32, channels ...int) (*Buffer, e
 
 // sendBufAllocMsg sends a /b_alloc message
 func (c *Client) sendBufAllocMsg(frames, channels int) (*Buffer, error) {
-	buf := &Buffer{client: c}
-	msg := osc.Message{
-		Address: bufferAllocAddress,
-		Arguments: osc.Arguments{
-			osc.Int(buf.Num),
-			osc.Int(int32(frames)),
-			osc.Int(int32(channels)),
-		},
+	num, err := c.AllocBufferNum()
+	if err != nil {
+		return nil, err
 	}
-	if err := c.oscConn.Send(msg); err != nil {
+	buf := &Buffer{Num: num, client: c}
+	if err := c.send(bufferAllocMsg(num, frames, channels)); err != nil {
+		_ = c.FreeBufferNum(num) // Best effort.
 		return nil, err
 	}
 	return buf, nil
@@ -157,25 +186,15 @@ func (c *Client) sendBufAllocMsg(frames, channels int) (*Buffer, error) {
 
 // sendBufReadMsg sends a /b_allocRead command.
 func (c *Client) sendBufReadMsg(path string, num int32, channels ...int) (*Buffer, error) {
-	buf := newReadBuffer(path, num, c)
-
-	var addr string
-	if len(channels) == 0 {
-		addr = bufferReadAddress
-	} else {
-		addr = bufferReadChannelAddress
-	}
-	msg := osc.Message{
-		Address: addr,
-		Arguments: osc.Arguments{
-			osc.Int(buf.Num),
-			osc.String(path),
-		},
-	}
-	for _, channel := range channels {
-		msg.Arguments = append(msg.Arguments, osc.Int(channel))
+	buf, isNew, err := newReadBuffer(path, num, c)
+	if err != nil {
+		return nil, err
 	}
-	if err := c.oscConn.Send(msg); err != nil {
+	if err := c.send(bufferReadMsg(path, buf.Num, channels...)); err != nil {
+		if isNew {
+			forgetReadBuffer(buf)
+			_ = c.FreeBufferNum(buf.Num) // Best effort.
+		}
 		return nil, err
 	}
 	return buf, nil
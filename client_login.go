@@ -0,0 +1,161 @@
+package sc
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+// Login errors.
+var (
+	// ErrTooManyLogins happens when scsynth already has
+	// as many clients as it was started with (see ServerOptions.MaxLogins).
+	ErrTooManyLogins = errors.New("scsynth refused the login: too many users")
+
+	// ErrPasswordRejected happens when scsynth closes a TCP connection
+	// that was opened with a password before replying to the login.
+	ErrPasswordRejected = errors.New("scsynth closed the connection: the password was rejected")
+
+	// ErrConnectionClosed happens when scsynth closes a TCP connection
+	// before replying to the login.
+	ErrConnectionClosed = errors.New("scsynth closed the connection")
+)
+
+// Login is the result of logging in to scsynth.
+type Login struct {
+	// ClientID is the ID scsynth assigned to the client.
+	// Clients that share a server should use it to partition node IDs.
+	ClientID int32
+
+	// MaxLogins is the maximum number of clients scsynth accepts.
+	MaxLogins int32
+}
+
+// Login registers the client with scsynth using /notify.
+// scsynth replies with the client's ID and the maximum number of logins,
+// and sends notifications (e.g. /n_go and /n_end) to the client until Logout is called.
+// It returns ErrTooManyLogins if the server refuses the client,
+// and ErrPasswordRejected if the client connected with a password
+// that scsynth did not accept.
+func (c *Client) Login(timeout time.Duration) (Login, error) {
+	if err := c.send(osc.Message{
+		Address: notifyAddress,
+		Arguments: osc.Arguments{
+			osc.Int(1),
+		},
+	}); err != nil {
+		return Login{}, c.loginError(err)
+	}
+	done, err := c.awaitNotify(timeout)
+	if err != nil {
+		return Login{}, err
+	}
+	var login Login
+
+	// Older versions of scsynth don't reply with a client ID or max logins.
+	if len(done.Arguments) > 1 {
+		if login.ClientID, err = done.Arguments[1].ReadInt32(); err != nil {
+			return Login{}, errors.Wrap(err, "reading client ID")
+		}
+	}
+	if len(done.Arguments) > 2 {
+		if login.MaxLogins, err = done.Arguments[2].ReadInt32(); err != nil {
+			return Login{}, errors.Wrap(err, "reading max logins")
+		}
+	}
+	c.loginMu.Lock()
+	c.login = &login
+	c.loginMu.Unlock()
+
+	return login, nil
+}
+
+// Logout stops scsynth from sending notifications to the client.
+func (c *Client) Logout(timeout time.Duration) error {
+	if err := c.send(osc.Message{
+		Address: notifyAddress,
+		Arguments: osc.Arguments{
+			osc.Int(0),
+		},
+	}); err != nil {
+		return c.loginError(err)
+	}
+	if _, err := c.awaitNotify(timeout); err != nil {
+		return err
+	}
+	c.loginMu.Lock()
+	c.login = nil
+	c.loginMu.Unlock()
+
+	return nil
+}
+
+// ClientID returns the ID that scsynth assigned to the client
+// and whether the client is logged in.
+func (c *Client) ClientID() (int32, bool) {
+	c.loginMu.RLock()
+	defer c.loginMu.RUnlock()
+
+	if c.login == nil {
+		return 0, false
+	}
+	return c.login.ClientID, true
+}
+
+// awaitNotify waits for scsynth to reply to /notify.
+func (c *Client) awaitNotify(timeout time.Duration) (osc.Message, error) {
+	var closed <-chan struct{}
+	if c.tcp != nil {
+		closed = c.tcp.closed
+	}
+	select {
+	case done := <-c.doneChan:
+		if len(done.Arguments) == 0 {
+			return osc.Message{}, errors.New("expected arguments to /done message")
+		}
+		addr, err := done.Arguments[0].ReadString()
+		if err != nil {
+			return osc.Message{}, err
+		}
+		if addr != notifyAddress {
+			return osc.Message{}, errors.Errorf("expected /done %s, got /done %s", notifyAddress, addr)
+		}
+		return done, nil
+	case fail := <-c.notifyFailChan:
+		return osc.Message{}, notifyFailure(fail)
+	case <-closed:
+		return osc.Message{}, c.loginError(c.tcp.err)
+	case <-time.After(timeout):
+		return osc.Message{}, errors.Wrapf(ErrTimeout, "waiting for /done %s", notifyAddress)
+	}
+}
+
+// loginError returns the error for a failed login
+// when the connection to scsynth failed with err.
+func (c *Client) loginError(err error) error {
+	if c.tcp == nil {
+		return err
+	}
+	select {
+	case <-c.tcp.closed:
+	default:
+		return err
+	}
+	if c.tcp.password {
+		return ErrPasswordRejected
+	}
+	return ErrConnectionClosed
+}
+
+// notifyFailure returns the error for a /fail /notify message.
+func notifyFailure(fail osc.Message) error {
+	var reason string
+	if len(fail.Arguments) > 1 {
+		reason, _ = fail.Arguments[1].ReadString()
+	}
+	if reason == "too many users" {
+		return ErrTooManyLogins
+	}
+	return errors.Errorf("scsynth refused the login: %s", reason)
+}
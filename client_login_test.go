@@ -0,0 +1,134 @@
+package sc
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestLogin(t *testing.T) {
+	fs := newFakeServer(t)
+
+	fs.SetMaxLogins(1)
+
+	c1, err := NewClient("udp", "127.0.0.1:0", fs.Addr(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c1.Close() }() // Best effort.
+
+	c2, err := NewClient("udp", "127.0.0.1:0", fs.Addr(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c2.Close() }() // Best effort.
+
+	if _, ok := c1.ClientID(); ok {
+		t.Fatal("expected client to not be logged in")
+	}
+	login, err := c1.Login(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := (Login{ClientID: 0, MaxLogins: 1}), login; expected != got {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+	if _, ok := c1.ClientID(); !ok {
+		t.Fatal("expected client to be logged in")
+	}
+	if _, err := c2.Login(time.Second); err != ErrTooManyLogins {
+		t.Fatalf("expected %v, got %v", ErrTooManyLogins, err)
+	}
+	if err := c1.Logout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c1.ClientID(); ok {
+		t.Fatal("expected client to be logged out")
+	}
+	if _, err := c2.Login(time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTCPLogin(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }() // Best effort.
+
+	go servePasswordLogins(ln, "secret")
+
+	for _, testcase := range []struct {
+		password string
+		err      error
+	}{
+		{"secret", nil},
+		{"wrong", ErrPasswordRejected},
+	} {
+		c, err := NewTCPClient(ln.Addr().String(), testcase.password, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		login, err := c.Login(time.Second)
+		if expected, got := testcase.err, err; expected != got {
+			t.Fatalf("password %s: expected error %v, got %v", testcase.password, expected, got)
+		}
+		if err == nil {
+			if expected, got := (Login{ClientID: 3, MaxLogins: 8}), login; expected != got {
+				t.Fatalf("expected %+v, got %+v", expected, got)
+			}
+		}
+		_ = c.Close() // Best effort.
+	}
+}
+
+// servePasswordLogins accepts TCP connections like scsynth does when it is
+// started with a password, and replies to the first message.
+// /status gets a /status.reply and anything else is treated as /notify.
+func servePasswordLogins(ln net.Listener, password string) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer func() { _ = conn.Close() }() // Best effort.
+
+			var size int32
+			if err := binary.Read(conn, byteOrder, &size); err != nil {
+				return
+			}
+			data := make([]byte, size)
+			if _, err := io.ReadFull(conn, data); err != nil {
+				return
+			}
+			if string(data) != string(osc.ToBytes(password)) {
+				return
+			}
+			msg, err := readOSC(conn, "tcp")
+			if err != nil {
+				return
+			}
+			if msg.Address == statusAddress {
+				_ = writeOSC(conn, "tcp", osc.Message{Address: statusReplyAddress})
+				return
+			}
+			_ = writeOSC(conn, "tcp", osc.Message{
+				Address: doneOscAddress,
+				Arguments: osc.Arguments{
+					osc.String(notifyAddress),
+					osc.Int(3),
+					osc.Int(8),
+				},
+			})
+			// Wait for the client to close the connection.
+			_, _ = io.Copy(ioutil.Discard, conn)
+		}(conn)
+	}
+}
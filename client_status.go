@@ -0,0 +1,96 @@
+package sc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// WatchStatus polls scsynth for its status every interval until ctx is done.
+// Every successful reply is sent on the returned channel as a StatusSnapshot.
+// Requests that fail or time out are skipped and counted as poll errors.
+// The channel is closed when ctx is done or the client is closed.
+func (c *Client) WatchStatus(ctx context.Context, interval time.Duration) <-chan StatusSnapshot {
+	var (
+		snapshots = make(chan StatusSnapshot)
+		window    = newStatusWindow(statusWindowSize)
+	)
+	go func() {
+		defer close(snapshots)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if c.isClosed() {
+				return
+			}
+			status, err := c.Status(interval)
+			if err != nil {
+				atomic.AddUint64(&c.statusErrors, 1)
+			} else if status == nil {
+				return // The client was closed while we were waiting.
+			} else {
+				snap := window.add(*status, time.Now())
+				c.setLastStatus(snap)
+
+				select {
+				case snapshots <- snap:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return snapshots
+}
+
+// LastStatus returns the most recent snapshot collected by WatchStatus.
+// The second return value is false if no snapshot has been collected yet.
+func (c *Client) LastStatus() (StatusSnapshot, bool) {
+	c.statusMu.RLock()
+	defer c.statusMu.RUnlock()
+	if c.lastStatus == nil {
+		return StatusSnapshot{}, false
+	}
+	return *c.lastStatus, true
+}
+
+// setLastStatus sets the most recent status snapshot.
+func (c *Client) setLastStatus(snap StatusSnapshot) {
+	c.statusMu.Lock()
+	c.lastStatus = &snap
+	c.statusMu.Unlock()
+}
+
+// StatusHandler returns an http.Handler that serves the most recent
+// snapshot collected by WatchStatus in the Prometheus text exposition format.
+// scsynth_up is 0 until the first snapshot has been collected.
+func (c *Client) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", prometheusContentType)
+
+		snap, ok := c.LastStatus()
+		up := 0
+		if ok {
+			up = 1
+		}
+		fmt.Fprintf(w, "# HELP scsynth_up Whether a status reply has been received from scsynth.\n")
+		fmt.Fprintf(w, "# TYPE scsynth_up gauge\n")
+		fmt.Fprintf(w, "scsynth_up %d\n", up)
+		fmt.Fprintf(w, "# HELP scsynth_status_poll_errors_total Number of status requests that failed or timed out.\n")
+		fmt.Fprintf(w, "# TYPE scsynth_status_poll_errors_total counter\n")
+		fmt.Fprintf(w, "scsynth_status_poll_errors_total %d\n", atomic.LoadUint64(&c.statusErrors))
+		if !ok {
+			return
+		}
+		_ = snap.WritePrometheus(w) // Best effort.
+	})
+}
@@ -0,0 +1,158 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+// NewTCPClient creates a new SuperCollider client that
+// connects to scsynth via TCP.
+// If password is not empty it is used to authenticate with
+// an scsynth that was started with a password (see ServerOptions).
+// Use Login to find out if scsynth accepted the connection.
+func NewTCPClient(scsynth, password string, timeout time.Duration) (*Client, error) {
+	c := newClient(nil)
+	if err := c.ConnectTCP(scsynth, password, timeout); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ConnectTCP connects to an scsynth instance via TCP.
+// If password is not empty it is sent before any other packet.
+// scsynth closes the connection if the password is wrong,
+// which Login reports as ErrPasswordRejected.
+func (c *Client) ConnectTCP(addr, password string, timeout time.Duration) error {
+	var (
+		conn  net.Conn
+		err   error
+		start = time.Now()
+	)
+	for time.Now().Sub(start) < timeout {
+		conn, err = net.DialTimeout("tcp", addr, timeout)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if conn == nil {
+		return errors.New("connection timeout")
+	}
+	tc := newTCPConn(conn)
+
+	if password != "" {
+		tc.password = true
+		if err := tc.write(osc.ToBytes(password)); err != nil {
+			_ = conn.Close() // Best effort.
+			return err
+		}
+	}
+	c.oscConn = tc
+	c.tcp = tc
+
+	go func() {
+		_ = tc.Serve(8, c.oscHandlers()) // The error is saved in tc.
+	}()
+	return nil
+}
+
+// tcpConn is an OSC connection to scsynth over TCP.
+// Every packet is prefixed with its size.
+type tcpConn struct {
+	net.Conn
+
+	password bool // password says whether a password was sent
+
+	writeMu sync.Mutex
+
+	closed chan struct{} // closed is closed when the connection can't be read any more
+	err    error         // err is the error that closed the connection
+}
+
+// newTCPConn creates a new TCP connection.
+func newTCPConn(conn net.Conn) *tcpConn {
+	return &tcpConn{
+		Conn:   conn,
+		closed: make(chan struct{}),
+	}
+}
+
+// Send sends an OSC packet.
+func (tc *tcpConn) Send(p osc.Packet) error {
+	return tc.write(p.Bytes())
+}
+
+// Serve reads OSC packets and dispatches them until the connection is closed.
+func (tc *tcpConn) Serve(numWorkers int, dispatcher osc.Dispatcher) error {
+	tc.err = tc.serve(dispatcher)
+	close(tc.closed)
+	return tc.err
+}
+
+// serve reads OSC packets and dispatches them.
+// Messages are dispatched in the order they are received,
+// so numWorkers is not used.
+func (tc *tcpConn) serve(dispatcher osc.Dispatcher) error {
+	for {
+		var size int32
+		if err := binary.Read(tc.Conn, byteOrder, &size); err != nil {
+			return err
+		}
+		if size < 0 {
+			return errors.Errorf("invalid packet size %d", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(tc.Conn, data); err != nil {
+			return err
+		}
+		msgs, err := tc.parse(data)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			h, ok := dispatcher[msg.Address]
+			if !ok {
+				continue
+			}
+			if err := h.Handle(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parse parses the messages in an OSC packet.
+func (tc *tcpConn) parse(data []byte) ([]osc.Message, error) {
+	if bytes.HasPrefix(data, osc.ToBytes("#bundle")) {
+		bun, err := parseScoreBundle(data)
+		if err != nil {
+			return nil, err
+		}
+		return bun.Messages, nil
+	}
+	msg, err := osc.ParseMessage(data, tc.RemoteAddr())
+	if err != nil {
+		return nil, err
+	}
+	return []osc.Message{msg}, nil
+}
+
+// write writes a size-prefixed packet.
+func (tc *tcpConn) write(data []byte) error {
+	tc.writeMu.Lock()
+	defer tc.writeMu.Unlock()
+
+	buf := make([]byte, 4+len(data))
+	byteOrder.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+
+	_, err := tc.Conn.Write(buf)
+	return err
+}
@@ -0,0 +1,32 @@
+package sc
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+// TestTCPConnShortBundle checks that a bundle without a time tag
+// closes the connection with an error instead of panicking.
+func TestTCPConnShortBundle(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }() // Best effort.
+
+	tc := newTCPConn(client)
+	go func() {
+		_ = tc.Serve(1, osc.Dispatcher{})
+	}()
+	go func() {
+		_ = newTCPConn(server).write(osc.ToBytes("#bundle")) // Best effort.
+	}()
+	select {
+	case <-tc.closed:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the connection to close")
+	}
+	if tc.err == nil {
+		t.Fatal("expected an error for a short bundle")
+	}
+}
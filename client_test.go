@@ -5,6 +5,8 @@ import (
 	"path"
 	"testing"
 	"time"
+
+	"github.com/scgolang/osc"
 )
 
 // skipIfNoScsynth skips a test if scsynth is not running.
@@ -51,3 +53,13 @@ func TestClient(t *testing.T) {
 		t.Fatalf("got nil buffer")
 	}
 }
+
+func TestPluginCommand(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	if err := client.PluginCommand("pluginStatus", osc.String("verbose"), osc.Int(1)); err != nil {
+		t.Fatal(err)
+	}
+	msg := waitReceived(t, fs, pluginCommandAddress)
+	checkArguments(t, msg, osc.String("pluginStatus"), osc.String("verbose"), osc.Int(1))
+}
@@ -0,0 +1,32 @@
+package sc
+
+// DiskOut streams audio to a sound file.
+// The buffer must have been opened for writing with Buffer.Write
+// and leaveOpen set to true, and its number of frames must be
+// a power of two that is at least twice the server's block size.
+type DiskOut struct {
+	// BufNum is the buffer to write to.
+	BufNum Input
+
+	// Channels are the signals to write.
+	// The buffer must have the same number of channels.
+	Channels Input
+}
+
+// Rate creates a new ugen at a specific rate.
+// DiskOut only supports AR, any other rate will cause a runtime panic.
+// There will also be a runtime panic if BufNum or Channels is nil.
+func (d DiskOut) Rate(rate int8) Ugen {
+	if rate != AR {
+		panic("DiskOut only supports AR")
+	}
+	if d.BufNum == nil {
+		panic("DiskOut requires a BufNum")
+	}
+	if d.Channels == nil {
+		panic("DiskOut requires Channels")
+	}
+	// DiskOut's output is the number of frames written, so it needs an output
+	// even though it is usually the root of a synthdef.
+	return *asOutput(NewUgen("DiskOut", rate, 0, 1, d.BufNum, d.Channels))
+}
@@ -0,0 +1,18 @@
+package sc
+
+import (
+	"testing"
+)
+
+func TestDiskOut(t *testing.T) {
+	var (
+		in  = In{NumChannels: 2, Bus: C(0)}.Rate(AR)
+		out = DiskOut{BufNum: C(1), Channels: in}.Rate(AR)
+	)
+	if expected, got := "DiskOut", out.Name; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := 2, len(out.inputs); expected != got {
+		t.Fatalf("expected %d inputs, got %d", expected, got)
+	}
+}
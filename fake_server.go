@@ -0,0 +1,348 @@
+package sc
+
+import (
+	"bytes"
+	"net"
+	"sync"
+
+	"github.com/scgolang/osc"
+)
+
+// FakeServer is an in-process stand-in for scsynth.
+// It replies to the subset of the server command reference that
+// Client depends on, so programs that use Client can be tested
+// without running scsynth.
+// It does not generate any sound.
+type FakeServer struct {
+	conn *osc.UDPConn
+
+	mu        sync.Mutex
+	received  []osc.Message
+	buffers   map[int32][2]int32 // buffer number -> frames, channels
+	nodes     map[int32]bool     // node id -> is a group
+	synthdefs int32
+	logins    map[string]int32 // client address -> client ID
+	maxLogins int32
+	avgCPU    float32
+	peakCPU   float32
+}
+
+// NewFakeServer creates a fake server listening on a local UDP address.
+// Use "127.0.0.1:0" to listen on a free port.
+func NewFakeServer(addr string) (*FakeServer, error) {
+	laddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := osc.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, err
+	}
+	fs := &FakeServer{
+		conn:      conn,
+		buffers:   map[int32][2]int32{},
+		nodes:     map[int32]bool{RootNodeID: true},
+		logins:    map[string]int32{},
+		maxLogins: DefaultMaxLogins,
+	}
+	go func() {
+		_ = conn.Serve(1, fs.handlers()) // Returns an error when the server is closed.
+	}()
+	return fs, nil
+}
+
+// Addr returns the address the fake server is listening on.
+func (fs *FakeServer) Addr() string {
+	return fs.conn.LocalAddr().String()
+}
+
+// Close closes the fake server.
+func (fs *FakeServer) Close() error {
+	return fs.conn.Close()
+}
+
+// SetMaxLogins sets the maximum number of clients that can log in.
+func (fs *FakeServer) SetMaxLogins(n int32) {
+	fs.mu.Lock()
+	fs.maxLogins = n
+	fs.mu.Unlock()
+}
+
+// SetCPU sets the CPU usage reported in /status.reply messages.
+func (fs *FakeServer) SetCPU(avg, peak float32) {
+	fs.mu.Lock()
+	fs.avgCPU, fs.peakCPU = avg, peak
+	fs.mu.Unlock()
+}
+
+// Received returns all the messages the fake server has received.
+func (fs *FakeServer) Received() []osc.Message {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]osc.Message{}, fs.received...)
+}
+
+// handlers returns the OSC handlers of the fake server.
+func (fs *FakeServer) handlers() osc.Dispatcher {
+	handlers := map[string]osc.MessageHandler{
+		bufferAllocAddress:       osc.Method(fs.bufferAlloc),
+		bufferCloseAddress:       osc.Method(fs.done),
+		bufferFreeAddress:        osc.Method(fs.bufferFree),
+		bufferWriteAddress:       osc.Method(fs.done),
+		bufferGenAddress:         osc.Method(fs.done),
+		bufferQueryAddress:       osc.Method(fs.bufferQuery),
+		bufferReadAddress:        osc.Method(fs.bufferAlloc),
+		bufferReadChannelAddress: osc.Method(fs.bufferAlloc),
+		groupQueryTreeAddress:    osc.Method(fs.groupQueryTree),
+		groupNewAddress:          osc.Method(fs.nodeNew),
+		parGroupNewAddress:       osc.Method(fs.nodeNew),
+		nodeFreeAddress:          osc.Method(fs.nodeFree),
+		notifyAddress:            osc.Method(fs.notify),
+		statusAddress:            osc.Method(fs.status),
+		synthNewAddress:          osc.Method(fs.nodeNew),
+		synthdefReceiveAddress:   osc.Method(fs.synthdefReceive),
+	}
+	// Other commands are only recorded.
+	for _, addr := range []string{
+		dumpOscAddress,
+		groupFreeAllAddress,
+		nodeMapAddress,
+		nodeMapaAddress,
+		nodeRunAddress,
+		nodeSetAddress,
+		pluginCommandAddress,
+		quitAddress,
+		ugenCommandAddress,
+	} {
+		handlers[addr] = osc.Method(func(msg osc.Message) error {
+			return nil
+		})
+	}
+	for addr, h := range handlers {
+		handlers[addr] = fs.receiving(h)
+	}
+	return handlers
+}
+
+// receiving wraps a handler so that every message it handles is saved.
+func (fs *FakeServer) receiving(h osc.MessageHandler) osc.MessageHandler {
+	return osc.Method(func(msg osc.Message) error {
+		fs.mu.Lock()
+		fs.received = append(fs.received, msg)
+		fs.mu.Unlock()
+		return h.Handle(msg)
+	})
+}
+
+// reply sends a message to the sender of another message.
+func (fs *FakeServer) reply(to osc.Message, address string, args ...osc.Argument) error {
+	return fs.conn.SendTo(to.Sender, osc.Message{
+		Address:   address,
+		Arguments: args,
+	})
+}
+
+// done replies with a /done message that contains the address
+// and the first argument of msg.
+func (fs *FakeServer) done(msg osc.Message) error {
+	args := osc.Arguments{osc.String(msg.Address)}
+	if len(msg.Arguments) > 0 {
+		args = append(args, msg.Arguments[0])
+	}
+	return fs.reply(msg, doneOscAddress, args...)
+}
+
+// bufferAlloc handles /b_alloc, /b_allocRead, and /b_allocReadChannel.
+func (fs *FakeServer) bufferAlloc(msg osc.Message) error {
+	if len(msg.Arguments) < 1 {
+		return nil
+	}
+	num, err := msg.Arguments[0].ReadInt32()
+	if err != nil {
+		return err
+	}
+	var frames, channels int32 = 0, 1
+	if msg.Address == bufferAllocAddress && len(msg.Arguments) >= 3 {
+		if frames, err = msg.Arguments[1].ReadInt32(); err != nil {
+			return err
+		}
+		if channels, err = msg.Arguments[2].ReadInt32(); err != nil {
+			return err
+		}
+	}
+	fs.mu.Lock()
+	fs.buffers[num] = [2]int32{frames, channels}
+	fs.mu.Unlock()
+
+	return fs.done(msg)
+}
+
+// bufferFree handles /b_free.
+func (fs *FakeServer) bufferFree(msg osc.Message) error {
+	if len(msg.Arguments) < 1 {
+		return nil
+	}
+	num, err := msg.Arguments[0].ReadInt32()
+	if err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	delete(fs.buffers, num)
+	fs.mu.Unlock()
+
+	return fs.done(msg)
+}
+
+// bufferQuery handles /b_query.
+func (fs *FakeServer) bufferQuery(msg osc.Message) error {
+	for _, arg := range msg.Arguments {
+		num, err := arg.ReadInt32()
+		if err != nil {
+			return err
+		}
+		fs.mu.Lock()
+		buf := fs.buffers[num]
+		fs.mu.Unlock()
+
+		if err := fs.reply(msg, bufferInfoAddress, osc.Int(num), osc.Int(buf[0]), osc.Int(buf[1]), osc.Float(48000)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupQueryTree handles /g_queryTree.
+// The reply always describes an empty group.
+func (fs *FakeServer) groupQueryTree(msg osc.Message) error {
+	if len(msg.Arguments) < 1 {
+		return nil
+	}
+	return fs.reply(msg, groupQueryTreeReplyAddress, osc.Int(1), msg.Arguments[0], osc.Int(0))
+}
+
+// nodeNew handles /s_new, /g_new, and /p_new.
+func (fs *FakeServer) nodeNew(msg osc.Message) error {
+	idx := 0
+	if msg.Address == synthNewAddress {
+		idx = 1
+	}
+	if len(msg.Arguments) <= idx {
+		return nil
+	}
+	id, err := msg.Arguments[idx].ReadInt32()
+	if err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	fs.nodes[id] = msg.Address != synthNewAddress
+	fs.mu.Unlock()
+	return nil
+}
+
+// nodeFree handles /n_free.
+func (fs *FakeServer) nodeFree(msg osc.Message) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for _, arg := range msg.Arguments {
+		id, err := arg.ReadInt32()
+		if err != nil {
+			return err
+		}
+		delete(fs.nodes, id)
+	}
+	return nil
+}
+
+// notify handles /notify.
+func (fs *FakeServer) notify(msg osc.Message) error {
+	if len(msg.Arguments) < 1 {
+		return nil
+	}
+	on, err := msg.Arguments[0].ReadInt32()
+	if err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	var (
+		sender       = msg.Sender.String()
+		id, loggedIn = fs.logins[sender]
+		maxLogins    = fs.maxLogins
+		full         = !loggedIn && int32(len(fs.logins)) >= maxLogins
+	)
+	switch {
+	case on == 0:
+		delete(fs.logins, sender)
+	case !loggedIn && !full:
+		id = fs.nextClientID()
+		fs.logins[sender] = id
+	}
+	fs.mu.Unlock()
+
+	if on != 0 && full {
+		return fs.reply(msg, failAddress, osc.String(notifyAddress), osc.String("too many users"))
+	}
+	return fs.reply(msg, doneOscAddress, osc.String(notifyAddress), osc.Int(id), osc.Int(maxLogins))
+}
+
+// nextClientID returns the lowest client ID that is not in use.
+// fs.mu must be held.
+func (fs *FakeServer) nextClientID() int32 {
+	used := map[int32]bool{}
+	for _, id := range fs.logins {
+		used[id] = true
+	}
+	id := int32(0)
+	for used[id] {
+		id++
+	}
+	return id
+}
+
+// status handles /status.
+func (fs *FakeServer) status(msg osc.Message) error {
+	fs.mu.Lock()
+	var numSynths, numGroups int32
+	for _, isGroup := range fs.nodes {
+		if isGroup {
+			numGroups++
+		} else {
+			numSynths++
+		}
+	}
+	var (
+		numSynthdefs    = fs.synthdefs
+		avgCPU, peakCPU = fs.avgCPU, fs.peakCPU
+	)
+	fs.mu.Unlock()
+
+	return fs.reply(msg, statusReplyAddress,
+		osc.Int(1),
+		osc.Int(0),
+		osc.Int(numSynths),
+		osc.Int(numGroups),
+		osc.Int(numSynthdefs),
+		osc.Float(avgCPU),
+		osc.Float(peakCPU),
+		osc.Float(48000),
+		osc.Float(48000),
+	)
+}
+
+// synthdefReceive handles /d_recv.
+// Every synthdef in the synthdef file counts towards the number of synthdefs.
+func (fs *FakeServer) synthdefReceive(msg osc.Message) error {
+	numDefs := int32(1)
+	if len(msg.Arguments) > 0 {
+		if data, err := msg.Arguments[0].ReadBlob(); err == nil {
+			if f, err := ReadSynthdefFile(bytes.NewReader(data)); err == nil {
+				numDefs = int32(len(f.Synthdefs))
+			}
+		}
+	}
+	fs.mu.Lock()
+	fs.synthdefs += numDefs
+	fs.mu.Unlock()
+
+	return fs.reply(msg, doneOscAddress, osc.String(synthdefReceiveAddress))
+}
@@ -0,0 +1,49 @@
+package sc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestFakeServer(t *testing.T) {
+	client, _ := newFakeClient(t)
+
+	var (
+		buf = &bytes.Buffer{}
+		tr  = NewTrafficRecorder(buf)
+	)
+	client.SetRecorder(tr)
+
+	if err := client.SendDef(NewSynthdef("sine_a", defSineA)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Synth("sine_a", client.NextSynthID(), AddToTail, RootNodeID, nil); err != nil {
+		t.Fatal(err)
+	}
+	status, err := client.Status(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(1), status.NumSynths; expected != got {
+		t.Fatalf("expected %d synths, got %d", expected, got)
+	}
+	if expected, got := int32(1), status.NumSynthdefs; expected != got {
+		t.Fatalf("expected %d synthdefs, got %d", expected, got)
+	}
+	client.SetRecorder(nil)
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := ReadTrafficSession(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 3, len(session.Filter(TrafficOut)); expected != got {
+		t.Fatalf("expected %d outgoing packets, got %d", expected, got)
+	}
+	if expected, got := 2, len(session.Filter(TrafficIn)); expected != got {
+		t.Fatalf("expected %d incoming packets, got %d", expected, got)
+	}
+}
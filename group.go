@@ -43,20 +43,20 @@ func (g *SynthNode) ID() int32 {
 type GroupNode struct {
 	Children []Node
 
-	client *Client
-	id     int32
+	client   *Client
+	id       int32
+	parallel bool
 }
 
-// Free frees all the nodes in a group.
-// TODO
+// Free frees the group and all the nodes in it.
 func (g *GroupNode) Free() error {
-	return nil
+	return g.client.NodeFree(g.id)
 }
 
 // FreeAll frees all the nodes in a group recursively.
-// TODO
+// The group itself is not freed.
 func (g *GroupNode) FreeAll() error {
-	return nil
+	return g.client.FreeAll(g.id)
 }
 
 // ID returns the node ID.
@@ -64,6 +64,24 @@ func (g *GroupNode) ID() int32 {
 	return g.id
 }
 
+// IsParGroup returns true if the group is a parallel group (see Client.ParGroup).
+// The replies to /g_queryTree do not say whether a group is parallel,
+// so groups returned by QueryGroup are only known to be parallel
+// if they were created by the same client.
+func (g *GroupNode) IsParGroup() bool {
+	return g.parallel
+}
+
+// Group adds a group to a group.
+func (g *GroupNode) Group(id, action int32) (*GroupNode, error) {
+	return g.client.Group(id, action, g.id)
+}
+
+// ParGroup adds a parallel group to a group (supernova only).
+func (g *GroupNode) ParGroup(id, action int32) (*GroupNode, error) {
+	return g.client.ParGroup(id, action, g.id)
+}
+
 // Synth adds a synth to a group
 func (g *GroupNode) Synth(defName string, id, action int32, ctls map[string]float32) (*Synth, error) {
 	return g.client.Synth(defName, id, action, g.id, ctls)
@@ -129,6 +147,7 @@ func (c *Client) parseNodeFrom(msg osc.Message, startIndex int) (Node, int, erro
 		Children: make([]Node, numChildren),
 		client:   c,
 		id:       nodeID,
+		parallel: c.isParGroup(nodeID),
 	}
 	startIndex = startIndex + 2
 
@@ -171,15 +190,18 @@ func (c *Client) parseSynthNodeFrom(msg osc.Message, nodeID int32, startIndex in
 		}
 		argsConsumed++
 
-		var cvstr string
-		cvflt, err := msg.Arguments[startIndex+argsConsumed].ReadFloat32()
-		if err == nil {
+		// Control values are floats, or strings like "c1" for controls that are mapped to a bus.
+		// supernova sends some values as ints.
+		var (
+			cvstr string
+			arg   = msg.Arguments[startIndex+argsConsumed]
+		)
+		if cvflt, err := arg.ReadFloat32(); err == nil {
 			cvstr = strconv.FormatFloat(float64(cvflt), 'f', -1, 32)
-		} else {
-			cvstr, err = msg.Arguments[startIndex+argsConsumed].ReadString()
-			if err != nil {
-				return nil, 0, errors.Wrap(err, "reading synth control value")
-			}
+		} else if cvint, err := arg.ReadInt32(); err == nil {
+			cvstr = strconv.FormatInt(int64(cvint), 10)
+		} else if cvstr, err = arg.ReadString(); err != nil {
+			return nil, 0, errors.Wrap(err, "reading synth control value")
 		}
 		argsConsumed++
 		sn.Controls[controlName] = cvstr
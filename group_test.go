@@ -0,0 +1,106 @@
+package sc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestParseGroup(t *testing.T) {
+	c := &Client{groups: map[int32]clientGroup{2000: {parent: 1, parallel: true}}}
+
+	// A reply from supernova for a group that contains a parallel group with one synth.
+	g, err := c.parseGroup(osc.Message{
+		Address: groupQueryTreeReplyAddress,
+		Arguments: osc.Arguments{
+			osc.Int(1), osc.Int(1),
+			osc.Int(2000), osc.Int(1),
+			osc.Int(1000), osc.Int(-1), osc.String("foo"), osc.Int(3),
+			osc.String("freq"), osc.Float(440),
+			osc.String("bus"), osc.Int(4),
+			osc.String("amp"), osc.String("c1"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.IsParGroup() {
+		t.Fatal("expected group 1 to not be a parallel group")
+	}
+	pg, ok := g.Children[0].(*GroupNode)
+	if !ok {
+		t.Fatalf("expected a *GroupNode, got %T", g.Children[0])
+	}
+	if !pg.IsParGroup() {
+		t.Fatal("expected group 2000 to be a parallel group")
+	}
+	sn, ok := pg.Children[0].(*SynthNode)
+	if !ok {
+		t.Fatalf("expected a *SynthNode, got %T", pg.Children[0])
+	}
+	for name, expected := range map[string]string{"freq": "440", "bus": "4", "amp": "c1"} {
+		if got := sn.Controls[name]; expected != got {
+			t.Fatalf("control %s: expected %s, got %s", name, expected, got)
+		}
+	}
+}
+
+func TestParGroup(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	pg, err := client.ParGroup(2000, AddToTail, RootNodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pg.IsParGroup() {
+		t.Fatal("expected a parallel group")
+	}
+	status, err := client.Status(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(2), status.NumGroups; expected != got {
+		t.Fatalf("expected %d groups, got %d", expected, got)
+	}
+	if expected, got := parGroupNewAddress, fs.Received()[0].Address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestForgetGroups(t *testing.T) {
+	client, _ := newFakeClient(t)
+
+	// 1000 contains 2000, which contains 2001, and 2002 is next to 1000.
+	g, err := client.Group(1000, AddToTail, RootNodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pg, err := g.ParGroup(2000, AddToHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pg.ParGroup(2001, AddToTail); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ParGroup(2002, AddAfter, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.FreeAll(); err != nil {
+		t.Fatal(err)
+	}
+	for id, expected := range map[int32]bool{1000: false, 2000: false, 2001: false, 2002: true} {
+		if got := client.isParGroup(id); expected != got {
+			t.Fatalf("group %d: expected parallel %t, got %t", id, expected, got)
+		}
+	}
+	if expected, got := 2, len(client.groups); expected != got {
+		t.Fatalf("expected %d groups, got %d", expected, got)
+	}
+	if err := client.NodeFree(RootNodeID); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 0, len(client.groups); expected != got {
+		t.Fatalf("expected %d groups, got %d", expected, got)
+	}
+}
@@ -21,14 +21,18 @@ func (in In) Rate(rate int8) Input {
 	CheckRate(rate)
 	(&in).defaults()
 
-	var (
-		uin = NewInput("In", rate, 0, in.NumChannels, in.Bus)
-		ins = make([]Input, in.NumChannels)
-	)
-	for i := range ins {
-		ins[i] = uin
+	// Every channel is a separate input, so that e.g. the channels
+	// of a stereo In are written to consecutive channels by Out.
+	var chans []Input
+	switch v := NewInput("In", rate, 0, in.NumChannels, in.Bus).(type) {
+	case *Ugen:
+		chans = v.channels()
+	case MultiInput:
+		for _, u := range v.InputArray() {
+			chans = append(chans, u.(*Ugen).channels()...)
+		}
 	}
-	return Multi(ins...)
+	return Multi(chans...)
 }
 
 func defIn(params Params) Ugen {
@@ -38,3 +38,27 @@ func TestIn(t *testing.T) {
 		t.Fatalf("synthdef different from sclang version")
 	}
 }
+
+func TestInChannels(t *testing.T) {
+	def := NewSynthdef("InChannels", func(params Params) Ugen {
+		return Out{
+			Bus:      C(0),
+			Channels: In{Bus: C(8), NumChannels: 2}.Rate(AR).Mul(C(0.5)),
+		}.Rate(AR)
+	})
+	if expected, got := 4, len(def.Ugens); expected != got {
+		t.Fatalf("expected %d ugens, got %d", expected, got)
+	}
+	if expected, got := 2, len(def.Ugens[0].Outputs); expected != got {
+		t.Fatalf("expected %d outputs, got %d", expected, got)
+	}
+	// Each BinaryOpUGen reads one channel of In.
+	for i, u := range def.Ugens[1:3] {
+		if expected, got := (UgenInput{UgenIndex: 0, OutputIndex: int32(i)}), u.Inputs[0]; expected != got {
+			t.Fatalf("ugen %d: expected input %v, got %v", i+1, expected, got)
+		}
+	}
+	if expected, got := 3, len(def.Ugens[3].Inputs); expected != got {
+		t.Fatalf("expected Out to have %d inputs, got %d", expected, got)
+	}
+}
@@ -1,9 +1,6 @@
 package sc
 
-import (
-	"encoding/binary"
-	"io"
-)
+import "io"
 
 // Input is implemented by any value that can serve as a
 // ugen input. This includes synthdef parameters,
@@ -246,12 +243,15 @@ type Input interface {
 	Wrap2(Input) Input
 }
 
-func readInput(r io.Reader) (UgenInput, error) {
-	var ui UgenInput
-	if err := binary.Read(r, byteOrder, &ui.UgenIndex); err != nil {
+func readInput(r io.Reader, version int32) (UgenInput, error) {
+	var (
+		ui  UgenInput
+		err error
+	)
+	if ui.UgenIndex, err = readSynthdefInt(r, version); err != nil {
 		return ui, err
 	}
-	if err := binary.Read(r, byteOrder, &ui.OutputIndex); err != nil {
+	if ui.OutputIndex, err = readSynthdefInt(r, version); err != nil {
 		return ui, err
 	}
 	return ui, nil
@@ -0,0 +1,128 @@
+package sc
+
+import (
+	"sort"
+
+	"github.com/scgolang/osc"
+)
+
+// The functions in this file create the messages that are sent by Client.
+// Score uses the same functions so that scores contain exactly the
+// messages a client would have sent.
+
+// bufferAllocMsg creates a /b_alloc message.
+func bufferAllocMsg(num int32, frames, channels int) osc.Message {
+	return osc.Message{
+		Address: bufferAllocAddress,
+		Arguments: osc.Arguments{
+			osc.Int(num),
+			osc.Int(int32(frames)),
+			osc.Int(int32(channels)),
+		},
+	}
+}
+
+// bufferReadMsg creates a /b_allocRead message,
+// or a /b_allocReadChannel message if any channels are provided.
+func bufferReadMsg(path string, num int32, channels ...int) osc.Message {
+	addr := bufferReadAddress
+	if len(channels) > 0 {
+		addr = bufferReadChannelAddress
+	}
+	msg := osc.Message{
+		Address: addr,
+		Arguments: osc.Arguments{
+			osc.Int(num),
+			osc.String(path),
+		},
+	}
+	for _, channel := range channels {
+		msg.Arguments = append(msg.Arguments, osc.Int(int32(channel)))
+	}
+	return msg
+}
+
+// groupNewMsg creates a /g_new message.
+func groupNewMsg(id, action, target int32) osc.Message {
+	return osc.Message{
+		Address: groupNewAddress,
+		Arguments: osc.Arguments{
+			osc.Int(id),
+			osc.Int(action),
+			osc.Int(target),
+		},
+	}
+}
+
+// parGroupNewMsg creates a /p_new message.
+func parGroupNewMsg(id, action, target int32) osc.Message {
+	msg := groupNewMsg(id, action, target)
+	msg.Address = parGroupNewAddress
+	return msg
+}
+
+// nodeFreeMsg creates a /n_free message.
+func nodeFreeMsg(ids ...int32) osc.Message {
+	msg := osc.Message{Address: nodeFreeAddress}
+	for _, id := range ids {
+		msg.Arguments = append(msg.Arguments, osc.Int(id))
+	}
+	return msg
+}
+
+// nodeSetMsg creates a /n_set message.
+func nodeSetMsg(id int32, ctls map[string]float32) osc.Message {
+	msg := osc.Message{
+		Address: nodeSetAddress,
+		Arguments: osc.Arguments{
+			osc.Int(id),
+		},
+	}
+	msg.Arguments = appendControls(msg.Arguments, ctls)
+	return msg
+}
+
+// synthNewMsg creates a /s_new message.
+func synthNewMsg(defName string, id, action, target int32, ctls map[string]float32) osc.Message {
+	msg := osc.Message{
+		Address: synthNewAddress,
+		Arguments: osc.Arguments{
+			osc.String(defName),
+			osc.Int(id),
+			osc.Int(action),
+			osc.Int(target),
+		},
+	}
+	msg.Arguments = appendControls(msg.Arguments, ctls)
+	return msg
+}
+
+// synthdefReceiveMsg creates a /d_recv message.
+// All the synthdefs are sent in a single synthdef file.
+func synthdefReceiveMsg(defs ...*Synthdef) (osc.Message, error) {
+	db, err := NewSynthdefFile(defs...).Bytes()
+	if err != nil {
+		return osc.Message{}, err
+	}
+	return osc.Message{
+		Address: synthdefReceiveAddress,
+		Arguments: osc.Arguments{
+			osc.Blob(db),
+		},
+	}, nil
+}
+
+// appendControls appends name/value pairs for a map of controls to args.
+// The controls are sorted by name so that messages are deterministic.
+func appendControls(args osc.Arguments, ctls map[string]float32) osc.Arguments {
+	names := make([]string, 0, len(ctls))
+	for name := range ctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		args = append(args, osc.String(name), osc.Float(ctls[name]))
+	}
+	return args
+}
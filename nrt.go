@@ -0,0 +1,176 @@
+package sc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header formats for NRT output files.
+const (
+	HeaderAIFF  = "AIFF"
+	HeaderWAV   = "WAV"
+	HeaderNeXT  = "NeXT"
+	HeaderIRCAM = "IRCAM"
+	HeaderRaw   = "raw"
+)
+
+// Sample formats for NRT output files.
+const (
+	SampleInt8   = "int8"
+	SampleInt16  = "int16"
+	SampleInt24  = "int24"
+	SampleInt32  = "int32"
+	SampleFloat  = "float"
+	SampleDouble = "double"
+	SampleMulaw  = "mulaw"
+	SampleAlaw   = "alaw"
+)
+
+// nrtNoInput is the input file name that tells scsynth not to use an input file.
+const nrtNoInput = "_"
+
+// nrtProgressPrefix is the prefix of the lines scsynth prints
+// before it executes each bundle of a score.
+const nrtProgressPrefix = "nextOSCPacket "
+
+// nrtWarningMarkers are substrings of the lines scsynth prints when something goes wrong.
+// scsynth also prints some of them for harmless problems (e.g. a missing synthdef
+// directory), so they are only warnings unless scsynth exits with an error.
+var nrtWarningMarkers = []string{"ERROR", "FAILURE", "failed"}
+
+// RenderNRT renders a score to outputFile by running scsynth in non-realtime mode.
+// If inputFile is empty then no input file is used.
+// headerFormat should be one of the Header constants and sampleFormat
+// should be one of the Sample constants.
+// The number of output channels is taken from options.
+// If s.NRTProgress is not nil it is called with the time of each bundle
+// as scsynth reaches it.
+// RenderNRT blocks until scsynth exits. It returns the lines scsynth printed
+// that look like errors as warnings, and it only returns an error if scsynth
+// exits with a non-zero status. The error contains the warnings.
+func (s *Server) RenderNRT(score *Score, inputFile, outputFile string, sampleRate int, headerFormat, sampleFormat string, options ServerOptions) ([]string, error) {
+	if err := validateNRTFormats(headerFormat, sampleFormat); err != nil {
+		return nil, err
+	}
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("sample rate must be positive (got %d)", sampleRate)
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if inputFile == "" {
+		inputFile = nrtNoInput
+	}
+	serverPath, err := s.getServerPath()
+	if err != nil {
+		return nil, err
+	}
+	cmdFile, err := writeScoreFile(score)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.Remove(cmdFile) }() // Best effort.
+
+	args := append([]string{
+		"-N",
+		cmdFile,
+		inputFile,
+		outputFile,
+		strconv.Itoa(sampleRate),
+		headerFormat,
+		sampleFormat,
+	}, options.Args()...)
+
+	pr, pw := io.Pipe()
+	s.Cmd = exec.Command(serverPath, args...)
+	s.Cmd.Stdout = pw
+	s.Cmd.Stderr = pw
+
+	if err := s.Cmd.Start(); err != nil {
+		return nil, err
+	}
+	output := make(chan []string)
+	go func() {
+		output <- s.scanNRTOutput(pr)
+	}()
+	waitErr := s.Cmd.Wait()
+	_ = pw.Close() // Never returns an error.
+	warnings := <-output
+
+	if waitErr != nil {
+		if len(warnings) == 0 {
+			return nil, fmt.Errorf("rendering %s: %s", outputFile, waitErr)
+		}
+		return warnings, fmt.Errorf("rendering %s: %s: %s", outputFile, waitErr, strings.Join(warnings, "; "))
+	}
+	return warnings, nil
+}
+
+// scanNRTOutput reads the output of scsynth in NRT mode.
+// It reports progress and returns the lines that look like errors.
+func (s *Server) scanNRTOutput(r io.Reader) []string {
+	var (
+		warnings []string
+		scanner  = bufio.NewScanner(r)
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, nrtProgressPrefix) {
+			secs, err := strconv.ParseFloat(strings.TrimPrefix(line, nrtProgressPrefix), 64)
+			if err == nil && s.NRTProgress != nil {
+				s.NRTProgress(time.Duration(secs * float64(time.Second)))
+			}
+			continue
+		}
+		for _, marker := range nrtWarningMarkers {
+			if strings.Contains(line, marker) {
+				warnings = append(warnings, line)
+				break
+			}
+		}
+	}
+	_, _ = io.Copy(ioutil.Discard, r) // Don't block scsynth if the scanner failed.
+	return warnings
+}
+
+// writeScoreFile writes a score to a temporary file and returns the path of the file.
+func writeScoreFile(score *Score) (string, error) {
+	f, err := ioutil.TempFile("", "sc-score-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := score.WriteTo(f); err != nil {
+		_ = f.Close()           // Best effort.
+		_ = os.Remove(f.Name()) // Best effort.
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name()) // Best effort.
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// validateNRTFormats returns an error if the header or sample format
+// is not supported by scsynth.
+func validateNRTFormats(headerFormat, sampleFormat string) error {
+	switch headerFormat {
+	case HeaderAIFF, HeaderWAV, HeaderNeXT, HeaderIRCAM, HeaderRaw:
+	default:
+		return fmt.Errorf("unsupported header format: %s", headerFormat)
+	}
+	switch sampleFormat {
+	case SampleInt8, SampleInt16, SampleInt24, SampleInt32, SampleFloat, SampleDouble, SampleMulaw, SampleAlaw:
+	default:
+		return fmt.Errorf("unsupported sample format: %s", sampleFormat)
+	}
+	return nil
+}
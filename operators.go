@@ -85,6 +85,139 @@ const (
 	UnaryOpUgenName = "UnaryOpUGen"
 )
 
+// operator is a BinaryOpUGen or UnaryOpUGen operator.
+type operator struct {
+	sclang string // sclang is the sclang selector, e.g. "+" or "midicps"
+	method string // method is the Input method that creates the operator, or "" if there is none
+}
+
+// binOps are the BinaryOpUGen operators, indexed by special index.
+var binOps = []operator{
+	BinOpAdd:      {"+", "Add"},
+	1:             {"-", ""},
+	BinOpMul:      {"*", "Mul"},
+	3:             {"div", ""},
+	BinOpDiv:      {"/", "Div"},
+	BinOpModulo:   {"%", "Modulo"},
+	6:             {"==", ""},
+	7:             {"!=", ""},
+	BinOpLT:       {"<", "LT"},
+	BinOpGT:       {">", "GT"},
+	BinOpLTE:      {"<=", "LTE"},
+	BinOpGTE:      {">=", "GTE"},
+	BinOpMin:      {"min", "Min"},
+	BinOpMax:      {"max", "Max"},
+	14:            {"bitAnd", ""},
+	15:            {"bitOr", ""},
+	16:            {"bitXor", ""},
+	BinOpLCM:      {"lcm", "LCM"},
+	BinOpGCD:      {"gcd", "GCD"},
+	BinOpRound:    {"round", "Round"},
+	20:            {"roundUp", ""},
+	BinOpTrunc:    {"trunc", "Trunc"},
+	BinOpAtan2:    {"atan2", "Atan2"},
+	BinOpHypot:    {"hypot", "Hypot"},
+	BinOpHypotApx: {"hypotApx", "HypotApx"},
+	BinOpPow:      {"pow", "Pow"},
+	26:            {"leftShift", ""},
+	27:            {"rightShift", ""},
+	28:            {"unsignedRightShift", ""},
+	29:            {"fill", ""},
+	BinOpRing1:    {"ring1", "Ring1"},
+	BinOpRing2:    {"ring2", "Ring2"},
+	BinOpRing3:    {"ring3", "Ring3"},
+	BinOpRing4:    {"ring4", "Ring4"},
+	BinOpDifsqr:   {"difsqr", "Difsqr"},
+	BinOpSumsqr:   {"sumsqr", "Sumsqr"},
+	BinOpSqrsum:   {"sqrsum", "Sqrsum"},
+	BinOpSqrdif:   {"sqrdif", "Sqrdif"},
+	BinOpAbsdif:   {"absdif", "Absdif"},
+	BinOpThresh:   {"thresh", "Thresh"},
+	BinOpAmclip:   {"amclip", "Amclip"},
+	BinOpScaleneg: {"scaleneg", "Scaleneg"},
+	BinOpClip2:    {"clip2", "Clip2"},
+	BinOpExcess:   {"excess", "Excess"},
+	BinOpFold2:    {"fold2", "Fold2"},
+	BinOpWrap2:    {"wrap2", "Wrap2"},
+	46:            {"firstArg", ""},
+	47:            {"rrand", ""},
+	48:            {"exprand", ""},
+}
+
+// unaryOps are the UnaryOpUGen operators, indexed by special index.
+var unaryOps = []operator{
+	UnaryOpNeg:        {"neg", "Neg"},
+	1:                 {"not", ""},
+	2:                 {"isNil", ""},
+	3:                 {"notNil", ""},
+	4:                 {"bitNot", ""},
+	UnaryOpAbs:        {"abs", "Abs"},
+	6:                 {"asFloat", ""},
+	7:                 {"asInteger", ""},
+	UnaryOpCeil:       {"ceil", "Ceil"},
+	UnaryOpFloor:      {"floor", "Floor"},
+	UnaryOpFrac:       {"frac", "Frac"},
+	UnaryOpSign:       {"sign", "Sign"},
+	UnaryOpSquared:    {"squared", "Squared"},
+	UnaryOpCubed:      {"cubed", "Cubed"},
+	UnaryOpSqrt:       {"sqrt", "Sqrt"},
+	UnaryOpExp:        {"exp", "Exp"},
+	UnaryOpReciprocal: {"reciprocal", "Reciprocal"},
+	UnaryOpMidicps:    {"midicps", "Midicps"},
+	UnaryOpCpsmidi:    {"cpsmidi", "Cpsmidi"},
+	UnaryOpMidiratio:  {"midiratio", "Midiratio"},
+	UnaryOpRatiomidi:  {"ratiomidi", "Ratiomidi"},
+	UnaryOpDbAmp:      {"dbamp", "DbAmp"},
+	UnaryOpAmpDb:      {"ampdb", "AmpDb"},
+	UnaryOpOctcps:     {"octcps", "Octcps"},
+	UnaryOpCpsoct:     {"cpsoct", "Cpsoct"},
+	UnaryOpLog:        {"log", "Log"},
+	UnaryOpLog2:       {"log2", "Log2"},
+	UnaryOpLog10:      {"log10", "Log10"},
+	UnaryOpSin:        {"sin", "Sin"},
+	UnaryOpCos:        {"cos", "Cos"},
+	UnaryOpTan:        {"tan", "Tan"},
+	UnaryOpAsin:       {"asin", "Asin"},
+	UnaryOpAcos:       {"acos", "Acos"},
+	UnaryOpAtan:       {"atan", "Atan"},
+	UnaryOpSinh:       {"sinh", "Sinh"},
+	UnaryOpCosh:       {"cosh", "Cosh"},
+	UnaryOpTanh:       {"tanh", "Tanh"},
+	UnaryOpRand:       {"rand", "Rand"},
+	UnaryOpRand2:      {"rand2", "Rand2"},
+	UnaryOpLinrand:    {"linrand", "Linrand"},
+	UnaryOpBilinrand:  {"bilinrand", "Bilinrand"},
+	UnaryOpSum3rand:   {"sum3rand", "Sum3rand"},
+	UnaryOpDistort:    {"distort", "Distort"},
+	UnaryOpSoftClip:   {"softclip", "SoftClip"},
+	UnaryOpCoin:       {"coin", "Coin"},
+	45:                {"digitValue", ""},
+	46:                {"silence", ""},
+	47:                {"thru", ""},
+	48:                {"rectWindow", ""},
+	49:                {"hanWindow", ""},
+	50:                {"welWindow", ""},
+	51:                {"triWindow", ""},
+	52:                {"ramp", ""},
+	53:                {"scurve", ""},
+}
+
+// lookupOp returns the operator of a BinaryOpUGen or UnaryOpUGen.
+// The bool is false if u is not an operator or has an unknown special index.
+func lookupOp(u *Ugen) (operator, bool) {
+	var ops []operator
+	switch u.Name {
+	case BinOpUgenName:
+		ops = binOps
+	case UnaryOpUgenName:
+		ops = unaryOps
+	}
+	if u.SpecialIndex < 0 || int(u.SpecialIndex) >= len(ops) {
+		return operator{}, false
+	}
+	return ops[u.SpecialIndex], true
+}
+
 // binOpAbsdif returns the absolute value of the difference of two inputs.
 func binOpAbsdif(rate int8, x, y Input, numOutputs int) Input {
 	CheckRate(rate)
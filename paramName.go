@@ -1,31 +1,33 @@
 package sc
 
-import (
-	"encoding/binary"
-	"io"
-)
+import "io"
 
 // ParamName represents a parameter name of a synthdef
 type ParamName struct {
-	Name  string `json:"name,omitempty"`
-	Index int32  `json:"index"`
+	Name  string `json:"name,omitempty" xml:"name,attr"`
+	Index int32  `json:"index"          xml:"index,attr"`
 }
 
 func (pn *ParamName) Write(w io.Writer) error {
+	return pn.write(w, synthdefVersion)
+}
+
+// write writes a ParamName using a particular synthdef file version.
+func (pn *ParamName) write(w io.Writer, version int32) error {
 	if err := newPstring(pn.Name).Write(w); err != nil {
 		return err
 	}
-	return binary.Write(w, byteOrder, int32(pn.Index))
+	return writeSynthdefInt(w, version, pn.Index)
 }
 
 // readParamName reads a ParamName from an io.Reader
-func readParamName(r io.Reader) (*ParamName, error) {
+func readParamName(r io.Reader, version int32) (*ParamName, error) {
 	name, err := readPstring(r)
 	if err != nil {
 		return nil, err
 	}
-	var idx int32
-	if err := binary.Read(r, byteOrder, &idx); err != nil {
+	idx, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, err
 	}
 	pn := ParamName{name.String(), idx}
@@ -0,0 +1,287 @@
+package sc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrEmptyPool happens when you try to use a ServerPool that has no clients.
+var ErrEmptyPool = errors.New("server pool has no clients")
+
+// ServerPool spreads synths across several scsynth instances.
+// Synthdefs and buffers are sent to every server in the pool,
+// and new synths are created on the least-loaded server.
+type ServerPool struct {
+	// Clients are the clients for each server in the pool.
+	Clients []*Client
+
+	mu       sync.Mutex
+	watching bool        // watching is true once WatchStatus has been called
+	placed   []int32     // placed counts the synths created on each server since its last snapshot
+	snapped  []time.Time // snapped is the time of the snapshot that placed is counted from
+}
+
+// NewServerPool creates a new server pool.
+func NewServerPool(clients ...*Client) *ServerPool {
+	return &ServerPool{Clients: clients}
+}
+
+// PoolSynth is a synth that was created by a ServerPool.
+type PoolSynth struct {
+	*Synth
+
+	// Server is the index of the client in the pool
+	// that created the synth.
+	Server int
+}
+
+// PoolStatus is the aggregate status of all the servers in a pool.
+type PoolStatus struct {
+	// Servers contains the status of each server in the pool.
+	// The status of a server that did not reply is nil.
+	Servers []*ServerStatus `json:"servers"`
+
+	// Errors contains the error for each server that did not reply.
+	Errors []error `json:"-"`
+
+	// NumHealthy is the number of servers that replied.
+	NumHealthy int `json:"numHealthy"`
+
+	NumUgens     int32   `json:"numUgens"`
+	NumSynths    int32   `json:"numSynths"`
+	NumGroups    int32   `json:"numGroups"`
+	NumSynthdefs int32   `json:"numSynthdefs"`
+	AvgCPU       float32 `json:"avgCPU"`  // AvgCPU is the mean of AvgCPU over the healthy servers.
+	PeakCPU      float32 `json:"peakCPU"` // PeakCPU is the max of PeakCPU over the healthy servers.
+}
+
+// Healthy returns true if every server in the pool replied.
+func (ps *PoolStatus) Healthy() bool {
+	return ps.NumHealthy == len(ps.Servers)
+}
+
+// AllocBuffer allocates a buffer on every server in the pool.
+// The returned buffers are in the same order as the pool's clients.
+func (pool *ServerPool) AllocBuffer(frames, channels int) ([]*Buffer, error) {
+	bufs := make([]*Buffer, len(pool.Clients))
+	err := pool.each(func(i int, c *Client) error {
+		buf, err := c.AllocBuffer(frames, channels)
+		bufs[i] = buf
+		return err
+	})
+	return bufs, err
+}
+
+// ReadBuffer reads an audio file into a buffer on every server in the pool.
+// The returned buffers are in the same order as the pool's clients.
+func (pool *ServerPool) ReadBuffer(path string, num int32, channels ...int) ([]*Buffer, error) {
+	bufs := make([]*Buffer, len(pool.Clients))
+	err := pool.each(func(i int, c *Client) error {
+		buf, err := c.ReadBuffer(path, num, channels...)
+		bufs[i] = buf
+		return err
+	})
+	return bufs, err
+}
+
+// SendDef sends a synthdef to every server in the pool.
+func (pool *ServerPool) SendDef(def *Synthdef) error {
+	return pool.each(func(i int, c *Client) error {
+		return c.SendDef(def)
+	})
+}
+
+// WatchStatus collects the status of every server in the pool
+// every interval until ctx is done, see Client.WatchStatus.
+// Synth uses the collected snapshots to place synths,
+// and Status returns them instead of sending status requests.
+func (pool *ServerPool) WatchStatus(ctx context.Context, interval time.Duration) {
+	pool.mu.Lock()
+	pool.watching = true
+	pool.mu.Unlock()
+
+	for _, c := range pool.Clients {
+		go func(snapshots <-chan StatusSnapshot) {
+			for range snapshots {
+			}
+		}(c.WatchStatus(ctx, interval))
+	}
+}
+
+// Status gets the status of every server in the pool.
+// If the pool is watching the status of its servers, it returns the most
+// recent snapshots instead of requesting the status of each server,
+// and servers without a snapshot count as not replying.
+// It only returns an error if none of the servers replied.
+func (pool *ServerPool) Status(timeout time.Duration) (*PoolStatus, error) {
+	if len(pool.Clients) == 0 {
+		return nil, ErrEmptyPool
+	}
+	var (
+		n      = len(pool.Clients)
+		status = &PoolStatus{
+			Servers: make([]*ServerStatus, n),
+			Errors:  make([]error, n),
+		}
+		wg sync.WaitGroup
+	)
+	pool.mu.Lock()
+	watching := pool.watching
+	pool.mu.Unlock()
+
+	for i, c := range pool.Clients {
+		if watching {
+			if snap, ok := c.LastStatus(); ok {
+				status.Servers[i] = &snap.ServerStatus
+			} else {
+				status.Errors[i] = errors.New("no status has been collected yet")
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c *Client) {
+			status.Servers[i], status.Errors[i] = c.Status(timeout)
+			wg.Done()
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, s := range status.Servers {
+		if s == nil {
+			continue
+		}
+		status.NumHealthy++
+		status.NumUgens += s.NumUgens
+		status.NumSynths += s.NumSynths
+		status.NumGroups += s.NumGroups
+		status.NumSynthdefs += s.NumSynthdefs
+		status.AvgCPU += s.AvgCPU
+		if s.PeakCPU > status.PeakCPU {
+			status.PeakCPU = s.PeakCPU
+		}
+	}
+	if status.NumHealthy == 0 {
+		return nil, fmt.Errorf("no server in the pool replied: %v", status.Errors[0])
+	}
+	status.AvgCPU /= float32(status.NumHealthy)
+	return status, nil
+}
+
+// Synth creates a synth node on the least-loaded server in the pool.
+// The load of each server is estimated from the most recent snapshot
+// collected by WatchStatus (or Client.WatchStatus), plus the synths the pool
+// has created on that server since the snapshot was taken.
+// Synth never sends status requests. Servers without a snapshot are skipped,
+// and if none of the servers has one the synths are spread evenly.
+// target must be a group that exists on every server (e.g. DefaultGroupID).
+func (pool *ServerPool) Synth(defName string, action, target int32, ctls map[string]float32) (*PoolSynth, error) {
+	if len(pool.Clients) == 0 {
+		return nil, ErrEmptyPool
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var (
+		idx = leastLoaded(pool.loads())
+		c   = pool.Clients[idx]
+	)
+	synth, err := c.Synth(defName, c.NextSynthID(), action, target, ctls)
+	if err != nil {
+		return nil, err
+	}
+	pool.placed[idx]++
+
+	return &PoolSynth{Synth: synth, Server: idx}, nil
+}
+
+// serverLoad is the estimated load of a server in a pool.
+type serverLoad struct {
+	known  bool    // known is false if there is no status snapshot for the server
+	cpu    float32 // cpu is the estimated AvgCPU
+	synths int32   // synths is the estimated number of synths
+}
+
+// loads returns the estimated load of each server in the pool.
+// Synths created since the last snapshot are assumed to use as much CPU
+// as the average synth in the snapshot.
+// It must be called with pool.mu held.
+func (pool *ServerPool) loads() []serverLoad {
+	if len(pool.placed) != len(pool.Clients) {
+		pool.placed = make([]int32, len(pool.Clients))
+		pool.snapped = make([]time.Time, len(pool.Clients))
+	}
+	loads := make([]serverLoad, len(pool.Clients))
+	for i, c := range pool.Clients {
+		snap, ok := c.LastStatus()
+		if !ok {
+			loads[i].synths = pool.placed[i]
+			continue
+		}
+		if !snap.Time.Equal(pool.snapped[i]) {
+			pool.placed[i], pool.snapped[i] = 0, snap.Time
+		}
+		loads[i] = serverLoad{
+			known:  true,
+			cpu:    snap.AvgCPU,
+			synths: snap.NumSynths + pool.placed[i],
+		}
+		if snap.NumSynths > 0 {
+			loads[i].cpu += float32(pool.placed[i]) * snap.AvgCPU / float32(snap.NumSynths)
+		}
+	}
+	return loads
+}
+
+// each calls f for every client in the pool concurrently.
+// It returns the first error returned by f.
+func (pool *ServerPool) each(f func(i int, c *Client) error) error {
+	if len(pool.Clients) == 0 {
+		return ErrEmptyPool
+	}
+	var (
+		errs = make([]error, len(pool.Clients))
+		wg   sync.WaitGroup
+	)
+	for i, c := range pool.Clients {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			errs[i] = f(i, c)
+			wg.Done()
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("server %d in the pool: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// leastLoaded returns the index of the smallest load.
+// Ties are broken by the number of synths.
+// Unknown loads are skipped, unless all the loads are unknown,
+// in which case it returns the index of the server with the fewest synths.
+func leastLoaded(loads []serverLoad) int {
+	idx := 0
+	for i, load := range loads {
+		min := loads[idx]
+		switch {
+		case load.known != min.known:
+			if load.known {
+				idx = i
+			}
+		case load.known && load.cpu != min.cpu:
+			if load.cpu < min.cpu {
+				idx = i
+			}
+		case load.synths < min.synths:
+			idx = i
+		}
+	}
+	return idx
+}
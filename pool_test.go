@@ -0,0 +1,277 @@
+package sc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestLeastLoaded(t *testing.T) {
+	load := func(cpu float32, synths int32) serverLoad {
+		return serverLoad{known: true, cpu: cpu, synths: synths}
+	}
+	unknown := func(synths int32) serverLoad {
+		return serverLoad{synths: synths}
+	}
+	for i, testcase := range []struct {
+		loads    []serverLoad
+		expected int
+	}{
+		{[]serverLoad{unknown(0), unknown(0)}, 0},
+		{[]serverLoad{unknown(2), unknown(1), unknown(1)}, 1},
+		{[]serverLoad{load(12, 0), load(3, 0), load(7, 0)}, 1},
+		{[]serverLoad{unknown(0), load(20, 0), load(10, 5)}, 2},
+		{[]serverLoad{load(5, 3), load(5, 2)}, 1},
+		{[]serverLoad{load(5, 2), load(5, 2)}, 0},
+	} {
+		if got := leastLoaded(testcase.loads); got != testcase.expected {
+			t.Fatalf("testcase %d: expected %d, got %d", i, testcase.expected, got)
+		}
+	}
+}
+
+func TestEmptyServerPool(t *testing.T) {
+	pool := NewServerPool()
+	if _, err := pool.Synth("foo", AddToTail, DefaultGroupID, nil); err != ErrEmptyPool {
+		t.Fatalf("expected ErrEmptyPool, got %v", err)
+	}
+	if err := pool.SendDef(nil); err != ErrEmptyPool {
+		t.Fatalf("expected ErrEmptyPool, got %v", err)
+	}
+	if _, err := pool.Status(0); err != ErrEmptyPool {
+		t.Fatalf("expected ErrEmptyPool, got %v", err)
+	}
+}
+
+// newTestPool creates a pool of clients connected to fake servers.
+func newTestPool(t *testing.T, n int) (*ServerPool, []*FakeServer) {
+	var (
+		pool    = NewServerPool()
+		servers = make([]*FakeServer, n)
+	)
+	for i := range servers {
+		c, fs := newFakeClient(t)
+		servers[i] = fs
+		pool.Clients = append(pool.Clients, c)
+	}
+	return pool, servers
+}
+
+// newFakeServer creates a fake server that is closed when the test finishes.
+func newFakeServer(t *testing.T) *FakeServer {
+	fs, err := NewFakeServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = fs.Close() }) // Best effort.
+	return fs
+}
+
+// newFakeClient creates a udp client connected to a new fake server.
+// Both are closed when the test finishes.
+func newFakeClient(t *testing.T) (*Client, *FakeServer) {
+	fs := newFakeServer(t)
+	c, err := NewClient("udp", "127.0.0.1:0", fs.Addr(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() }) // Best effort.
+	return c, fs
+}
+
+// received counts the messages with an address that a fake server received.
+func received(fs *FakeServer, addr string) int {
+	var n int
+	for _, msg := range fs.Received() {
+		if msg.Address == addr {
+			n++
+		}
+	}
+	return n
+}
+
+// waitReceived waits for a fake server to receive a message with an address,
+// and returns the first one.
+func waitReceived(t *testing.T, fs *FakeServer, addr string) osc.Message {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		for _, msg := range fs.Received() {
+			if msg.Address == addr {
+				return msg
+			}
+		}
+	}
+	t.Fatalf("timed out waiting for %s", addr)
+	return osc.Message{}
+}
+
+// checkArguments checks the arguments of a message a fake server received.
+func checkArguments(t *testing.T, msg osc.Message, expected ...osc.Argument) {
+	t.Helper()
+
+	if len(expected) != len(msg.Arguments) {
+		t.Fatalf("%s: expected %d arguments, got %d", msg.Address, len(expected), len(msg.Arguments))
+	}
+	for i, arg := range expected {
+		if !arg.Equal(msg.Arguments[i]) {
+			t.Fatalf("%s: expected argument %d to be %v, got %v", msg.Address, i, arg, msg.Arguments[i])
+		}
+	}
+}
+
+func TestServerPoolSendDef(t *testing.T) {
+	pool, servers := newTestPool(t, 3)
+
+	if err := pool.SendDef(NewSynthdef("sine_a", defSineA)); err != nil {
+		t.Fatal(err)
+	}
+	for i, fs := range servers {
+		if expected, got := 1, received(fs, synthdefReceiveAddress); expected != got {
+			t.Fatalf("server %d: expected %d %s messages, got %d", i, expected, synthdefReceiveAddress, got)
+		}
+	}
+}
+
+func TestServerPoolBuffers(t *testing.T) {
+	pool, servers := newTestPool(t, 2)
+
+	bufs, err := pool.AllocBuffer(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := len(servers), len(bufs); expected != got {
+		t.Fatalf("expected %d buffers, got %d", expected, got)
+	}
+	for i, buf := range bufs {
+		if buf.client != pool.Clients[i] {
+			t.Fatalf("buffer %d: expected the client of server %d", i, i)
+		}
+	}
+	if _, err := pool.ReadBuffer("pool_test.wav", 10); err != nil {
+		t.Fatal(err)
+	}
+	for i, fs := range servers {
+		if expected, got := 1, received(fs, bufferAllocAddress); expected != got {
+			t.Fatalf("server %d: expected %d %s messages, got %d", i, expected, bufferAllocAddress, got)
+		}
+		if expected, got := 1, received(fs, bufferReadAddress); expected != got {
+			t.Fatalf("server %d: expected %d %s messages, got %d", i, expected, bufferReadAddress, got)
+		}
+	}
+}
+
+func TestServerPoolSynth(t *testing.T) {
+	pool, servers := newTestPool(t, 3)
+
+	// Without status snapshots the synths are spread evenly.
+	for i := 0; i < 3; i++ {
+		synth, err := pool.Synth("sine_a", AddToTail, DefaultGroupID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := i, synth.Server; expected != got {
+			t.Fatalf("expected synth %d on server %d, got %d", i, expected, got)
+		}
+	}
+	servers[0].SetCPU(30, 40)
+	servers[1].SetCPU(5, 10)
+	servers[2].SetCPU(10, 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.WatchStatus(ctx, time.Hour)
+
+	for i, c := range pool.Clients {
+		waitForStatus(t, c, i)
+	}
+	// Server 1 reported 5% CPU for its only synth, so after one more synth
+	// it is estimated at 10%, and ties with server 2 which has fewer synths.
+	for i, expected := range []int{1, 2, 1} {
+		synth, err := pool.Synth("sine_a", AddToTail, DefaultGroupID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := synth.Server; expected != got {
+			t.Fatalf("synth %d: expected server %d, got %d", i, expected, got)
+		}
+	}
+	// The only status requests come from WatchStatus.
+	for i, fs := range servers {
+		if expected, got := 1, received(fs, statusAddress); expected != got {
+			t.Fatalf("server %d: expected %d status requests, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestServerPoolStatus(t *testing.T) {
+	pool, servers := newTestPool(t, 2)
+
+	servers[0].SetCPU(10, 20)
+	servers[1].SetCPU(30, 50)
+
+	if _, err := pool.Clients[0].Synth("sine_a", 1000, AddToTail, RootNodeID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.SendDef(NewSynthdef("sine_a", defSineA)); err != nil {
+		t.Fatal(err)
+	}
+	check := func(status *PoolStatus) {
+		t.Helper()
+
+		if !status.Healthy() {
+			t.Fatalf("expected a healthy pool, got errors %v", status.Errors)
+		}
+		if expected, got := int32(1), status.NumSynths; expected != got {
+			t.Fatalf("expected %d synths, got %d", expected, got)
+		}
+		if expected, got := int32(2), status.NumSynthdefs; expected != got {
+			t.Fatalf("expected %d synthdefs, got %d", expected, got)
+		}
+		if expected, got := float32(20), status.AvgCPU; expected != got {
+			t.Fatalf("expected avg cpu %f, got %f", expected, got)
+		}
+		if expected, got := float32(50), status.PeakCPU; expected != got {
+			t.Fatalf("expected peak cpu %f, got %f", expected, got)
+		}
+	}
+	status, err := pool.Status(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(status)
+
+	// Once the pool is watching, Status returns the snapshots.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.WatchStatus(ctx, time.Hour)
+
+	for i, c := range pool.Clients {
+		waitForStatus(t, c, i)
+	}
+	status, err = pool.Status(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(status)
+
+	for i, fs := range servers {
+		if expected, got := 2, received(fs, statusAddress); expected != got {
+			t.Fatalf("server %d: expected %d status requests, got %d", i, expected, got)
+		}
+	}
+}
+
+// waitForStatus waits for the first snapshot collected by WatchStatus.
+func waitForStatus(t *testing.T, c *Client, server int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if _, ok := c.LastStatus(); ok {
+			return
+		}
+	}
+	t.Fatalf("server %d: timed out waiting for a status snapshot", server)
+}
@@ -20,6 +20,20 @@ func CheckRate(rate int8) {
 	}
 }
 
+// rateName returns the lower case name of a rate, e.g. "ar" for AR,
+// or other if rate is not IR, KR, or AR.
+func rateName(rate int8, other string) string {
+	switch rate {
+	case IR:
+		return "ir"
+	case KR:
+		return "kr"
+	case AR:
+		return "ar"
+	}
+	return other
+}
+
 // Rater is the interface of a Ugen that can compute its output at different rates.
 type Rater interface {
 	Rate(int8) Input
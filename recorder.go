@@ -0,0 +1,179 @@
+package sc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Recorder defaults.
+const (
+	// DefaultRecorderChannels is the default number of channels a Recorder records.
+	DefaultRecorderChannels = 2
+
+	// DefaultRecorderBufferFrames is the default size of the buffer DiskOut streams through.
+	DefaultRecorderBufferFrames = 65536
+)
+
+// Recorder errors.
+var (
+	ErrRecorderStarted    = errors.New("recorder already started")
+	ErrRecorderNotStarted = errors.New("recorder not started")
+)
+
+// Recorder records audio buses to a sound file on the server's machine.
+// It streams the buses to disk with a DiskOut synth at the tail of
+// the root node, so everything else on the server is recorded.
+type Recorder struct {
+	// Path is the path of the sound file.
+	Path string
+
+	// Bus is the first audio bus that is recorded.
+	Bus int32
+
+	// NumChannels is the number of channels that are recorded,
+	// starting at Bus. If it is 0 DefaultRecorderChannels is used.
+	NumChannels int
+
+	// HeaderFormat is one of the Header constants.
+	// If it is empty HeaderAIFF is used.
+	HeaderFormat string
+
+	// SampleFormat is one of the Sample constants.
+	// If it is empty SampleFloat is used.
+	SampleFormat string
+
+	// BufferFrames is the number of frames in the buffer DiskOut streams through.
+	// It must be a power of two. If it is 0 DefaultRecorderBufferFrames is used.
+	BufferFrames int
+
+	client *Client
+
+	mu    sync.Mutex
+	buf   *Buffer
+	synth *Synth
+}
+
+// NewRecorder creates a recorder that records the first
+// DefaultRecorderChannels audio buses to path.
+func (c *Client) NewRecorder(path string) *Recorder {
+	return &Recorder{
+		Path:   path,
+		client: c,
+	}
+}
+
+// Start starts recording.
+// It allocates a buffer, opens the sound file for writing,
+// and adds a DiskOut synth at the tail of the root node.
+func (r *Recorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.synth != nil {
+		return ErrRecorderStarted
+	}
+	r.defaults()
+
+	if err := validateNRTFormats(r.HeaderFormat, r.SampleFormat); err != nil {
+		return err
+	}
+	if n := r.BufferFrames; n&(n-1) != 0 {
+		return fmt.Errorf("BufferFrames must be a power of two (got %d)", n)
+	}
+	def := recorderSynthdef(r.NumChannels)
+	if err := r.client.SendDef(def); err != nil {
+		return err
+	}
+	buf, err := r.client.AllocBuffer(r.BufferFrames, r.NumChannels)
+	if err != nil {
+		return err
+	}
+	if err := buf.Write(r.Path, r.HeaderFormat, r.SampleFormat, 0, 0, true); err != nil {
+		_ = buf.Free() // Best effort.
+		return err
+	}
+	synth, err := r.client.Synth(def.Name, r.client.NextSynthID(), AddToTail, RootNodeID, map[string]float32{
+		"bufnum": float32(buf.Num),
+		"in":     float32(r.Bus),
+	})
+	if err != nil {
+		_ = buf.Close() // Best effort.
+		_ = buf.Free()  // Best effort.
+		return err
+	}
+	r.buf, r.synth = buf, synth
+	return nil
+}
+
+// Pause pauses recording.
+func (r *Recorder) Pause() error {
+	return r.run(false)
+}
+
+// Resume resumes recording after Pause.
+func (r *Recorder) Resume() error {
+	return r.run(true)
+}
+
+// Stop stops recording.
+// It frees the DiskOut synth, closes the sound file, and frees the buffer.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.synth == nil {
+		return ErrRecorderNotStarted
+	}
+	buf, synth := r.buf, r.synth
+	r.buf, r.synth = nil, nil
+
+	if err := r.client.NodeFree(synth.ID); err != nil {
+		return err
+	}
+	if err := buf.Close(); err != nil {
+		return err
+	}
+	return buf.Free()
+}
+
+// run turns the DiskOut synth on or off.
+func (r *Recorder) run(on bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.synth == nil {
+		return ErrRecorderNotStarted
+	}
+	return r.client.NodeRun(r.synth.ID, on)
+}
+
+// defaults sets default values for the recorder's options.
+func (r *Recorder) defaults() {
+	if r.NumChannels <= 0 {
+		r.NumChannels = DefaultRecorderChannels
+	}
+	if r.HeaderFormat == "" {
+		r.HeaderFormat = HeaderAIFF
+	}
+	if r.SampleFormat == "" {
+		r.SampleFormat = SampleFloat
+	}
+	if r.BufferFrames <= 0 {
+		r.BufferFrames = DefaultRecorderBufferFrames
+	}
+}
+
+// recorderSynthdef returns the synthdef a Recorder uses to record numChannels channels.
+func recorderSynthdef(numChannels int) *Synthdef {
+	return NewSynthdef(fmt.Sprintf("sc-recorder-%d", numChannels), func(p Params) Ugen {
+		var (
+			bufnum = p.Add("bufnum", 0)
+			in     = p.Add("in", 0)
+		)
+		return DiskOut{
+			BufNum:   bufnum,
+			Channels: In{NumChannels: numChannels, Bus: in}.Rate(AR),
+		}.Rate(AR)
+	})
+}
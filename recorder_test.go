@@ -0,0 +1,83 @@
+package sc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	rec := client.NewRecorder("/tmp/out.wav")
+	rec.NumChannels = 4
+	rec.HeaderFormat = HeaderWAV
+	rec.SampleFormat = SampleInt24
+
+	if err := rec.Pause(); err != ErrRecorderNotStarted {
+		t.Fatalf("expected %v, got %v", ErrRecorderNotStarted, err)
+	}
+	if err := rec.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Start(); err != ErrRecorderStarted {
+		t.Fatalf("expected %v, got %v", ErrRecorderStarted, err)
+	}
+	if err := rec.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	received := fs.Received()
+
+	addrs := []string{}
+	for _, msg := range received {
+		addrs = append(addrs, msg.Address)
+	}
+	expected := []string{
+		synthdefReceiveAddress,
+		bufferAllocAddress,
+		bufferWriteAddress,
+		synthNewAddress,
+		nodeRunAddress,
+		nodeRunAddress,
+		nodeFreeAddress,
+		bufferCloseAddress,
+		bufferFreeAddress,
+	}
+	if !reflect.DeepEqual(expected, addrs) {
+		t.Fatalf("expected %v, got %v", expected, addrs)
+	}
+	// The buffer has one channel per recorded channel.
+	if channels, _ := received[1].Arguments[2].ReadInt32(); channels != 4 {
+		t.Fatalf("expected 4 channels, got %d", channels)
+	}
+	// The synth is added to the tail of the root node.
+	if action, _ := received[3].Arguments[2].ReadInt32(); action != AddToTail {
+		t.Fatalf("expected action %d, got %d", AddToTail, action)
+	}
+}
+
+func TestRecorderSynthdef(t *testing.T) {
+	def := recorderSynthdef(3)
+
+	if expected, got := "sc-recorder-3", def.Name; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	var diskOut *Ugen
+	for _, u := range def.Ugens {
+		if u.Name == "DiskOut" {
+			diskOut = u
+		}
+	}
+	if diskOut == nil {
+		t.Fatal("expected a DiskOut ugen")
+	}
+	// The bufnum plus one input per channel.
+	if expected, got := 4, len(diskOut.Inputs); expected != got {
+		t.Fatalf("expected %d inputs, got %d", expected, got)
+	}
+}
@@ -0,0 +1,318 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+// ScoreBundle is a list of messages that are executed at the same time in a score.
+type ScoreBundle struct {
+	// Time is the time of the bundle relative to the start of the score.
+	Time time.Duration
+
+	// Messages are the messages in the bundle.
+	Messages []osc.Message
+}
+
+// Score is a time-ordered list of OSC bundles that can be
+// rendered with scsynth in non-realtime mode (see Server.RenderNRT).
+// The methods of Score that add messages create the same messages
+// as the corresponding methods of Client.
+type Score struct {
+	Bundles []ScoreBundle
+}
+
+// NewScore creates a new empty score.
+func NewScore() *Score {
+	return &Score{Bundles: []ScoreBundle{}}
+}
+
+// Add adds messages to the score at the provided time.
+// Messages that are added at the same time are kept in the order they were added.
+func (s *Score) Add(at time.Duration, msgs ...osc.Message) {
+	i := sort.Search(len(s.Bundles), func(i int) bool {
+		return s.Bundles[i].Time >= at
+	})
+	if i < len(s.Bundles) && s.Bundles[i].Time == at {
+		s.Bundles[i].Messages = append(s.Bundles[i].Messages, msgs...)
+		return
+	}
+	s.Bundles = append(s.Bundles, ScoreBundle{})
+	copy(s.Bundles[i+1:], s.Bundles[i:])
+	s.Bundles[i] = ScoreBundle{
+		Time:     at,
+		Messages: append([]osc.Message{}, msgs...),
+	}
+}
+
+// AllocBuffer adds a /b_alloc message to the score.
+func (s *Score) AllocBuffer(at time.Duration, num int32, frames, channels int) {
+	s.Add(at, bufferAllocMsg(num, frames, channels))
+}
+
+// ReadBuffer adds a /b_allocRead (or /b_allocReadChannel) message to the score.
+// In non-realtime mode the path is read by scsynth, so it must be accessible
+// from the machine that renders the score.
+func (s *Score) ReadBuffer(at time.Duration, path string, num int32, channels ...int) {
+	s.Add(at, bufferReadMsg(path, num, channels...))
+}
+
+// Group adds a /g_new message to the score.
+func (s *Score) Group(at time.Duration, id, action, target int32) {
+	s.Add(at, groupNewMsg(id, action, target))
+}
+
+// ParGroup adds a /p_new message to the score (supernova only).
+func (s *Score) ParGroup(at time.Duration, id, action, target int32) {
+	s.Add(at, parGroupNewMsg(id, action, target))
+}
+
+// NodeFree adds a /n_free message to the score.
+func (s *Score) NodeFree(at time.Duration, ids ...int32) {
+	s.Add(at, nodeFreeMsg(ids...))
+}
+
+// NodeSet adds a /n_set message to the score.
+func (s *Score) NodeSet(at time.Duration, id int32, ctls map[string]float32) {
+	s.Add(at, nodeSetMsg(id, ctls))
+}
+
+// SendDef adds a /d_recv message to the score.
+func (s *Score) SendDef(at time.Duration, def *Synthdef) error {
+	msg, err := synthdefReceiveMsg(def)
+	if err != nil {
+		return err
+	}
+	s.Add(at, msg)
+	return nil
+}
+
+// Synth adds a /s_new message to the score.
+func (s *Score) Synth(at time.Duration, defName string, id, action, target int32, ctls map[string]float32) {
+	s.Add(at, synthNewMsg(defName, id, action, target, ctls))
+}
+
+// End marks the end of the score.
+// scsynth stops rendering after it executes the last bundle in a score,
+// so this adds a message that does nothing at the provided time.
+func (s *Score) End(at time.Duration) {
+	s.Add(at, osc.Message{
+		Address:   controlBusSetAddress,
+		Arguments: osc.Arguments{osc.Int(0), osc.Int(0)},
+	})
+}
+
+// Duration returns the time of the last bundle in the score.
+func (s *Score) Duration() time.Duration {
+	if len(s.Bundles) == 0 {
+		return 0
+	}
+	return s.Bundles[len(s.Bundles)-1].Time
+}
+
+// Merge returns a new score that contains the bundles of both scores.
+// When both scores have a bundle at the same time, the messages
+// of s come before the messages of other.
+func (s *Score) Merge(other *Score) *Score {
+	merged := s.Shift(0)
+	for _, bun := range other.Bundles {
+		merged.Add(bun.Time, bun.Messages...)
+	}
+	return merged
+}
+
+// Shift returns a new score with every bundle moved by d.
+func (s *Score) Shift(d time.Duration) *Score {
+	shifted := &Score{Bundles: make([]ScoreBundle, len(s.Bundles))}
+	for i, bun := range s.Bundles {
+		shifted.Bundles[i] = ScoreBundle{
+			Time:     bun.Time + d,
+			Messages: append([]osc.Message{}, bun.Messages...),
+		}
+	}
+	return shifted
+}
+
+// Slice returns a new score that contains the bundles whose time is
+// in the range [start, end). The times of the bundles are not changed,
+// use Shift(-start) to make the slice start at zero.
+func (s *Score) Slice(start, end time.Duration) *Score {
+	sliced := NewScore()
+	for _, bun := range s.Bundles {
+		if bun.Time >= start && bun.Time < end {
+			sliced.Add(bun.Time, bun.Messages...)
+		}
+	}
+	return sliced
+}
+
+// String returns a human-readable representation of a score,
+// with one line for the time of each bundle followed by
+// one indented line for each message.
+func (s *Score) String() string {
+	buf := &bytes.Buffer{}
+	for _, bun := range s.Bundles {
+		fmt.Fprintf(buf, "%.6f\n", bun.Time.Seconds())
+		for _, msg := range bun.Messages {
+			fmt.Fprintf(buf, "    %s\n", describeMessage(msg))
+		}
+	}
+	return buf.String()
+}
+
+// Diff returns a diff of one score and another.
+// A diff is represented as a slice of pairs of strings.
+// The first string in each pair describes the score on the left (the receiver),
+// and the second string describes the other score.
+// If the returned slice is empty the scores contain the same bundles.
+func (s *Score) Diff(other *Score) [][2]string {
+	diffs := [][2]string{}
+	for i := 0; i < len(s.Bundles) || i < len(other.Bundles); i++ {
+		switch {
+		case i >= len(s.Bundles):
+			diffs = append(diffs, [2]string{
+				fmt.Sprintf("bundle %d is missing", i),
+				fmt.Sprintf("bundle %d is at %s", i, other.Bundles[i].Time),
+			})
+			continue
+		case i >= len(other.Bundles):
+			diffs = append(diffs, [2]string{
+				fmt.Sprintf("bundle %d is at %s", i, s.Bundles[i].Time),
+				fmt.Sprintf("bundle %d is missing", i),
+			})
+			continue
+		}
+		b1, b2 := s.Bundles[i], other.Bundles[i]
+		if b1.Time != b2.Time {
+			diffs = append(diffs, [2]string{
+				fmt.Sprintf("bundle %d is at %s", i, b1.Time),
+				fmt.Sprintf("bundle %d is at %s", i, b2.Time),
+			})
+		}
+		for j := 0; j < len(b1.Messages) || j < len(b2.Messages); j++ {
+			var (
+				m1, m2 = "missing", "missing"
+				d1, d2 []byte
+			)
+			if j < len(b1.Messages) {
+				m1, d1 = describeMessage(b1.Messages[j]), b1.Messages[j].Bytes()
+			}
+			if j < len(b2.Messages) {
+				m2, d2 = describeMessage(b2.Messages[j]), b2.Messages[j].Bytes()
+			}
+			if d1 == nil || d2 == nil || !bytes.Equal(d1, d2) {
+				diffs = append(diffs, [2]string{
+					fmt.Sprintf("bundle %d message %d is %s", i, j, m1),
+					fmt.Sprintf("bundle %d message %d is %s", i, j, m2),
+				})
+			}
+		}
+	}
+	return diffs
+}
+
+// describeMessage formats a message the way sclang prints a message in a score,
+// e.g. [ "/s_new", "foo", 1000, 1, 1 ].
+func describeMessage(msg osc.Message) string {
+	parts := []string{fmt.Sprintf("%q", msg.Address)}
+	for _, arg := range msg.Arguments {
+		parts = append(parts, describeArgument(arg))
+	}
+	return "[ " + strings.Join(parts, ", ") + " ]"
+}
+
+// describeArgument formats an OSC argument.
+func describeArgument(arg osc.Argument) string {
+	switch arg.Typetag() {
+	case 'i':
+		i, _ := arg.ReadInt32()
+		return strconv.FormatInt(int64(i), 10)
+	case 'f':
+		f, _ := arg.ReadFloat32()
+		return strconv.FormatFloat(float64(f), 'g', -1, 32)
+	case 's':
+		s, _ := arg.ReadString()
+		return fmt.Sprintf("%q", s)
+	case 'b':
+		b, _ := arg.ReadBlob()
+		return fmt.Sprintf("<%d bytes>", len(b))
+	}
+	return fmt.Sprintf("%v", arg)
+}
+
+// Bytes returns the score encoded as an NRT command file.
+func (s *Score) Bytes() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if _, err := s.WriteTo(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the score to w in the format scsynth uses for NRT command files.
+// Each bundle is preceded by its size in bytes as a big-endian int32.
+func (s *Score) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, bun := range s.Bundles {
+		data, err := bun.bytes()
+		if err != nil {
+			return n, err
+		}
+		if err := binary.Write(w, byteOrder, int32(len(data))); err != nil {
+			return n, err
+		}
+		n += 4
+
+		written, err := w.Write(data)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// bytes encodes a score bundle as an OSC bundle.
+// NRT time tags are relative to the start of the score.
+func (bun ScoreBundle) bytes() ([]byte, error) {
+	if bun.Time < 0 {
+		return nil, fmt.Errorf("bundle time must not be negative (got %s)", bun.Time)
+	}
+	buf := &bytes.Buffer{}
+	_, _ = buf.Write(osc.ToBytes("#bundle")) // Writes to a bytes.Buffer never fail.
+	_ = binary.Write(buf, byteOrder, durationToTimetag(bun.Time))
+
+	for _, msg := range bun.Messages {
+		data := msg.Bytes()
+		_ = binary.Write(buf, byteOrder, int32(len(data)))
+		_, _ = buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// durationToTimetag converts a duration to an NTP time tag.
+// The upper 32 bits are seconds and the lower 32 bits are fractions of a second.
+func durationToTimetag(d time.Duration) uint64 {
+	var (
+		secs = uint64(d / time.Second)
+		frac = (uint64(d%time.Second) << 32) / uint64(time.Second)
+	)
+	return secs<<32 | frac
+}
+
+// timetagToDuration converts an NTP time tag to a duration.
+func timetagToDuration(tt uint64) time.Duration {
+	var (
+		secs = time.Duration(tt>>32) * time.Second
+		frac = time.Duration(((tt&0xFFFFFFFF)*uint64(time.Second) + 1<<31) >> 32)
+	)
+	return secs + frac
+}
@@ -0,0 +1,78 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/scgolang/osc"
+)
+
+// ReadScore reads a score from an NRT command file
+// (e.g. one that was written by Score.WriteTo or by sclang's Score.write).
+// Bundles with the same time are combined into a single bundle.
+func ReadScore(r io.Reader) (*Score, error) {
+	score := NewScore()
+	for i := 0; ; i++ {
+		var size int32
+		if err := binary.Read(r, byteOrder, &size); err == io.EOF {
+			return score, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading size of bundle %d: %s", i, err)
+		}
+		if size < bundleHeaderSize {
+			return nil, fmt.Errorf("bundle %d is too small (%d bytes)", i, size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("reading bundle %d: %s", i, err)
+		}
+		bun, err := parseScoreBundle(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing bundle %d: %s", i, err)
+		}
+		score.Add(bun.Time, bun.Messages...)
+	}
+}
+
+// parseScoreBundle parses an encoded OSC bundle from an NRT command file.
+func parseScoreBundle(data []byte) (ScoreBundle, error) {
+	if !bytes.HasPrefix(data, osc.ToBytes("#bundle")) {
+		return ScoreBundle{}, fmt.Errorf("expected #bundle")
+	}
+	if len(data) < bundleHeaderSize {
+		return ScoreBundle{}, fmt.Errorf("bundle is too small (%d bytes)", len(data))
+	}
+	bun := ScoreBundle{
+		Time:     timetagToDuration(byteOrder.Uint64(data[8:16])),
+		Messages: []osc.Message{},
+	}
+	for rest := data[bundleHeaderSize:]; len(rest) > 0; {
+		if len(rest) < 4 {
+			return ScoreBundle{}, fmt.Errorf("truncated bundle element")
+		}
+		size := int(int32(byteOrder.Uint32(rest[:4])))
+		rest = rest[4:]
+
+		if size < 0 || size > len(rest) {
+			return ScoreBundle{}, fmt.Errorf("bundle element size %d is out of range", size)
+		}
+		if bytes.HasPrefix(rest, osc.ToBytes("#bundle")) {
+			// Flatten nested bundles, which scsynth executes at the time of the outer bundle.
+			nested, err := parseScoreBundle(rest[:size])
+			if err != nil {
+				return ScoreBundle{}, err
+			}
+			bun.Messages = append(bun.Messages, nested.Messages...)
+		} else {
+			msg, err := osc.ParseMessage(rest[:size], nil)
+			if err != nil {
+				return ScoreBundle{}, err
+			}
+			bun.Messages = append(bun.Messages, msg)
+		}
+		rest = rest[size:]
+	}
+	return bun, nil
+}
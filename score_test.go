@@ -0,0 +1,247 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestScoreAdd(t *testing.T) {
+	score := NewScore()
+	score.NodeFree(2*time.Second, 1000)
+	score.Synth(0, "foo", 1000, AddToTail, DefaultGroupID, map[string]float32{"freq": 440, "amp": 0.5})
+	score.NodeSet(time.Second, 1000, map[string]float32{"freq": 220})
+	score.Group(0, DefaultGroupID, AddToHead, RootNodeID)
+
+	if expected, got := 3, len(score.Bundles); expected != got {
+		t.Fatalf("expected %d bundles, got %d", expected, got)
+	}
+	for i, expected := range []struct {
+		time      time.Duration
+		addresses []string
+	}{
+		{0, []string{synthNewAddress, groupNewAddress}},
+		{time.Second, []string{nodeSetAddress}},
+		{2 * time.Second, []string{nodeFreeAddress}},
+	} {
+		bun := score.Bundles[i]
+		if bun.Time != expected.time {
+			t.Fatalf("bundle %d: expected time %s, got %s", i, expected.time, bun.Time)
+		}
+		if len(bun.Messages) != len(expected.addresses) {
+			t.Fatalf("bundle %d: expected %d messages, got %d", i, len(expected.addresses), len(bun.Messages))
+		}
+		for j, addr := range expected.addresses {
+			if got := bun.Messages[j].Address; got != addr {
+				t.Fatalf("bundle %d message %d: expected %s, got %s", i, j, addr, got)
+			}
+		}
+	}
+	// Controls are sorted by name.
+	if expected, got := "amp", score.Bundles[0].Messages[0].Arguments[4]; !got.Equal(osc.String(expected)) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := 2*time.Second, score.Duration(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestScoreBytes(t *testing.T) {
+	score := NewScore()
+	score.End(1500 * time.Millisecond)
+
+	msg := osc.Message{
+		Address:   controlBusSetAddress,
+		Arguments: osc.Arguments{osc.Int(0), osc.Int(0)},
+	}
+	bun := &bytes.Buffer{}
+	bun.Write(osc.ToBytes("#bundle"))
+	_ = binary.Write(bun, byteOrder, uint64(1)<<32|uint64(1)<<31)
+	_ = binary.Write(bun, byteOrder, int32(len(msg.Bytes())))
+	bun.Write(msg.Bytes())
+
+	expected := &bytes.Buffer{}
+	_ = binary.Write(expected, byteOrder, int32(bun.Len()))
+	expected.Write(bun.Bytes())
+
+	got, err := score.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected.Bytes(), got) {
+		t.Fatalf("expected %x, got %x", expected.Bytes(), got)
+	}
+	score.Add(-time.Second)
+	if _, err := score.Bytes(); err == nil {
+		t.Fatal("expected an error for a negative bundle time")
+	}
+}
+
+func TestScanNRTOutput(t *testing.T) {
+	var (
+		progress = []time.Duration{}
+		s        = &Server{NRTProgress: func(d time.Duration) { progress = append(progress, d) }}
+		output   = strings.Join([]string{
+			"start time 0",
+			"nextOSCPacket 0",
+			"nextOSCPacket 0.5",
+			"*** ERROR: open directory failed '/nope'",
+			"nextOSCPacket 2",
+		}, "\n")
+	)
+	warnings := s.scanNRTOutput(strings.NewReader(output))
+
+	if expected, got := 1, len(warnings); expected != got {
+		t.Fatalf("expected %d warnings, got %d", expected, got)
+	}
+	if expected, got := []time.Duration{0, 500 * time.Millisecond, 2 * time.Second}, progress; len(expected) != len(got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	} else {
+		for i := range expected {
+			if expected[i] != got[i] {
+				t.Fatalf("expected %v, got %v", expected, got)
+			}
+		}
+	}
+}
+
+func TestRenderNRTFormats(t *testing.T) {
+	s := &Server{}
+	if _, err := s.RenderNRT(NewScore(), "", "out.wav", 48000, "MP3", SampleInt16, ServerOptions{}); err == nil {
+		t.Fatal("expected an error for an unsupported header format")
+	}
+	if _, err := s.RenderNRT(NewScore(), "", "out.wav", 48000, HeaderWAV, "int12", ServerOptions{}); err == nil {
+		t.Fatal("expected an error for an unsupported sample format")
+	}
+}
+
+// TestRenderNRTExitStatus checks that only the exit status of scsynth decides
+// whether rendering failed.
+func TestRenderNRTExitStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	dir := t.TempDir()
+
+	for i, testcase := range []struct {
+		script string
+		fails  bool
+	}{
+		{"echo \"*** ERROR: open directory failed '/nope'\"; echo 'nextOSCPacket 0'", false},
+		{"echo 'FAILURE IN SERVER /s_new SynthDef not found'; exit 1", true},
+	} {
+		s := &Server{Path: writeScript(t, dir, "scsynth"+strconv.Itoa(i), testcase.script)}
+		warnings, err := s.RenderNRT(NewScore(), "", filepath.Join(dir, "out.wav"), 48000, HeaderWAV, SampleInt16, ServerOptions{})
+		if testcase.fails && err == nil {
+			t.Fatalf("testcase %d: expected an error", i)
+		} else if !testcase.fails && err != nil {
+			t.Fatalf("testcase %d: %s", i, err)
+		}
+		if expected, got := 1, len(warnings); expected != got {
+			t.Fatalf("testcase %d: expected %d warnings, got %v", i, expected, warnings)
+		}
+	}
+}
+
+func TestReadScore(t *testing.T) {
+	def := NewSynthdef("foo", func(p Params) Ugen {
+		return Out{Bus: C(0), Channels: SinOsc{}.Rate(AR)}.Rate(AR)
+	})
+	score := NewScore()
+	if err := score.SendDef(0, def); err != nil {
+		t.Fatal(err)
+	}
+	score.Synth(0, "foo", 1000, AddToTail, RootNodeID, map[string]float32{"freq": 440})
+	score.ReadBuffer(250*time.Millisecond, "/tmp/foo.wav", 1, 0, 1)
+	score.NodeSet(333*time.Millisecond, 1000, map[string]float32{"freq": 220})
+	score.End(3 * time.Second)
+
+	data, err := score.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadScore(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := score.Diff(got); len(diff) > 0 {
+		t.Fatalf("expected no diff, got %v", diff)
+	}
+	if _, err := ReadScore(bytes.NewReader(data[:len(data)-3])); err == nil {
+		t.Fatal("expected an error for a truncated score")
+	}
+}
+
+// TestReadScoreTruncatedNestedBundle checks that a nested bundle
+// without a time tag is an error.
+func TestReadScoreTruncatedNestedBundle(t *testing.T) {
+	var bun bytes.Buffer
+	bun.Write(osc.ToBytes("#bundle"))
+	bun.Write(make([]byte, 8)) // Time tag.
+	_ = binary.Write(&bun, byteOrder, int32(8))
+	bun.Write(osc.ToBytes("#bundle"))
+
+	var file bytes.Buffer
+	_ = binary.Write(&file, byteOrder, int32(bun.Len()))
+	file.Write(bun.Bytes())
+
+	if _, err := ReadScore(&file); err == nil {
+		t.Fatal("expected an error for a truncated nested bundle")
+	}
+}
+
+func TestScoreMergeShiftSlice(t *testing.T) {
+	s1 := NewScore()
+	s1.Synth(0, "foo", 1000, AddToTail, DefaultGroupID, nil)
+	s1.NodeFree(time.Second, 1000)
+
+	s2 := NewScore()
+	s2.Synth(0, "bar", 1001, AddToTail, DefaultGroupID, nil)
+	s2.NodeFree(2*time.Second, 1001)
+
+	merged := s1.Merge(s2)
+
+	expected := NewScore()
+	expected.Synth(0, "foo", 1000, AddToTail, DefaultGroupID, nil)
+	expected.Synth(0, "bar", 1001, AddToTail, DefaultGroupID, nil)
+	expected.NodeFree(time.Second, 1000)
+	expected.NodeFree(2*time.Second, 1001)
+
+	if diff := expected.Diff(merged); len(diff) > 0 {
+		t.Fatalf("merge: expected no diff, got %v", diff)
+	}
+	if expected, got := 2, len(s1.Bundles); expected != got {
+		t.Fatalf("merge modified the receiver: expected %d bundles, got %d", expected, got)
+	}
+	shifted := merged.Slice(time.Second, 3*time.Second).Shift(-time.Second)
+
+	expected = NewScore()
+	expected.NodeFree(0, 1000)
+	expected.NodeFree(time.Second, 1001)
+
+	if diff := expected.Diff(shifted); len(diff) > 0 {
+		t.Fatalf("slice and shift: expected no diff, got %v", diff)
+	}
+}
+
+func TestScoreString(t *testing.T) {
+	score := NewScore()
+	score.Synth(0, "foo", 1000, AddToTail, DefaultGroupID, map[string]float32{"freq": 440.5})
+	score.End(1500 * time.Millisecond)
+
+	expected := `0.000000
+    [ "/s_new", "foo", 1000, 1, 1, "freq", 440.5 ]
+1.500000
+    [ "/c_set", 0, 0 ]
+`
+	if got := score.String(); expected != got {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
@@ -0,0 +1,90 @@
+// Package sctest provides a SuperCollider server for tests.
+//
+// StartServer runs scsynth if it is installed, and otherwise falls back
+// to sc.FakeServer, so the same tests run on machines with SuperCollider
+// and on machines without it.
+package sctest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/scgolang/sc"
+)
+
+// DefaultTimeout is the default timeout for starting a server and connecting a client.
+const DefaultTimeout = 5 * time.Second
+
+// Server is a server that was started for a test.
+type Server struct {
+	// Client is connected to the server.
+	Client *sc.Client
+
+	// Server is the scsynth server.
+	// It is nil if scsynth is not installed.
+	Server *sc.Server
+
+	// Fake is the fake server that is used when scsynth is not installed.
+	// It is nil if scsynth is installed.
+	Fake *sc.FakeServer
+}
+
+// IsFake returns true if the server is a fake server.
+// Tests that need to hear sound or rely on behavior that
+// the fake server doesn't implement should skip if it returns true.
+func (s *Server) IsFake() bool {
+	return s.Fake != nil
+}
+
+// StartServer starts scsynth on a free UDP port without any hardware
+// input or output channels, and connects a client to it.
+// If scsynth is not installed it starts a fake server instead.
+// The server and client are closed when the test finishes.
+func StartServer(t testing.TB) *Server {
+	return StartServerOptions(t, sc.ServerOptions{
+		NumInputBusChannels:  sc.ChannelCount(0),
+		NumOutputBusChannels: sc.ChannelCount(0),
+	})
+}
+
+// StartServerOptions is like StartServer but it starts scsynth with the provided options.
+func StartServerOptions(t testing.TB, opts sc.ServerOptions) *Server {
+	t.Helper()
+
+	srv, client, cleanup, err := sc.StartLocal(&sc.Server{Network: "udp", Options: opts}, DefaultTimeout)
+	if err == sc.ErrNoScsynth {
+		return startFakeServer(t, opts)
+	}
+	if err != nil {
+		t.Fatalf("starting scsynth: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			t.Errorf("stopping scsynth: %s", err)
+		}
+	})
+	return &Server{Client: client, Server: srv}
+}
+
+// startFakeServer starts a fake server and connects a client to it.
+func startFakeServer(t testing.TB, opts sc.ServerOptions) *Server {
+	t.Helper()
+
+	fs, err := sc.NewFakeServer(net.JoinHostPort("127.0.0.1", "0"))
+	if err != nil {
+		t.Fatalf("starting fake server: %s", err)
+	}
+	t.Cleanup(func() { _ = fs.Close() }) // Best effort.
+
+	client, err := sc.NewClient("udp", net.JoinHostPort("127.0.0.1", "0"), fs.Addr(), DefaultTimeout)
+	if err != nil {
+		t.Fatalf("connecting to fake server: %s", err)
+	}
+	t.Cleanup(func() { _ = client.Close() }) // Best effort.
+
+	if err := client.SetOptions(opts); err != nil {
+		t.Fatalf("setting client options: %s", err)
+	}
+	return &Server{Client: client, Fake: fs}
+}
@@ -0,0 +1,32 @@
+package sctest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scgolang/sc"
+)
+
+func TestStartServer(t *testing.T) {
+	s := StartServer(t)
+
+	def := sc.NewSynthdef("sctest_sine", func(p sc.Params) sc.Ugen {
+		return sc.Out{Bus: sc.C(0), Channels: sc.SinOsc{}.Rate(sc.AR)}.Rate(sc.AR)
+	})
+	if err := s.Client.SendDef(def); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Client.Synth("sctest_sine", s.Client.NextSynthID(), sc.AddToTail, sc.RootNodeID, nil); err != nil {
+		t.Fatal(err)
+	}
+	status, err := s.Client.Status(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.NumSynths < 1 {
+		t.Fatalf("expected at least 1 synth, got %d", status.NumSynths)
+	}
+	if s.IsFake() != (s.Server == nil) {
+		t.Fatal("expected exactly one of Server and Fake to be set")
+	}
+}
@@ -2,11 +2,15 @@ package sc
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -15,64 +19,148 @@ import (
 // DefaultServerPort is the default listening port for scsynth.
 const DefaultServerPort = 57120
 
+// Server programs.
+const (
+	// ProgramScsynth is the default SuperCollider server.
+	ProgramScsynth = "scsynth"
+
+	// ProgramSupernova is the multi-threaded SuperCollider server.
+	// It supports parallel groups (see Client.ParGroup).
+	ProgramSupernova = "supernova"
+)
+
+// Environment variables that contain the path to the server program.
+// They take precedence over PATH and the usual install directories.
+const (
+	ScsynthEnv   = "SCSYNTH"
+	SupernovaEnv = "SUPERNOVA"
+)
+
 // ErrNoScsynth happens when you try to start a SuperCollider
 // server but do not have an scsynth executable in your PATH.
-var ErrNoScsynth = errors.New("Please install scsynth somewhere in your PATH.")
+var ErrNoScsynth = errors.New("Please install scsynth somewhere in your PATH or set " + ScsynthEnv + ".")
+
+// ErrNoSupernova happens when you try to start supernova
+// but do not have a supernova executable in your PATH.
+var ErrNoSupernova = errors.New("Please install supernova somewhere in your PATH or set " + SupernovaEnv + ".")
 
 // Server represents a running instance of scsynth.
 type Server struct {
 	*exec.Cmd
 
-	Network      string
-	Port         int
+	Network string
+
+	// Port is the port the server listens on.
+	// If it is 0 a free port is chosen when the server is started,
+	// and Port is set to the chosen port.
+	Port int
+
 	StartTimeout time.Duration
+
+	// Program is the server program that is run,
+	// either ProgramScsynth (the default) or ProgramSupernova.
+	Program string
+
+	// Path is the path to the server program.
+	// If it is empty the path in ScsynthEnv (or SupernovaEnv) is used,
+	// then the program is searched for in the directories in PATH
+	// and the directories SuperCollider is usually installed in.
+	Path string
+
+	// Options are the command line options for scsynth.
+	// They are validated before scsynth is started.
+	// Pass the same options to Client.SetOptions so that
+	// the client's allocators match the server.
+	Options ServerOptions
+
+	// NRTProgress, if not nil, is called by RenderNRT with the
+	// time of each bundle in the score as scsynth reaches it.
+	NRTProgress func(time.Duration)
 }
 
-// getServerPath gets the path to the scsynth executable.
+// getServerPath gets the path to the scsynth (or supernova) executable.
 func (s *Server) getServerPath() (string, error) {
-	for _, file := range strings.Split(ServerPath, ":") {
-		ok, err := isExecutable(file)
+	if s.Path != "" {
+		return exec.LookPath(s.Path)
+	}
+	program, envVar, errNotFound := ProgramScsynth, ScsynthEnv, ErrNoScsynth
+	if s.Program == ProgramSupernova {
+		program, envVar, errNotFound = ProgramSupernova, SupernovaEnv, ErrNoSupernova
+	}
+	if file := os.Getenv(envVar); file != "" {
+		path, err := exec.LookPath(file)
 		if err != nil {
-			return "", err
-		}
-		if ok {
-			return file, nil
+			return "", fmt.Errorf("%s is set but is not an executable: %v", envVar, err)
 		}
+		return path, nil
 	}
-
-	path, hasPath := os.LookupEnv("PATH")
-	if !hasPath {
-		return "", ErrNoScsynth
+	if path, err := exec.LookPath(program); err == nil {
+		return path, nil
 	}
-
-	for _, file := range strings.Split(path, ":") {
-		ok, err := isExecutable(file)
+	for _, pattern := range installDirs {
+		dirs, err := filepath.Glob(pattern)
 		if err != nil {
 			return "", err
 		}
-		if ok {
-			return file, nil
+		for _, dir := range dirs {
+			if path, err := exec.LookPath(filepath.Join(dir, program)); err == nil {
+				return path, nil
+			}
 		}
 	}
-	return "", ErrNoScsynth
+	return "", errNotFound
 }
 
-// isExecutable returns true if the provided file is executable, false otherwise.
-// It also returns any error that occurs while trying to read the file.
-func isExecutable(filename string) (bool, error) {
-	info, err := os.Stat(filename)
+// versionRegexp matches the version that scsynth and supernova print with -v.
+var versionRegexp = regexp.MustCompile(`\d+\.\d+[\w.\-]*`)
+
+// Version returns the version of the server program, e.g. "3.13.0".
+// It runs the program that Start would run with -v, so it can be used
+// to check the program before the server is started.
+func (s *Server) Version() (string, error) {
+	serverPath, err := s.getServerPath()
 	if err != nil {
-		return false, err
+		return "", err
 	}
-	mode := info.Mode()
-	return ((mode & 0x01) | (mode & 0x08) | (mode & 0x40)) != 0, nil
+	out, err := exec.Command(serverPath, "-v").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s -v: %v: %s", serverPath, err, bytes.TrimSpace(out))
+	}
+	version := versionRegexp.Find(out)
+	if version == nil {
+		return "", fmt.Errorf("%s -v: could not find a version in %q", serverPath, bytes.TrimSpace(out))
+	}
+	return string(version), nil
 }
 
 // args gets the command line args to scsynth
 func (s *Server) args() ([]string, error) {
+	if err := s.Options.Validate(); err != nil {
+		return nil, err
+	}
+	if s.Options.Password != "" && s.Network != "tcp" {
+		return nil, errors.New("a password can only be used with the tcp network")
+	}
+	switch s.Program {
+	case "", ProgramScsynth:
+		if s.Options.Threads > 0 {
+			return nil, errors.New("Threads can only be used with supernova")
+		}
+	case ProgramSupernova:
+	default:
+		return nil, fmt.Errorf("unrecognized server program: %s", s.Program)
+	}
+
 	// Get the port.
-	if s.Port <= 0 {
-		s.Port = DefaultServerPort
+	if s.Port < 0 {
+		return nil, fmt.Errorf("invalid port: %d", s.Port)
+	}
+	if s.Port == 0 {
+		port, err := freePort(s.Network, s.host())
+		if err != nil {
+			return nil, err
+		}
+		s.Port = port
 	}
 	portArg := strconv.FormatInt(int64(s.Port), 10)
 
@@ -88,26 +176,69 @@ func (s *Server) args() ([]string, error) {
 		args = append(args, "-t", portArg)
 	}
 
-	return args, nil
+	return append(args, s.Options.Args()...), nil
 }
 
 const serverReadyMessage = "server ready"
 
-// Start starts a new instance of scsynth.
-// If the server doesn't print a line containing ServerReadyMessage
-// within the timeout then ErrTimeout is returned.
-func (s *Server) Start(timeout time.Duration) (io.ReadCloser, io.ReadCloser, error) {
+// Addr returns the address clients on this machine can use to reach the server.
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.host(), strconv.Itoa(s.Port))
+}
+
+// host returns the host clients on this machine can use to reach the server.
+func (s *Server) host() string {
+	if host := s.Options.BindAddress; host != "" && host != "0.0.0.0" {
+		return host
+	}
+	return "127.0.0.1"
+}
+
+// freePort returns a port that is not in use on the provided host.
+func freePort(network, host string) (int, error) {
+	switch network {
+	case "udp":
+		conn, err := net.ListenPacket(network, net.JoinHostPort(host, "0"))
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = conn.Close() }() // Best effort.
+		return conn.LocalAddr().(*net.UDPAddr).Port, nil
+	case "tcp":
+		ln, err := net.Listen(network, net.JoinHostPort(host, "0"))
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = ln.Close() }() // Best effort.
+		return ln.Addr().(*net.TCPAddr).Port, nil
+	}
+	return 0, fmt.Errorf("unrecognized network type: %s", network)
+}
+
+// command creates the command that runs scsynth.
+func (s *Server) command() (*exec.Cmd, error) {
 	args, err := s.args()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-
 	serverPath, err := s.getServerPath()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return exec.Command(serverPath, args...), nil
+}
 
-	s.Cmd = exec.Command(serverPath, args...)
+// Start starts a new instance of scsynth.
+// If the server doesn't print a line containing ServerReadyMessage
+// within the timeout then it is killed and ErrTimeout is returned.
+// See Supervisor for a way to run scsynth that detects readiness
+// with /status and restarts scsynth if it fails.
+func (s *Server) Start(timeout time.Duration) (io.ReadCloser, io.ReadCloser, error) {
+	cmd, err := s.command()
+	if err != nil {
+		return nil, nil, err
+	}
+	s.Cmd = cmd
 	stdout, err := s.Cmd.StdoutPipe()
 	if err != nil {
 		return nil, nil, err
@@ -121,21 +252,31 @@ func (s *Server) Start(timeout time.Duration) (io.ReadCloser, io.ReadCloser, err
 	}
 
 	// Wait until the server prints a ready message.
-	var (
-		scanner = bufio.NewScanner(stdout)
-		start   = time.Now()
-	)
-	for i := 0; scanner.Scan(); i++ {
-		if time.Now().Sub(start) > timeout {
-			return nil, nil, ErrTimeout
+	ready := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if strings.Contains(scanner.Text(), serverReadyMessage) {
+				ready <- nil
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			ready <- err
+			return
 		}
-		if strings.Index(scanner.Text(), serverReadyMessage) == -1 {
-			continue
-		} else {
-			break
+		ready <- errors.New("scsynth exited before it was ready")
+	}()
+	select {
+	case err := <-ready:
+		if err != nil {
+			return nil, nil, err
 		}
+		return stdout, stderr, nil
+	case <-time.After(timeout):
+		_ = s.Process.Kill() // Best effort.
+		return nil, nil, ErrTimeout
 	}
-	return stdout, stderr, scanner.Err()
 }
 
 // Stop stops a running server.
@@ -3,4 +3,16 @@
 package sc
 
 // ServerPath is the path to scsynth on darwin systems.
+//
+// Deprecated: Start looks for scsynth in Server.Path, ScsynthEnv, PATH
+// and the usual install directories, so there is no need to use this.
 const ServerPath = "/Applications/SuperCollider.app/Contents/Resources/scsynth"
+
+// installDirs are the directories scsynth and supernova
+// are usually installed in on darwin systems.
+var installDirs = []string{
+	"/Applications/SuperCollider.app/Contents/Resources",
+	"/Applications/SuperCollider/SuperCollider.app/Contents/Resources",
+	"/opt/homebrew/bin",
+	"/usr/local/bin",
+}
@@ -3,4 +3,16 @@
 package sc
 
 // ServerPath is the path the scsynth executable on linux systems.
+//
+// Deprecated: Start looks for scsynth in Server.Path, ScsynthEnv, PATH
+// and the usual install directories, so there is no need to use this.
 const ServerPath = "/usr/bin/scsynth:/usr/local/bin/scsynth"
+
+// installDirs are the directories scsynth and supernova
+// are usually installed in on linux systems.
+var installDirs = []string{
+	"/usr/bin",
+	"/usr/local/bin",
+	"/opt/SuperCollider/bin",
+	"/opt/local/bin",
+}
@@ -0,0 +1,57 @@
+package sc
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// StartLocal starts a server on this machine and connects a client to it.
+// If s is nil a UDP server is started on a free port.
+// Set s.Port to 0 to have several local servers (or a server next to
+// another application) without port collisions.
+// A UDP client listens on a free port on the loopback interface,
+// and a TCP client sends s.Options.Password when it connects.
+// The client's allocators are sized with the server's options.
+// The returned func closes the client and stops the server.
+func StartLocal(s *Server, timeout time.Duration) (*Server, *Client, func() error, error) {
+	if s == nil {
+		s = &Server{Network: "udp"}
+	}
+	if s.Network != "udp" && s.Network != "tcp" {
+		return nil, nil, nil, fmt.Errorf("unsupported network for a local client: %s", s.Network)
+	}
+	sv := NewSupervisor(s)
+	sv.Policy = RestartNever
+	sv.ReadyTimeout = timeout
+
+	if err := sv.Start(); err != nil {
+		return nil, nil, nil, err
+	}
+	c, err := newLocalClient(s, timeout)
+	if err != nil {
+		_ = sv.Stop() // Best effort.
+		return nil, nil, nil, err
+	}
+	if err := c.SetOptions(s.Options); err != nil {
+		_ = c.Close() // Best effort.
+		_ = sv.Stop() // Best effort.
+		return nil, nil, nil, err
+	}
+	cleanup := func() error {
+		cerr := c.Close()
+		if err := sv.Stop(); err != nil {
+			return err
+		}
+		return cerr
+	}
+	return s, c, cleanup, nil
+}
+
+// newLocalClient connects a client to a server that was started by StartLocal.
+func newLocalClient(s *Server, timeout time.Duration) (*Client, error) {
+	if s.Network == "tcp" {
+		return NewTCPClient(s.Addr(), s.Options.Password, timeout)
+	}
+	return NewClient(s.Network, net.JoinHostPort(s.host(), "0"), s.Addr(), timeout)
+}
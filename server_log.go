@@ -0,0 +1,78 @@
+package sc
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// Log levels.
+const (
+	LogInfo    = "info"
+	LogWarning = "warning"
+	LogError   = "error"
+)
+
+// Log sources.
+const (
+	LogStdout     = "stdout"
+	LogStderr     = "stderr"
+	LogSupervisor = "supervisor"
+)
+
+// LogEvent is a structured line of scsynth output,
+// or an event generated by a Supervisor.
+type LogEvent struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"` // Source is LogStdout, LogStderr, or LogSupervisor.
+	Level  string    `json:"level"`  // Level is LogInfo, LogWarning, or LogError.
+
+	// Command is the OSC address of the command that failed,
+	// for lines like "FAILURE IN SERVER /s_new SynthDef not found".
+	Command string `json:"command,omitempty"`
+
+	// Line is the text of the line.
+	Line string `json:"line"`
+}
+
+// logFailurePrefix is the prefix scsynth prints when a command fails.
+const logFailurePrefix = "FAILURE IN SERVER "
+
+// parseLogLine parses a line of scsynth output.
+func parseLogLine(source, line string) LogEvent {
+	ev := LogEvent{
+		Time:   time.Now(),
+		Source: source,
+		Level:  LogInfo,
+		Line:   line,
+	}
+	switch lower := strings.ToLower(line); {
+	case strings.HasPrefix(line, logFailurePrefix):
+		ev.Level = LogError
+		if fields := strings.Fields(strings.TrimPrefix(line, logFailurePrefix)); len(fields) > 0 {
+			ev.Command = fields[0]
+		}
+	case strings.Contains(lower, "exception in real time"),
+		strings.Contains(line, "FAILURE"),
+		strings.Contains(lower, "error"):
+		ev.Level = LogError
+	case strings.Contains(lower, "warning"),
+		strings.HasPrefix(lower, "late "):
+		ev.Level = LogWarning
+	}
+	return ev
+}
+
+// scanLog parses every line that is read from r and passes it to f.
+// It returns when r returns an error (e.g. when the process exits).
+func scanLog(source string, r io.Reader, f func(LogEvent)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if f != nil {
+			f(parseLogLine(source, scanner.Text()))
+		}
+	}
+	_, _ = io.Copy(ioutil.Discard, r) // Don't block scsynth if the scanner failed.
+}
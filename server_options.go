@@ -0,0 +1,231 @@
+package sc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Default values of scsynth's command line options.
+// See http://doc.sccode.org/Classes/ServerOptions.html.
+const (
+	DefaultBlockSize             = 64
+	DefaultNumAudioBusChannels   = 1024
+	DefaultNumControlBusChannels = 16384
+	DefaultNumInputBusChannels   = 8
+	DefaultNumOutputBusChannels  = 8
+	DefaultMaxNodes              = 1024
+	DefaultMaxSynthdefs          = 1024
+	DefaultNumBuffers            = 1024
+	DefaultRealtimeMemorySize    = 8192
+	DefaultNumWireBuffers        = 64
+	DefaultMaxLogins             = 64
+)
+
+// ServerOptions are the command line options of scsynth.
+// The zero value of every field means that scsynth's default is used.
+// Options that can be set to zero are pointers, see Int.
+type ServerOptions struct {
+	// BlockSize is the number of samples in one control period (-z).
+	BlockSize int
+
+	// SampleRate is the hardware sample rate (-S).
+	SampleRate int
+
+	// HardwareBufferSize is the size of the hardware buffer in samples (-Z).
+	HardwareBufferSize int
+
+	// NumAudioBusChannels is the number of audio bus channels (-a).
+	// This includes the hardware input and output channels.
+	NumAudioBusChannels int
+
+	// NumControlBusChannels is the number of control bus channels (-c).
+	NumControlBusChannels int
+
+	// NumInputBusChannels is the number of hardware input channels (-i).
+	// Use ChannelCount(0) to start scsynth without any hardware inputs.
+	NumInputBusChannels *int
+
+	// NumOutputBusChannels is the number of hardware output channels (-o).
+	// Use ChannelCount(0) to start scsynth without any hardware outputs.
+	NumOutputBusChannels *int
+
+	// MaxNodes is the maximum number of nodes (-n).
+	MaxNodes int
+
+	// MaxSynthdefs is the maximum number of synthdefs (-d).
+	MaxSynthdefs int
+
+	// NumBuffers is the number of sample buffers (-b).
+	NumBuffers int
+
+	// RealtimeMemorySize is the size of the real time memory pool in kilobytes (-m).
+	RealtimeMemorySize int
+
+	// NumWireBuffers is the maximum number of buffers used to connect ugens (-w).
+	NumWireBuffers int
+
+	// InputDevice is the name of the hardware input device (-H).
+	InputDevice string
+
+	// OutputDevice is the name of the hardware output device (-H).
+	// If it is empty then InputDevice is used for input and output.
+	OutputDevice string
+
+	// UgenPluginsPath is a list of directories that contain ugen plugins (-U).
+	UgenPluginsPath []string
+
+	// DontLoadSynthdefs prevents scsynth from loading the synthdefs
+	// in its synthdef directory when it starts (-D 0).
+	DontLoadSynthdefs bool
+
+	// Password is the session password that TCP clients must send (-p).
+	Password string
+
+	// BindAddress is the address scsynth listens on (-B).
+	BindAddress string
+
+	// MaxLogins is the maximum number of clients that can be logged in (-l).
+	MaxLogins int
+
+	// Threads is the number of audio threads (-T).
+	// It is only supported by supernova, and 0 means one thread per core.
+	Threads int
+}
+
+// ChannelCount returns a pointer to a number of channels.
+// It is used to set NumInputBusChannels and NumOutputBusChannels,
+// which are pointers so that they can be set to zero.
+func ChannelCount(n int) *int {
+	return &n
+}
+
+// Args returns the command line args for scsynth.
+// It does not include the -u or -t port args.
+func (opts ServerOptions) Args() []string {
+	args := []string{}
+
+	for _, opt := range []struct {
+		flag string
+		val  *int
+	}{
+		{"-z", positive(opts.BlockSize)},
+		{"-S", positive(opts.SampleRate)},
+		{"-Z", positive(opts.HardwareBufferSize)},
+		{"-a", positive(opts.NumAudioBusChannels)},
+		{"-c", positive(opts.NumControlBusChannels)},
+		{"-i", opts.NumInputBusChannels},
+		{"-o", opts.NumOutputBusChannels},
+		{"-n", positive(opts.MaxNodes)},
+		{"-d", positive(opts.MaxSynthdefs)},
+		{"-b", positive(opts.NumBuffers)},
+		{"-m", positive(opts.RealtimeMemorySize)},
+		{"-w", positive(opts.NumWireBuffers)},
+		{"-l", positive(opts.MaxLogins)},
+		{"-T", positive(opts.Threads)},
+	} {
+		if opt.val != nil {
+			args = append(args, opt.flag, strconv.Itoa(*opt.val))
+		}
+	}
+	if opts.InputDevice != "" || opts.OutputDevice != "" {
+		args = append(args, "-H", opts.InputDevice)
+		if opts.OutputDevice != "" {
+			args = append(args, opts.OutputDevice)
+		}
+	}
+	if len(opts.UgenPluginsPath) > 0 {
+		args = append(args, "-U", strings.Join(opts.UgenPluginsPath, ":"))
+	}
+	if opts.DontLoadSynthdefs {
+		args = append(args, "-D", "0")
+	}
+	if opts.Password != "" {
+		args = append(args, "-p", opts.Password)
+	}
+	if opts.BindAddress != "" {
+		args = append(args, "-B", opts.BindAddress)
+	}
+	return args
+}
+
+// Validate returns an error if the options can not be used to start scsynth.
+func (opts ServerOptions) Validate() error {
+	for _, opt := range []struct {
+		name string
+		val  int
+	}{
+		{"BlockSize", opts.BlockSize},
+		{"SampleRate", opts.SampleRate},
+		{"HardwareBufferSize", opts.HardwareBufferSize},
+		{"NumAudioBusChannels", opts.NumAudioBusChannels},
+		{"NumControlBusChannels", opts.NumControlBusChannels},
+		{"NumInputBusChannels", withDefault(opts.NumInputBusChannels, 0)},
+		{"NumOutputBusChannels", withDefault(opts.NumOutputBusChannels, 0)},
+		{"MaxNodes", opts.MaxNodes},
+		{"MaxSynthdefs", opts.MaxSynthdefs},
+		{"NumBuffers", opts.NumBuffers},
+		{"RealtimeMemorySize", opts.RealtimeMemorySize},
+		{"NumWireBuffers", opts.NumWireBuffers},
+		{"MaxLogins", opts.MaxLogins},
+		{"Threads", opts.Threads},
+	} {
+		if opt.val < 0 {
+			return fmt.Errorf("%s must not be negative (got %d)", opt.name, opt.val)
+		}
+	}
+	if bs := opts.BlockSize; bs > 0 && bs&(bs-1) != 0 {
+		return fmt.Errorf("BlockSize must be a power of two (got %d)", bs)
+	}
+	if hw, numAudio := opts.numInputBusChannels()+opts.numOutputBusChannels(), opts.numAudioBusChannels(); hw > numAudio {
+		return fmt.Errorf("NumAudioBusChannels (%d) must be at least the number of hardware input and output channels (%d)", numAudio, hw)
+	}
+	if opts.OutputDevice != "" && opts.InputDevice == "" {
+		return fmt.Errorf("InputDevice must be set if OutputDevice is set")
+	}
+	if strings.ContainsAny(opts.Password, " \t\n") {
+		return fmt.Errorf("Password must not contain whitespace")
+	}
+	return nil
+}
+
+// numAudioBusChannels returns the number of audio bus channels.
+func (opts ServerOptions) numAudioBusChannels() int {
+	return withDefault(positive(opts.NumAudioBusChannels), DefaultNumAudioBusChannels)
+}
+
+// numControlBusChannels returns the number of control bus channels.
+func (opts ServerOptions) numControlBusChannels() int {
+	return withDefault(positive(opts.NumControlBusChannels), DefaultNumControlBusChannels)
+}
+
+// numInputBusChannels returns the number of hardware input channels.
+func (opts ServerOptions) numInputBusChannels() int {
+	return withDefault(opts.NumInputBusChannels, DefaultNumInputBusChannels)
+}
+
+// numOutputBusChannels returns the number of hardware output channels.
+func (opts ServerOptions) numOutputBusChannels() int {
+	return withDefault(opts.NumOutputBusChannels, DefaultNumOutputBusChannels)
+}
+
+// numBuffers returns the number of buffers.
+func (opts ServerOptions) numBuffers() int {
+	return withDefault(positive(opts.NumBuffers), DefaultNumBuffers)
+}
+
+// positive returns a pointer to val if it is positive, otherwise it returns nil.
+func positive(val int) *int {
+	if val > 0 {
+		return &val
+	}
+	return nil
+}
+
+// withDefault returns *val if val is not nil, otherwise it returns def.
+func withDefault(val *int, def int) int {
+	if val != nil {
+		return *val
+	}
+	return def
+}
@@ -0,0 +1,149 @@
+package sc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServerOptionsArgs(t *testing.T) {
+	for i, testcase := range []struct {
+		opts     ServerOptions
+		expected []string
+	}{
+		{ServerOptions{}, []string{}},
+		{
+			ServerOptions{BlockSize: 128, SampleRate: 48000, NumBuffers: 4096, NumAudioBusChannels: 256},
+			[]string{"-z", "128", "-S", "48000", "-a", "256", "-b", "4096"},
+		},
+		{
+			ServerOptions{InputDevice: "in", OutputDevice: "out", DontLoadSynthdefs: true},
+			[]string{"-H", "in", "out", "-D", "0"},
+		},
+		{
+			ServerOptions{NumInputBusChannels: ChannelCount(0), NumOutputBusChannels: ChannelCount(2)},
+			[]string{"-i", "0", "-o", "2"},
+		},
+		{
+			ServerOptions{UgenPluginsPath: []string{"/a", "/b"}, Password: "secret", BindAddress: "127.0.0.1", MaxLogins: 4},
+			[]string{"-l", "4", "-U", "/a:/b", "-p", "secret", "-B", "127.0.0.1"},
+		},
+	} {
+		if expected, got := testcase.expected, testcase.opts.Args(); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("testcase %d: expected %v, got %v", i, expected, got)
+		}
+	}
+}
+
+func TestServerOptionsValidate(t *testing.T) {
+	for i, testcase := range []struct {
+		opts  ServerOptions
+		valid bool
+	}{
+		{ServerOptions{}, true},
+		{ServerOptions{BlockSize: 32, NumAudioBusChannels: 16}, true},
+		{ServerOptions{BlockSize: 48}, false},
+		{ServerOptions{NumBuffers: -1}, false},
+		{ServerOptions{NumAudioBusChannels: 8}, false},
+		{ServerOptions{NumAudioBusChannels: 8, NumInputBusChannels: ChannelCount(2), NumOutputBusChannels: ChannelCount(2)}, true},
+		{ServerOptions{OutputDevice: "out"}, false},
+		{ServerOptions{NumInputBusChannels: ChannelCount(0), NumAudioBusChannels: 8}, true},
+		{ServerOptions{NumOutputBusChannels: ChannelCount(-1)}, false},
+		{ServerOptions{Password: "two words"}, false},
+	} {
+		if err := testcase.opts.Validate(); testcase.valid && err != nil {
+			t.Fatalf("testcase %d: expected options to be valid, got %s", i, err)
+		} else if !testcase.valid && err == nil {
+			t.Fatalf("testcase %d: expected options to be invalid", i)
+		}
+	}
+}
+
+func TestServerArgs(t *testing.T) {
+	s := &Server{Network: "udp", Port: 57130, Options: ServerOptions{MaxNodes: 2048}}
+	args, err := s.args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []string{"-u", "57130", "-n", "2048"}, args; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	s.Options.Password = "secret"
+	if _, err := s.args(); err == nil {
+		t.Fatal("expected an error for a password with udp")
+	}
+}
+
+func TestClientAllocators(t *testing.T) {
+	c := &Client{}
+	if err := c.SetOptions(ServerOptions{
+		NumAudioBusChannels:   12,
+		NumControlBusChannels: 4,
+		NumInputBusChannels:   ChannelCount(2),
+		NumOutputBusChannels:  ChannelCount(2),
+		NumBuffers:            2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Audio buses start after the hardware channels.
+	a1, err := c.AllocAudioBus(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(4), a1; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	a2, err := c.AllocAudioBus(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(6), a2; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, err := c.AllocAudioBus(4); err == nil {
+		t.Fatal("expected an error when the audio buses are exhausted")
+	}
+	if err := c.FreeAudioBus(a1, 2); err != nil {
+		t.Fatal(err)
+	}
+	a3, err := c.AllocAudioBus(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := a1, a3; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, err := c.AllocControlBus(5); err == nil {
+		t.Fatal("expected an error when allocating more control buses than the server has")
+	}
+	for expected := int32(0); expected < 2; expected++ {
+		got, err := c.AllocBufferNum()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected != got {
+			t.Fatalf("expected %d, got %d", expected, got)
+		}
+	}
+	if _, err := c.AllocBufferNum(); err == nil {
+		t.Fatal("expected an error when the buffers are exhausted")
+	}
+	if err := c.SetOptions(ServerOptions{BlockSize: 3}); err == nil {
+		t.Fatal("expected an error for invalid options")
+	}
+}
+
+func TestServerArgsSupernova(t *testing.T) {
+	s := &Server{Network: "udp", Port: 57130, Options: ServerOptions{Threads: 4}}
+	if _, err := s.args(); err == nil {
+		t.Fatal("expected an error for threads with scsynth")
+	}
+	s.Program = ProgramSupernova
+
+	args, err := s.args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []string{"-u", "57130", "-T", "4"}, args; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,10 @@
+// +build !linux,!darwin,!windows
+
+package sc
+
+// installDirs are the directories scsynth and supernova
+// are usually installed in on other systems.
+var installDirs = []string{
+	"/usr/local/bin",
+	"/usr/bin",
+}
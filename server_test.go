@@ -0,0 +1,128 @@
+package sc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerFreePort(t *testing.T) {
+	for _, network := range []string{"udp", "tcp"} {
+		s := &Server{Network: network}
+		args, err := s.args()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Port == 0 {
+			t.Fatalf("%s: expected a port to be chosen", network)
+		}
+		if expected, got := strconv.Itoa(s.Port), args[1]; expected != got {
+			t.Fatalf("%s: expected port arg %s, got %s", network, expected, got)
+		}
+		if expected, got := "127.0.0.1:"+strconv.Itoa(s.Port), s.Addr(); expected != got {
+			t.Fatalf("%s: expected %s, got %s", network, expected, got)
+		}
+	}
+	if _, err := (&Server{Network: "udp", Port: -1}).args(); err == nil {
+		t.Fatal("expected an error for a negative port")
+	}
+	if _, err := (&Server{Network: "sctp"}).args(); err == nil {
+		t.Fatal("expected an error for an unrecognized network")
+	}
+}
+
+func TestStartLocalNetwork(t *testing.T) {
+	if _, _, _, err := StartLocal(&Server{Network: "sctp"}, time.Second); err == nil {
+		t.Fatal("expected an error for an unrecognized network")
+	}
+}
+
+func TestServerPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	var (
+		dir     = t.TempDir()
+		scsynth = writeScript(t, dir, ProgramScsynth, "echo 'scsynth 3.13.0 (Built from tag Version-3.13.0)'")
+	)
+	t.Setenv(ScsynthEnv, "")
+	t.Setenv("PATH", strings.Join([]string{filepath.Join(dir, "missing"), dir}, string(os.PathListSeparator)))
+
+	// PATH directories are joined with the program name,
+	// and directories that don't exist are skipped.
+	path, err := (&Server{}).getServerPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := scsynth, path; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, err := (&Server{Program: ProgramSupernova}).getServerPath(); err == nil {
+		t.Fatal("expected an error for a missing supernova")
+	}
+
+	// The environment variable takes precedence over PATH.
+	other := writeScript(t, t.TempDir(), "my-scsynth", "echo 'scsynth 3.12.2'")
+	t.Setenv(ScsynthEnv, other)
+	if path, err = (&Server{}).getServerPath(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := other, path; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	t.Setenv(ScsynthEnv, filepath.Join(dir, "missing"))
+	if _, err := (&Server{}).getServerPath(); err == nil {
+		t.Fatal("expected an error for a missing program in " + ScsynthEnv)
+	}
+
+	// Server.Path takes precedence over everything.
+	if path, err = (&Server{Path: scsynth}).getServerPath(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := scsynth, path; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	dir := t.TempDir()
+
+	for i, testcase := range []struct {
+		script   string
+		expected string
+		valid    bool
+	}{
+		{"echo 'scsynth 3.13.0 (Built from tag Version-3.13.0)'", "3.13.0", true},
+		{"echo 'supernova 3.11.0-dev'", "3.11.0-dev", true},
+		{"echo 'no version here'", "", false},
+		{"echo 'broken' >&2; exit 1", "", false},
+	} {
+		s := &Server{Path: writeScript(t, dir, "scsynth"+strconv.Itoa(i), testcase.script)}
+		version, err := s.Version()
+		if testcase.valid && err != nil {
+			t.Fatalf("testcase %d: %s", i, err)
+		} else if !testcase.valid && err == nil {
+			t.Fatalf("testcase %d: expected an error", i)
+		}
+		if expected, got := testcase.expected, version; expected != got {
+			t.Fatalf("testcase %d: expected %s, got %s", i, expected, got)
+		}
+	}
+}
+
+// writeScript writes an executable shell script to dir.
+func writeScript(t *testing.T, dir, name, script string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,11 @@
+// +build windows
+
+package sc
+
+// installDirs are the directories scsynth and supernova
+// are usually installed in on windows systems.
+// The SuperCollider installer includes the version in the directory name.
+var installDirs = []string{
+	`C:\Program Files\SuperCollider*`,
+	`C:\Program Files (x86)\SuperCollider*`,
+}
@@ -2,6 +2,7 @@ package sc
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/scgolang/osc"
 )
@@ -28,38 +29,131 @@ func newStatus(msg osc.Message) (*ServerStatus, error) {
 	if numArgs != 9 {
 		return nil, fmt.Errorf("Only got %d arguments in /status.reply message", numArgs)
 	}
+	// The first argument is unused.
 	var err error
-	status.NumUgens, err = msg.Arguments[0].ReadInt32()
+	status.NumUgens, err = msg.Arguments[1].ReadInt32()
 	if err != nil {
 		return nil, err
 	}
-	status.NumSynths, err = msg.Arguments[1].ReadInt32()
+	status.NumSynths, err = msg.Arguments[2].ReadInt32()
 	if err != nil {
 		return nil, err
 	}
-	status.NumGroups, err = msg.Arguments[2].ReadInt32()
+	status.NumGroups, err = msg.Arguments[3].ReadInt32()
 	if err != nil {
 		return nil, err
 	}
-	status.NumSynthdefs, err = msg.Arguments[3].ReadInt32()
+	status.NumSynthdefs, err = msg.Arguments[4].ReadInt32()
 	if err != nil {
 		return nil, err
 	}
-	status.AvgCPU, err = msg.Arguments[4].ReadFloat32()
+	status.AvgCPU, err = msg.Arguments[5].ReadFloat32()
 	if err != nil {
 		return nil, err
 	}
-	status.PeakCPU, err = msg.Arguments[5].ReadFloat32()
+	status.PeakCPU, err = msg.Arguments[6].ReadFloat32()
 	if err != nil {
 		return nil, err
 	}
-	status.NominalSampleRate, err = msg.Arguments[6].ReadFloat32()
+	status.NominalSampleRate, err = msg.Arguments[7].ReadFloat32()
 	if err != nil {
 		return nil, err
 	}
-	status.ActualSampleRate, err = msg.Arguments[7].ReadFloat32()
+	status.ActualSampleRate, err = msg.Arguments[8].ReadFloat32()
 	if err != nil {
 		return nil, err
 	}
 	return status, nil
 }
+
+// statusWindowSize is the number of /status replies that are used
+// to compute the rolling statistics in a StatusSnapshot.
+const statusWindowSize = 60
+
+// StatusSnapshot is a ServerStatus that has been collected by WatchStatus.
+// It includes rolling statistics about the most recent status replies.
+type StatusSnapshot struct {
+	ServerStatus
+
+	// Time is when the status reply was received.
+	Time time.Time `json:"time"`
+
+	// AvgCPUStats describes recent values of AvgCPU.
+	AvgCPUStats CPUStats `json:"avgCPUStats"`
+
+	// PeakCPUStats describes recent values of PeakCPU.
+	PeakCPUStats CPUStats `json:"peakCPUStats"`
+
+	// SampleRateDrift is the relative difference between the actual and
+	// nominal sample rates, e.g. 0.001 means the actual sample rate
+	// is 0.1% higher than the nominal sample rate.
+	SampleRateDrift float32 `json:"sampleRateDrift"`
+}
+
+// CPUStats contains the min, max, and mean of a CPU usage metric
+// over the most recent status replies.
+type CPUStats struct {
+	Min float32 `json:"min"`
+	Max float32 `json:"max"`
+	Avg float32 `json:"avg"`
+}
+
+// statusWindow holds the most recent status replies in a ring buffer.
+type statusWindow struct {
+	avgCPU  []float32
+	peakCPU []float32
+	next    int
+}
+
+// newStatusWindow creates a new status window.
+func newStatusWindow(size int) *statusWindow {
+	return &statusWindow{
+		avgCPU:  make([]float32, 0, size),
+		peakCPU: make([]float32, 0, size),
+	}
+}
+
+// add adds a status reply to the window and returns a snapshot
+// with the updated statistics.
+func (sw *statusWindow) add(status ServerStatus, t time.Time) StatusSnapshot {
+	if len(sw.avgCPU) < cap(sw.avgCPU) {
+		sw.avgCPU = append(sw.avgCPU, status.AvgCPU)
+		sw.peakCPU = append(sw.peakCPU, status.PeakCPU)
+	} else {
+		sw.avgCPU[sw.next] = status.AvgCPU
+		sw.peakCPU[sw.next] = status.PeakCPU
+	}
+	sw.next = (sw.next + 1) % cap(sw.avgCPU)
+
+	snap := StatusSnapshot{
+		ServerStatus: status,
+		Time:         t,
+		AvgCPUStats:  newCPUStats(sw.avgCPU),
+		PeakCPUStats: newCPUStats(sw.peakCPU),
+	}
+	if status.NominalSampleRate != 0 {
+		snap.SampleRateDrift = (status.ActualSampleRate - status.NominalSampleRate) / status.NominalSampleRate
+	}
+	return snap
+}
+
+// newCPUStats computes statistics for a list of CPU usage values.
+func newCPUStats(vals []float32) CPUStats {
+	if len(vals) == 0 {
+		return CPUStats{}
+	}
+	stats := CPUStats{Min: vals[0], Max: vals[0]}
+
+	var sum float32
+	for _, val := range vals {
+		if val < stats.Min {
+			stats.Min = val
+		}
+		if val > stats.Max {
+			stats.Max = val
+		}
+		sum += val
+	}
+	stats.Avg = sum / float32(len(vals))
+	return stats
+}
@@ -0,0 +1,50 @@
+package sc
+
+import (
+	"fmt"
+	"io"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// statusMetric is a single metric that is exported from a StatusSnapshot.
+type statusMetric struct {
+	name  string
+	help  string
+	value float64
+}
+
+// metrics returns the list of metrics exported for a status snapshot.
+func (snap StatusSnapshot) metrics() []statusMetric {
+	return []statusMetric{
+		{"scsynth_ugens", "Number of running ugens.", float64(snap.NumUgens)},
+		{"scsynth_synths", "Number of running synths.", float64(snap.NumSynths)},
+		{"scsynth_groups", "Number of groups.", float64(snap.NumGroups)},
+		{"scsynth_synthdefs", "Number of loaded synthdefs.", float64(snap.NumSynthdefs)},
+		{"scsynth_avg_cpu", "Average CPU usage in percent.", float64(snap.AvgCPU)},
+		{"scsynth_avg_cpu_min", "Minimum of recent average CPU usage in percent.", float64(snap.AvgCPUStats.Min)},
+		{"scsynth_avg_cpu_max", "Maximum of recent average CPU usage in percent.", float64(snap.AvgCPUStats.Max)},
+		{"scsynth_avg_cpu_mean", "Mean of recent average CPU usage in percent.", float64(snap.AvgCPUStats.Avg)},
+		{"scsynth_peak_cpu", "Peak CPU usage in percent.", float64(snap.PeakCPU)},
+		{"scsynth_peak_cpu_min", "Minimum of recent peak CPU usage in percent.", float64(snap.PeakCPUStats.Min)},
+		{"scsynth_peak_cpu_max", "Maximum of recent peak CPU usage in percent.", float64(snap.PeakCPUStats.Max)},
+		{"scsynth_peak_cpu_mean", "Mean of recent peak CPU usage in percent.", float64(snap.PeakCPUStats.Avg)},
+		{"scsynth_nominal_sample_rate", "Nominal sample rate in Hz.", float64(snap.NominalSampleRate)},
+		{"scsynth_actual_sample_rate", "Actual sample rate in Hz.", float64(snap.ActualSampleRate)},
+		{"scsynth_sample_rate_drift", "Relative difference between the actual and nominal sample rates.", float64(snap.SampleRateDrift)},
+		{"scsynth_status_timestamp_seconds", "Unix time when the status reply was received.", float64(snap.Time.UnixNano()) / 1e9},
+	}
+}
+
+// WritePrometheus writes a snapshot to an io.Writer
+// in the Prometheus text exposition format.
+// All metrics are gauges.
+func (snap StatusSnapshot) WritePrometheus(w io.Writer) error {
+	for _, m := range snap.metrics() {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", m.name, m.help, m.name, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package sc
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+// TestNewStatus checks the argument indices of /status.reply,
+// which WatchStatus and the metrics handler depend on.
+func TestNewStatus(t *testing.T) {
+	status, err := newStatus(osc.Message{
+		Address: statusReplyAddress,
+		Arguments: osc.Arguments{
+			osc.Int(1), // unused
+			osc.Int(10),
+			osc.Int(3),
+			osc.Int(2),
+			osc.Int(7),
+			osc.Float(1.5),
+			osc.Float(2.5),
+			osc.Float(48000),
+			osc.Float(48010),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ServerStatus{
+		NumUgens:          10,
+		NumSynths:         3,
+		NumGroups:         2,
+		NumSynthdefs:      7,
+		AvgCPU:            1.5,
+		PeakCPU:           2.5,
+		NominalSampleRate: 48000,
+		ActualSampleRate:  48010,
+	}
+	if got := *status; expected != got {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestStatusWindow(t *testing.T) {
+	var (
+		sw   = newStatusWindow(3)
+		now  = time.Now()
+		snap StatusSnapshot
+	)
+	for _, cpu := range []float32{10, 20, 30, 40} {
+		snap = sw.add(ServerStatus{
+			AvgCPU:            cpu,
+			PeakCPU:           2 * cpu,
+			NominalSampleRate: 48000,
+			ActualSampleRate:  48048,
+		}, now)
+	}
+	// The first value should have been dropped from the window.
+	if expected, got := (CPUStats{Min: 20, Max: 40, Avg: 30}), snap.AvgCPUStats; expected != got {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+	if expected, got := (CPUStats{Min: 40, Max: 80, Avg: 60}), snap.PeakCPUStats; expected != got {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+	if expected, got := float32(0.001), snap.SampleRateDrift; expected != got {
+		t.Fatalf("expected sample rate drift %f, got %f", expected, got)
+	}
+}
+
+func TestStatusSnapshotWritePrometheus(t *testing.T) {
+	snap := StatusSnapshot{
+		ServerStatus: ServerStatus{NumSynths: 12, AvgCPU: 3.5},
+		AvgCPUStats:  CPUStats{Min: 1, Max: 4, Avg: 2.5},
+	}
+	buf := &bytes.Buffer{}
+	if err := snap.WritePrometheus(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE scsynth_synths gauge",
+		"scsynth_synths 12",
+		"scsynth_avg_cpu 3.5",
+		"scsynth_avg_cpu_max 4",
+		"scsynth_avg_cpu_mean 2.5",
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("expected output to contain %q, got\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	c := &Client{}
+
+	rec := httptest.NewRecorder()
+	c.StatusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), "scsynth_up 0\n") {
+		t.Fatalf("expected scsynth_up to be 0, got\n%s", rec.Body.String())
+	}
+	c.setLastStatus(StatusSnapshot{ServerStatus: ServerStatus{NumUgens: 7}})
+
+	rec = httptest.NewRecorder()
+	c.StatusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, line := range []string{"scsynth_up 1", "scsynth_ugens 7"} {
+		if !strings.Contains(rec.Body.String(), line+"\n") {
+			t.Fatalf("expected output to contain %q, got\n%s", line, rec.Body.String())
+		}
+	}
+}
+
+// TestWatchStatus checks that WatchStatus sends a snapshot every interval
+// with statistics over all the replies so far, and closes the channel
+// when ctx is done.
+func TestWatchStatus(t *testing.T) {
+	client, fs := newFakeClient(t)
+	fs.SetCPU(10, 20)
+
+	const interval = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		snapshots = client.WatchStatus(ctx, interval)
+		snap      StatusSnapshot
+		times     []time.Time
+	)
+	for _, cpu := range []float32{10, 30} {
+		select {
+		case snap = <-snapshots:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for a status snapshot")
+		}
+		if expected, got := cpu, snap.AvgCPU; expected != got {
+			t.Fatalf("expected avg cpu %f, got %f", expected, got)
+		}
+		times = append(times, time.Now())
+		fs.SetCPU(30, 60)
+	}
+	if elapsed := times[1].Sub(times[0]); elapsed < interval/2 {
+		t.Fatalf("expected snapshots %s apart, got %s", interval, elapsed)
+	}
+	// The second snapshot includes the first reply.
+	if expected, got := (CPUStats{Min: 10, Max: 30, Avg: 20}), snap.AvgCPUStats; expected != got {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+	if expected, got := (CPUStats{Min: 20, Max: 60, Avg: 40}), snap.PeakCPUStats; expected != got {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-snapshots:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("expected the channel to be closed when ctx is done")
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package sc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+// RestartPolicy determines when a Supervisor restarts scsynth.
+type RestartPolicy int
+
+// Restart policies.
+const (
+	// RestartNever never restarts scsynth.
+	RestartNever RestartPolicy = iota
+
+	// RestartOnFailure restarts scsynth if it exits with an error.
+	RestartOnFailure
+
+	// RestartAlways restarts scsynth whenever it exits,
+	// unless it was stopped with Supervisor.Stop.
+	RestartAlways
+)
+
+// Supervisor defaults.
+const (
+	DefaultReadyTimeout = 5 * time.Second
+	DefaultGracePeriod  = 2 * time.Second
+	DefaultMinBackoff   = 100 * time.Millisecond
+	DefaultMaxBackoff   = 10 * time.Second
+)
+
+// Readiness probing.
+const (
+	// readyPollInterval is how often a supervisor sends /status while it waits for scsynth to be ready.
+	readyPollInterval = 100 * time.Millisecond
+
+	// readyProbeTimeout is how long a supervisor waits for the reply to a single /status,
+	// so that a lost datagram doesn't use up the whole ReadyTimeout.
+	readyProbeTimeout = 500 * time.Millisecond
+)
+
+// ErrSupervisorStopped happens when you try to start a supervisor that has been stopped.
+var ErrSupervisorStopped = errors.New("supervisor stopped")
+
+// Supervisor runs scsynth, restarts it according to a restart policy,
+// and turns its output into structured log events.
+type Supervisor struct {
+	// Server is the server that is supervised.
+	Server *Server
+
+	// Policy determines when scsynth is restarted.
+	Policy RestartPolicy
+
+	// MaxRestarts is the maximum number of consecutive restarts.
+	// If it is zero there is no limit.
+	MaxRestarts int
+
+	// MinBackoff is how long the supervisor waits before the first restart.
+	// The wait doubles after every consecutive restart up to MaxBackoff.
+	// If scsynth stays up for longer than MaxBackoff the wait is reset.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// ReadyTimeout is how long scsynth has to reply to /status after it is started.
+	ReadyTimeout time.Duration
+
+	// GracePeriod is how long scsynth has to exit after it is sent /quit,
+	// before it is killed.
+	GracePeriod time.Duration
+
+	// OnLog, if not nil, is called with every line of scsynth output
+	// and with events generated by the supervisor.
+	// It is called from several goroutines.
+	OnLog func(LogEvent)
+
+	mu      sync.Mutex
+	proc    *supervisedProcess
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+	err     error // err is the error from the last time scsynth exited
+}
+
+// supervisedProcess is a running instance of scsynth.
+type supervisedProcess struct {
+	cmd     *exec.Cmd
+	started time.Time
+	exited  chan struct{} // exited is closed when the process exits
+	err     error         // err is the error returned by cmd.Wait, it is set before exited is closed
+}
+
+// NewSupervisor creates a supervisor for a server.
+func NewSupervisor(s *Server) *Supervisor {
+	return &Supervisor{
+		Server:       s,
+		Policy:       RestartOnFailure,
+		MinBackoff:   DefaultMinBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		ReadyTimeout: DefaultReadyTimeout,
+		GracePeriod:  DefaultGracePeriod,
+	}
+}
+
+// Start starts scsynth and waits until it replies to /status.
+// After Start returns successfully scsynth is restarted
+// according to the restart policy until Stop is called.
+func (sv *Supervisor) Start() error {
+	sv.mu.Lock()
+	if sv.stopped {
+		sv.mu.Unlock()
+		return ErrSupervisorStopped
+	}
+	if sv.done != nil {
+		sv.mu.Unlock()
+		return errors.New("supervisor already started")
+	}
+	sv.stop = make(chan struct{})
+	sv.done = make(chan struct{})
+	sv.mu.Unlock()
+
+	proc, err := sv.launch()
+	if err != nil {
+		close(sv.done)
+		return err
+	}
+	sv.quitIfStopped(proc)
+	go sv.supervise(proc)
+	return nil
+}
+
+// Stop sends /quit to scsynth and waits for it to exit.
+// If scsynth doesn't exit within the grace period it is killed.
+// Stop returns when scsynth has exited and the supervisor is done.
+func (sv *Supervisor) Stop() error {
+	sv.mu.Lock()
+	if sv.stopped || sv.done == nil {
+		sv.mu.Unlock()
+		return nil
+	}
+	sv.stopped = true
+	close(sv.stop)
+	proc := sv.proc
+	sv.mu.Unlock()
+
+	if proc != nil {
+		if err := sv.quit(proc); err != nil {
+			return err
+		}
+	}
+	<-sv.done
+	return nil
+}
+
+// Done returns a channel that is closed when the supervisor
+// has stopped and will not restart scsynth any more.
+func (sv *Supervisor) Done() <-chan struct{} {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.done
+}
+
+// Err returns the error from the last time scsynth exited.
+func (sv *Supervisor) Err() error {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.err
+}
+
+// supervise waits for scsynth to exit and restarts it according to the restart policy.
+func (sv *Supervisor) supervise(proc *supervisedProcess) {
+	defer close(sv.done)
+
+	for restarts := 0; ; {
+		<-proc.exited
+
+		sv.mu.Lock()
+		sv.err, sv.proc = proc.err, nil
+		sv.mu.Unlock()
+
+		if proc.err != nil {
+			sv.log(LogError, fmt.Sprintf("scsynth exited: %s", proc.err))
+		} else {
+			sv.log(LogInfo, "scsynth exited")
+		}
+		if !sv.shouldRestart(proc.err) {
+			return
+		}
+		if time.Since(proc.started) > sv.maxBackoff() {
+			restarts = 0
+		}
+		for {
+			if sv.MaxRestarts > 0 && restarts >= sv.MaxRestarts {
+				sv.log(LogError, fmt.Sprintf("giving up after %d restarts", restarts))
+				return
+			}
+			wait := backoff(restarts, sv.minBackoff(), sv.maxBackoff())
+			restarts++
+
+			sv.log(LogWarning, fmt.Sprintf("restarting scsynth in %s", wait))
+			select {
+			case <-time.After(wait):
+			case <-sv.stop:
+				return
+			}
+			p, err := sv.launch()
+			if err == nil {
+				sv.quitIfStopped(p)
+				proc = p
+				break
+			}
+			sv.log(LogError, fmt.Sprintf("restarting scsynth: %s", err))
+		}
+	}
+}
+
+// quitIfStopped stops a process that was launched while Stop was being called.
+func (sv *Supervisor) quitIfStopped(proc *supervisedProcess) {
+	select {
+	case <-sv.stop:
+		_ = sv.quit(proc) // Best effort, supervise waits for the process to exit.
+	default:
+	}
+}
+
+// shouldRestart returns true if scsynth should be restarted after exiting with err.
+func (sv *Supervisor) shouldRestart(err error) bool {
+	select {
+	case <-sv.stop:
+		return false
+	default:
+	}
+	switch sv.Policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	}
+	return false
+}
+
+// launch starts scsynth and waits for it to be ready.
+func (sv *Supervisor) launch() (*supervisedProcess, error) {
+	cmd, err := sv.Server.command()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	proc := &supervisedProcess{
+		cmd:     cmd,
+		started: time.Now(),
+		exited:  make(chan struct{}),
+	}
+	var logs sync.WaitGroup
+	logs.Add(2)
+	go func() {
+		scanLog(LogStdout, stdout, sv.OnLog)
+		logs.Done()
+	}()
+	go func() {
+		scanLog(LogStderr, stderr, sv.OnLog)
+		logs.Done()
+	}()
+	go func() {
+		logs.Wait() // Wait must not be called before the pipes have been read.
+		proc.err = cmd.Wait()
+		close(proc.exited)
+	}()
+
+	sv.mu.Lock()
+	sv.Server.Cmd, sv.proc = cmd, proc
+	sv.mu.Unlock()
+
+	if err := sv.waitReady(proc); err != nil {
+		_ = cmd.Process.Kill() // Best effort.
+		<-proc.exited
+		sv.mu.Lock()
+		sv.proc = nil
+		sv.mu.Unlock()
+		return nil, err
+	}
+	sv.log(LogInfo, fmt.Sprintf("scsynth is ready on %s", sv.Server.Addr()))
+	return proc, nil
+}
+
+// waitReady sends /status to scsynth until it replies.
+// Each /status gets readyProbeTimeout to be answered before it is sent again.
+func (sv *Supervisor) waitReady(proc *supervisedProcess) error {
+	timeout := sv.ReadyTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		probeCtx, cancelProbe := context.WithTimeout(ctx, readyProbeTimeout)
+		err := probeStatus(probeCtx, sv.Server.Network, sv.Server.Addr(), sv.Server.Options.Password)
+		cancelProbe()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-proc.exited:
+			if proc.err != nil {
+				return fmt.Errorf("scsynth exited before it was ready: %s", proc.err)
+			}
+			return errors.New("scsynth exited before it was ready")
+		case <-ctx.Done():
+			return ErrTimeout
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// quit sends /quit to scsynth and kills it if it hasn't exited after the grace period.
+func (sv *Supervisor) quit(proc *supervisedProcess) error {
+	grace := sv.GracePeriod
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	if err := sendOSC(sv.Server.Network, sv.Server.Addr(), sv.Server.Options.Password, osc.Message{Address: quitAddress}); err != nil {
+		sv.log(LogWarning, fmt.Sprintf("sending /quit: %s", err))
+	}
+	select {
+	case <-proc.exited:
+		return nil
+	case <-time.After(grace):
+	}
+	sv.log(LogWarning, fmt.Sprintf("scsynth did not quit within %s, killing it", grace))
+
+	if err := proc.cmd.Process.Kill(); err != nil {
+		return err
+	}
+	<-proc.exited
+	return nil
+}
+
+// log passes an event generated by the supervisor to OnLog.
+func (sv *Supervisor) log(level, line string) {
+	if sv.OnLog == nil {
+		return
+	}
+	sv.OnLog(LogEvent{
+		Time:   time.Now(),
+		Source: LogSupervisor,
+		Level:  level,
+		Line:   line,
+	})
+}
+
+// minBackoff returns the minimum backoff.
+func (sv *Supervisor) minBackoff() time.Duration {
+	if sv.MinBackoff <= 0 {
+		return DefaultMinBackoff
+	}
+	return sv.MinBackoff
+}
+
+// maxBackoff returns the maximum backoff.
+func (sv *Supervisor) maxBackoff() time.Duration {
+	if sv.MaxBackoff <= 0 {
+		return DefaultMaxBackoff
+	}
+	return sv.MaxBackoff
+}
+
+// backoff returns how long to wait before a restart,
+// given the number of consecutive restarts that have already happened.
+func backoff(restarts int, min, max time.Duration) time.Duration {
+	d := min
+	for i := 0; i < restarts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// probeStatus sends /status to scsynth and waits for /status.reply.
+// It does not use a Client so it can be used before scsynth is ready.
+// password is sent first if it is not empty (see sendPassword).
+func probeStatus(ctx context.Context, network, addr, password string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	if err := sendPassword(conn, network, password); err != nil {
+		return err
+	}
+	if err := writeOSC(conn, network, osc.Message{Address: statusAddress}); err != nil {
+		return err
+	}
+	for {
+		msg, err := readOSC(conn, network)
+		if err != nil {
+			return err
+		}
+		if msg.Address == statusReplyAddress {
+			return nil
+		}
+	}
+}
+
+// sendOSC sends a single message to scsynth without waiting for a reply.
+// password is sent first if it is not empty (see sendPassword).
+func sendOSC(network, addr, password string, msg osc.Message) error {
+	conn, err := net.DialTimeout(network, addr, DefaultConnectTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	if err := sendPassword(conn, network, password); err != nil {
+		return err
+	}
+	return writeOSC(conn, network, msg)
+}
+
+// sendPassword sends the session password of an scsynth that was started
+// with one (see ServerOptions.Password).
+// scsynth expects it before any other packet on a TCP connection,
+// and it is not sent over UDP.
+func sendPassword(conn net.Conn, network, password string) error {
+	if network != "tcp" || password == "" {
+		return nil
+	}
+	return writePacket(conn, network, osc.ToBytes(password))
+}
+
+// writeOSC writes a message to a connection.
+func writeOSC(conn net.Conn, network string, msg osc.Message) error {
+	return writePacket(conn, network, msg.Bytes())
+}
+
+// writePacket writes a packet to a connection.
+// Packets sent over TCP are prefixed with their size.
+func writePacket(conn net.Conn, network string, data []byte) error {
+	if network == "tcp" {
+		if err := binary.Write(conn, byteOrder, int32(len(data))); err != nil {
+			return err
+		}
+	}
+	_, err := conn.Write(data)
+	return err
+}
+
+// readOSC reads a message from a connection.
+func readOSC(conn net.Conn, network string) (osc.Message, error) {
+	if network != "tcp" {
+		buf := make([]byte, 65536)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return osc.Message{}, err
+		}
+		return osc.ParseMessage(buf[:n], conn.RemoteAddr())
+	}
+	var size int32
+	if err := binary.Read(conn, byteOrder, &size); err != nil {
+		return osc.Message{}, err
+	}
+	if size < 0 {
+		return osc.Message{}, fmt.Errorf("invalid message size %d", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return osc.Message{}, err
+	}
+	return osc.ParseMessage(buf, conn.RemoteAddr())
+}
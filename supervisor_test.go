@@ -0,0 +1,125 @@
+package sc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestParseLogLine(t *testing.T) {
+	for i, testcase := range []struct {
+		line    string
+		level   string
+		command string
+	}{
+		{"SuperCollider 3 server ready.", LogInfo, ""},
+		{"FAILURE IN SERVER /s_new SynthDef not found", LogError, "/s_new"},
+		{"exception in real time: alloc failed", LogError, ""},
+		{"*** ERROR: failed to open UDP socket: address in use.", LogError, ""},
+		{"WARNING: Number of Devices changed", LogWarning, ""},
+		{"late 0.012345678", LogWarning, ""},
+	} {
+		ev := parseLogLine(LogStdout, testcase.line)
+		if expected, got := testcase.level, ev.Level; expected != got {
+			t.Fatalf("testcase %d: expected level %s, got %s", i, expected, got)
+		}
+		if expected, got := testcase.command, ev.Command; expected != got {
+			t.Fatalf("testcase %d: expected command %q, got %q", i, expected, got)
+		}
+		if expected, got := testcase.line, ev.Line; expected != got {
+			t.Fatalf("testcase %d: expected line %q, got %q", i, expected, got)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for i, testcase := range []struct {
+		restarts int
+		expected time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{10, time.Second},
+	} {
+		if got := backoff(testcase.restarts, 100*time.Millisecond, time.Second); testcase.expected != got {
+			t.Fatalf("testcase %d: expected %s, got %s", i, testcase.expected, got)
+		}
+	}
+}
+
+func TestProbeStatus(t *testing.T) {
+	fs := newFakeServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := probeStatus(ctx, "udp", fs.Addr(), ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProbeStatusPassword(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }() // Best effort.
+
+	go servePasswordLogins(ln, "secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := probeStatus(ctx, "tcp", ln.Addr().String(), "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := probeStatus(ctx, "tcp", ln.Addr().String(), "wrong"); err == nil {
+		t.Fatal("expected an error for a wrong password")
+	}
+}
+
+func TestWaitReadyRetries(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	// Drop the first /status like a lost datagram, and reply to the next one.
+	go func() {
+		buf := make([]byte, 65536)
+		for i := 0; ; i++ {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if i == 0 {
+				continue
+			}
+			_, _ = conn.WriteTo(osc.Message{Address: statusReplyAddress}.Bytes(), addr) // Best effort.
+		}
+	}()
+	sv := NewSupervisor(&Server{
+		Network: "udp",
+		Port:    conn.LocalAddr().(*net.UDPAddr).Port,
+	})
+	sv.ReadyTimeout = 5 * readyProbeTimeout
+
+	if err := sv.waitReady(&supervisedProcess{exited: make(chan struct{})}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSupervisorStopBeforeStart(t *testing.T) {
+	sv := NewSupervisor(&Server{Network: "udp"})
+	if err := sv.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sv.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
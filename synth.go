@@ -40,7 +40,23 @@ func (s *Synth) Set(ctls map[string]float32) error {
 		msg.Arguments = append(msg.Arguments, osc.String(name))
 		msg.Arguments = append(msg.Arguments, osc.Float(value))
 	}
-	return s.client.oscConn.Send(msg)
+	return s.client.send(msg)
+}
+
+// UgenCommand sends a /u_cmd message to a ugen in this synth.
+// ugenIndex is the index of the ugen in the synthdef that was used to
+// create the synth (see Synthdef.UgenIndex).
+func (s *Synth) UgenCommand(ugenIndex int32, name string, args ...osc.Argument) error {
+	msg := osc.Message{
+		Address: ugenCommandAddress,
+		Arguments: osc.Arguments{
+			osc.Int(s.ID),
+			osc.Int(ugenIndex),
+			osc.String(name),
+		},
+	}
+	msg.Arguments = append(msg.Arguments, args...)
+	return s.client.send(msg)
 }
 
 // newSynth creates a new synth structure.
@@ -0,0 +1,21 @@
+package sc
+
+import (
+	"testing"
+
+	"github.com/scgolang/osc"
+)
+
+func TestSynthUgenCommand(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	synth, err := client.Synth("foo", 1000, AddToTail, RootNodeID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.UgenCommand(2, "setTarget", osc.Float(0.5), osc.Int(3)); err != nil {
+		t.Fatal(err)
+	}
+	msg := waitReceived(t, fs, ugenCommandAddress)
+	checkArguments(t, msg, osc.Int(1000), osc.Int(2), osc.String("setTarget"), osc.Float(0.5), osc.Int(3))
+}
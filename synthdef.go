@@ -10,10 +10,25 @@ import (
 
 const (
 	synthdefStart     = "SCgf"
-	synthdefVersion   = 2
+	synthdefVersion   = SynthdefVersion2
 	constantUgenIndex = -1
 )
 
+// Synthdef file format versions.
+// Version 1 uses int16 instead of int32 for counts, input indices,
+// and param indices. It is still produced by some older tools.
+const (
+	SynthdefVersion1 = int32(1)
+	SynthdefVersion2 = int32(2)
+)
+
+// SynthdefSchema is the version of the json and xml representations
+// of synthdefs written by WriteJSON and WriteXML.
+// It is incremented whenever a change to those representations
+// would stop older versions of ReadSynthdefJSON and ReadSynthdefXML
+// from reading them.
+const SynthdefSchema = 1
+
 var byteOrder = binary.BigEndian
 
 // Synthdef defines the structure of synthdef data as defined
@@ -38,27 +53,37 @@ type Synthdef struct {
 	// Variants is the list of variants contained in the synth def
 	Variants []*Variant `json:"variants,omitempty" xml:"Variants>Variant"`
 
-	// inidx helps us track which outputs we have consumed from the In ugen.
-	// In triggers multichannel expansion when it has > 1 outputs,
-	// but usually the behavior for ugens with multiple outputs that act
-	// as an input to another ugen is that the outputs get appended as
-	// consecutive inputs.
-	// In, on the other hand, puts one of its output channels on each channel of the
-	// expression tree above it.
-	inidx int
-
 	// seen is an array of ugen nodes that have been added
 	// to the synthdef
 	seen []*Ugen
 
 	// root is the root of the ugen tree that defines this synthdef
-	// this is used, for example, when drawing an svg representation
-	// of the synthdef
+	// (WriteDOT and WriteSVG draw the flattened Ugens instead,
+	// so they also work for synthdefs that were read from files)
 	root Ugen
+
+	// opts controls how the ugen graph is flattened
+	opts SynthdefOptions
+}
+
+// SynthdefOptions controls how a ugen graph is flattened
+// when a synthdef is created.
+type SynthdefOptions struct {
+	// Fuse rewrites a*b+c into MulAdd and chains of additions into Sum3 and Sum4
+	// with the same rules sclang uses when it builds a synthdef.
+	// This lets ugen graphs written with Mul and Add produce
+	// the same synthdef as sclang, with fewer ugens.
+	Fuse bool
 }
 
 // NewSynthdef creates a synthdef by traversing a ugen graph
 func NewSynthdef(name string, graphFunc UgenFunc) *Synthdef {
+	return NewSynthdefWithOptions(name, graphFunc, SynthdefOptions{})
+}
+
+// NewSynthdefWithOptions creates a synthdef by traversing a ugen graph
+// and flattening it according to opts.
+func NewSynthdefWithOptions(name string, graphFunc UgenFunc, opts SynthdefOptions) *Synthdef {
 	// It would be nice to parse synthdef params from function arguments
 	// with the reflect package.
 	// See https://groups.google.com/forum/#!topic/golang-nuts/nM_ZhL7fuGc
@@ -71,7 +96,7 @@ func NewSynthdef(name string, graphFunc UgenFunc) *Synthdef {
 	// they were created in the UgenFunc.
 	var (
 		params = newParams()
-		def    = &Synthdef{Name: name, root: graphFunc(params)}
+		def    = &Synthdef{Name: name, root: graphFunc(params), opts: opts}
 	)
 	return def.flatten(params)
 }
@@ -148,6 +173,25 @@ func (def *Synthdef) Root() int32 {
 	return 0
 }
 
+// UgenIndex returns the index of a ugen in the synthdef.
+// The ugen is identified by its name and its occurrence, where
+// occurrence 0 is the first ugen with the name, occurrence 1 is the second,
+// and so on.
+// This can be used to address a ugen with Synth.UgenCommand.
+func (def *Synthdef) UgenIndex(name string, occurrence int) (int32, error) {
+	n := 0
+	for i, u := range def.Ugens {
+		if u.Name != name {
+			continue
+		}
+		if n == occurrence {
+			return int32(i), nil
+		}
+		n++
+	}
+	return -1, fmt.Errorf("synthdef %s does not have occurrence %d of ugen %s", def.Name, occurrence, name)
+}
+
 // addConstant adds a constant to a synthdef and returns
 // the index in the constants array where that constant is
 // located.
@@ -211,6 +255,9 @@ func (def *Synthdef) addUgen(u *Ugen) (*Ugen, int, bool) {
 func (def *Synthdef) flatten(params Params) *Synthdef {
 	def.addParams(params)
 
+	if def.opts.Fuse {
+		def.root = *fuseUgens(&def.root)
+	}
 	// Get a topologically sorted ugens list.
 	ugenNodes := def.topsort(&def.root)
 
@@ -232,25 +279,7 @@ func (def *Synthdef) flatten(params Params) *Synthdef {
 func (def *Synthdef) flattenInput(params Params, ugen *Ugen, input Input) {
 	switch v := input.(type) {
 	case *Ugen:
-		_, idx, _ := def.addUgen(v)
-
-		// In has different behavior than other ugens when it is multichannel expanded.
-		// Each channel of its output gets mapped to a single channel of the expression
-		// tree above it. [briansorahan]
-		if v.Name == "In" {
-			ugen.Inputs = append(ugen.Inputs, UgenInput{
-				UgenIndex:   int32(idx),
-				OutputIndex: int32(def.inidx),
-			})
-			def.inidx++
-			break
-		}
-		for outputIndex := range v.Outputs {
-			ugen.Inputs = append(ugen.Inputs, UgenInput{
-				UgenIndex:   int32(idx),
-				OutputIndex: int32(outputIndex),
-			})
-		}
+		def.flattenUgenInput(ugen, v)
 	case C:
 		idx := def.addConstant(v)
 		ugen.Inputs = append(ugen.Inputs, UgenInput{
@@ -267,14 +296,7 @@ func (def *Synthdef) flattenInput(params Params, ugen *Ugen, input Input) {
 		for _, min := range v.InputArray() {
 			switch x := min.(type) {
 			case *Ugen:
-				_, idx, _ := def.addUgen(x)
-				// will we ever need to use a different output index? [bps]
-				for outputIndex := range x.Outputs {
-					ugen.Inputs = append(ugen.Inputs, UgenInput{
-						UgenIndex:   int32(idx),
-						OutputIndex: int32(outputIndex),
-					})
-				}
+				def.flattenUgenInput(ugen, x)
 			case C:
 				ugen.Inputs = append(ugen.Inputs, UgenInput{
 					UgenIndex:   -1,
@@ -290,11 +312,32 @@ func (def *Synthdef) flattenInput(params Params, ugen *Ugen, input Input) {
 	}
 }
 
+// flattenUgenInput adds the outputs of u to the inputs of ugen.
+// If u is a single channel of a multichannel ugen (see Ugen.channels)
+// only that channel is added, otherwise all of the outputs are.
+func (def *Synthdef) flattenUgenInput(ugen *Ugen, u *Ugen) {
+	_, idx, _ := def.addUgen(u.sourceUgen())
+
+	if u.source != nil {
+		ugen.Inputs = append(ugen.Inputs, UgenInput{
+			UgenIndex:   int32(idx),
+			OutputIndex: u.channel,
+		})
+		return
+	}
+	for outputIndex := range u.Outputs {
+		ugen.Inputs = append(ugen.Inputs, UgenInput{
+			UgenIndex:   int32(idx),
+			OutputIndex: int32(outputIndex),
+		})
+	}
+}
+
 // processUgenInput processes a single ugen input
 func (def *Synthdef) processUgenInput(input Input, stack *stack, depth int) {
 	switch v := input.(type) {
 	case *Ugen:
-		def.topsortr(v, stack, depth+1)
+		def.topsortr(v.sourceUgen(), stack, depth+1)
 		break
 	case MultiInput:
 		// multi input
@@ -302,7 +345,7 @@ func (def *Synthdef) processUgenInput(input Input, stack *stack, depth int) {
 		for j := len(mins) - 1; j >= 0; j-- {
 			switch w := mins[j].(type) {
 			case *Ugen:
-				def.topsortr(w, stack, depth+1)
+				def.topsortr(w.sourceUgen(), stack, depth+1)
 				break
 			}
 		}
@@ -372,10 +415,13 @@ func (d differ) crawl(diffs [][2]string, idx1, idx2 int32) [][2]string {
 			fmt.Sprintf("ugen %d is a(n) %s", idx2, u2.Name),
 		})
 	}
-	if u1.NumOutputs != u2.NumOutputs {
+	// Compare the outputs that are written to synthdef files.
+	// NumOutputs is not written, and ugens with no outputs (e.g. Out)
+	// have NumOutputs 1 when they are created with NewUgen.
+	if l1, l2 := len(u1.Outputs), len(u2.Outputs); l1 != l2 {
 		return append(diffs, [2]string{
-			fmt.Sprintf("ugen %d has %d output(s)", idx1, u1.NumOutputs),
-			fmt.Sprintf("ugen %d has %d output(s)", idx2, u2.NumOutputs),
+			fmt.Sprintf("ugen %d has %d output(s)", idx1, l1),
+			fmt.Sprintf("ugen %d has %d output(s)", idx2, l2),
 		})
 	}
 	if u1.Rate != u2.Rate {
@@ -430,7 +476,7 @@ func (d differ) crawl(diffs [][2]string, idx1, idx2 int32) [][2]string {
 		}
 		// They are both not constant.
 		// TODO: detect cycles
-		diffs = append(diffs, d.crawl(diffs, ui1, ui2)...)
+		diffs = d.crawl(diffs, ui1, ui2)
 	}
 	return diffs
 }
@@ -458,3 +504,11 @@ func (d differ) do() [][2]string {
 	}
 	return d.crawl([][2]string{}, d[0].Root(), d[1].Root())
 }
+
+// checkSynthdefVersion returns an error if version is not a supported synthdef file version.
+func checkSynthdefVersion(version int32) error {
+	if version != SynthdefVersion1 && version != SynthdefVersion2 {
+		return fmt.Errorf("bad synthdef version %d", version)
+	}
+	return nil
+}
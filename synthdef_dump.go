@@ -0,0 +1,185 @@
+package sc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dump writes a human-readable listing of the synthdef to w,
+// similar to SynthDef.dumpUGens in sclang, e.g.
+//
+//	SynthDef "SineTone"
+//	params:
+//	  freq = 440
+//	ugens:
+//	  [0] Control.kr: freq
+//	  [1] SinOsc.ar(freq: Control[0], 0)
+//	  [2] *.ar(SinOsc[1], 0.5)
+//	  [3] Out.ar(0, *[2])
+//
+// Ugen inputs refer to other ugens by name and index, followed by the output
+// index if the ugen has more than one output. Inputs that read a param are
+// shown with the name of the param and the control ugen instead. Operators are shown instead of
+// BinaryOpUGen and UnaryOpUGen.
+// Dump does not validate the synthdef, bad indices are shown as such.
+func (def *Synthdef) Dump(w io.Writer) error {
+	var (
+		b     strings.Builder
+		names = def.valueNames()
+	)
+	fmt.Fprintf(&b, "SynthDef %s\n", strconv.Quote(def.Name))
+
+	if params := def.dumpParams(); len(params) > 0 {
+		b.WriteString("params:\n")
+		for _, p := range params {
+			fmt.Fprintf(&b, "  %s = %s\n", p.name, graphNumbers(p.values))
+		}
+	}
+	if len(def.Variants) > 0 {
+		b.WriteString("variants:\n")
+		for _, v := range def.Variants {
+			fmt.Fprintf(&b, "  %s\n", def.dumpVariant(v, names))
+		}
+	}
+	if len(def.Ugens) > 0 {
+		b.WriteString("ugens:\n")
+		for i, u := range def.Ugens {
+			fmt.Fprintf(&b, "  [%d] %s\n", i, def.dumpUgen(u, names))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dumpParam is a param and its initial values.
+type dumpParam struct {
+	name   string
+	index  int // index is the index of the first value
+	values []float32
+}
+
+// dumpParams returns the params sorted by index.
+// Params with a bad index are left out, and are reported by Validate.
+func (def *Synthdef) dumpParams() []dumpParam {
+	names := append([]ParamName{}, def.ParamNames...)
+	sort.SliceStable(names, func(i, j int) bool { return names[i].Index < names[j].Index })
+
+	var params []dumpParam
+	for i, pn := range names {
+		end := len(def.InitialParamValues)
+		if i+1 < len(names) && int(names[i+1].Index) < end {
+			end = int(names[i+1].Index)
+		}
+		if pn.Index < 0 || int(pn.Index) >= end {
+			continue
+		}
+		params = append(params, dumpParam{name: pn.Name, index: int(pn.Index), values: def.InitialParamValues[pn.Index:end]})
+	}
+	return params
+}
+
+// valueNames returns the name of every param value.
+// Values of array params are named like freqs[1].
+// Values that don't belong to a param have an empty name.
+func (def *Synthdef) valueNames() []string {
+	names := make([]string, len(def.InitialParamValues))
+
+	for _, p := range def.dumpParams() {
+		if len(p.values) == 1 {
+			names[p.index] = p.name
+			continue
+		}
+		for j := range p.values {
+			names[p.index+j] = p.name + "[" + strconv.Itoa(j) + "]"
+		}
+	}
+	return names
+}
+
+// dumpVariant returns a variant with the param values that differ from
+// the initial param values.
+func (def *Synthdef) dumpVariant(v *Variant, names []string) string {
+	var changes []string
+
+	for i, val := range v.InitialParamValues {
+		if i < len(def.InitialParamValues) && val == def.InitialParamValues[i] {
+			continue
+		}
+		name := "value " + strconv.Itoa(i)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		changes = append(changes, name+" = "+graphNumber(val))
+	}
+	if len(changes) == 0 {
+		return v.Name
+	}
+	return v.Name + ": " + strings.Join(changes, ", ")
+}
+
+// dumpUgen returns a ugen with its resolved inputs.
+func (def *Synthdef) dumpUgen(u *Ugen, names []string) string {
+	var (
+		label = ugenLabel(u)
+		s     = label[0] + "." + label[1]
+	)
+	if len(u.Inputs) > 0 {
+		inputs := make([]string, len(u.Inputs))
+		for i, in := range u.Inputs {
+			inputs[i] = def.dumpInput(in, names)
+		}
+		s += "(" + strings.Join(inputs, ", ") + ")"
+	}
+	if _, ok := controlUgens[u.Name]; ok {
+		var params []string
+		for i := range u.Outputs {
+			k := int(u.SpecialIndex) + i
+			if k < 0 || k >= len(names) || names[k] == "" {
+				params = append(params, "value "+strconv.Itoa(k))
+				continue
+			}
+			params = append(params, names[k])
+		}
+		if len(params) > 0 {
+			s += ": " + strings.Join(params, ", ")
+		}
+		return s
+	}
+	if len(label) > 2 {
+		s += " (" + label[2] + ")"
+	}
+	return s
+}
+
+// dumpInput returns a ugen input.
+func (def *Synthdef) dumpInput(in UgenInput, names []string) string {
+	if in.IsConstant() {
+		if in.OutputIndex < 0 || int(in.OutputIndex) >= len(def.Constants) {
+			return "<bad constant " + strconv.Itoa(int(in.OutputIndex)) + ">"
+		}
+		return graphNumber(def.Constants[in.OutputIndex])
+	}
+	if in.UgenIndex < 0 || int(in.UgenIndex) >= len(def.Ugens) {
+		return "<bad ugen " + strconv.Itoa(int(in.UgenIndex)) + ">"
+	}
+	var (
+		from = def.Ugens[in.UgenIndex]
+		ref  = ugenLabel(from)[0] + "[" + strconv.Itoa(int(in.UgenIndex)) + "]"
+	)
+	if in.OutputIndex < 0 || int(in.OutputIndex) >= len(from.Outputs) {
+		return ref + "<bad output " + strconv.Itoa(int(in.OutputIndex)) + ">"
+	}
+	// The param name says which output of a control is read.
+	if _, ok := controlUgens[from.Name]; ok {
+		if k := int(from.SpecialIndex) + int(in.OutputIndex); k >= 0 && k < len(names) && names[k] != "" {
+			return names[k] + ": " + ref
+		}
+	}
+	if len(from.Outputs) > 1 {
+		ref += "[" + strconv.Itoa(int(in.OutputIndex)) + "]"
+	}
+	return ref
+}
@@ -0,0 +1,116 @@
+package sc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	for _, testCase := range []struct {
+		Name     string
+		Def      func() *Synthdef
+		Expected string
+	}{
+		{
+			Name: "params and variants",
+			Def: func() *Synthdef {
+				def := NewSynthdef("sine tone", func(p Params) Ugen {
+					var (
+						freq = p.Add("freq", 440)
+						amp  = p.Add("amp", 0.1)
+						sine = SinOsc{Freq: freq}.Rate(AR)
+					)
+					return Out{Bus: C(0), Channels: Multi(sine.Mul(amp), sine.Mul(amp).Neg())}.Rate(AR)
+				})
+				def.Variants = []*Variant{
+					{Name: "high", InitialParamValues: []float32{880, 0.1}},
+					{Name: "same", InitialParamValues: []float32{440, 0.1}},
+				}
+				return def
+			},
+			Expected: `SynthDef "sine tone"
+params:
+  freq = 440
+  amp = 0.1
+variants:
+  high: freq = 880
+  same
+ugens:
+  [0] Control.kr: freq, amp
+  [1] SinOsc.ar(freq: Control[0], 0)
+  [2] *.ar(SinOsc[1], amp: Control[0])
+  [3] *.ar(SinOsc[1], amp: Control[0])
+  [4] neg.ar(*[3])
+  [5] Out.ar(0, *[2], neg[4])
+`,
+		},
+		{
+			// Array params, lags and trigger controls can't be created with Params.
+			// The last three inputs of Out are bad.
+			Name: "controls",
+			Def: func() *Synthdef {
+				return &Synthdef{
+					Name:               "controls",
+					Constants:          []float32{0.1, 0},
+					InitialParamValues: []float32{200, 300, 1, 0},
+					ParamNames: []ParamName{
+						{Name: "freqs", Index: 0},
+						{Name: "amp", Index: 2},
+						{Name: "t_trig", Index: 3},
+					},
+					Ugens: []*Ugen{
+						{Name: "LagControl", Rate: KR, Inputs: []UgenInput{{-1, 1}, {-1, 1}, {-1, 0}}, Outputs: []Output{KR, KR, KR}},
+						{Name: "TrigControl", Rate: KR, SpecialIndex: 3, Outputs: []Output{KR}},
+						{Name: "Saw", Rate: AR, SpecialIndex: 3, Inputs: []UgenInput{{0, 0}, {0, 1}}, Outputs: []Output{AR, AR}},
+						{Name: "Decay", Rate: KR, Inputs: []UgenInput{{1, 0}, {-1, 0}}, Outputs: []Output{KR}},
+						{Name: BinOpUgenName, Rate: KR, SpecialIndex: BinOpMul, Inputs: []UgenInput{{0, 2}, {3, 0}}, Outputs: []Output{KR}},
+						{Name: "Out", Rate: AR, Inputs: []UgenInput{{-1, 1}, {2, 1}, {4, 0}, {-1, 2}, {7, 0}, {2, 2}}},
+					},
+					Variants: []*Variant{{Name: "low", InitialParamValues: []float32{100, 150, 1, 0}}},
+				}
+			},
+			Expected: `SynthDef "controls"
+params:
+  freqs = [200, 300]
+  amp = 1
+  t_trig = 0
+variants:
+  low: freqs[0] = 100, freqs[1] = 150
+ugens:
+  [0] LagControl.kr(0, 0, 0.1): freqs[0], freqs[1], amp
+  [1] TrigControl.kr: t_trig
+  [2] Saw.ar(freqs[0]: LagControl[0], freqs[1]: LagControl[0]) (special index 3)
+  [3] Decay.kr(t_trig: TrigControl[1], 0.1)
+  [4] *.kr(amp: LagControl[0], Decay[3])
+  [5] Out.ar(0, Saw[2][1], *[4], <bad constant 2>, <bad ugen 7>, Saw[2]<bad output 2>)
+`,
+		},
+	} {
+		var buf bytes.Buffer
+		if err := testCase.Def().Dump(&buf); err != nil {
+			t.Fatalf("%s: %s", testCase.Name, err)
+		}
+		if expected, got := testCase.Expected, buf.String(); expected != got {
+			t.Fatalf("%s: expected\n%s\ngot\n%s", testCase.Name, expected, got)
+		}
+	}
+}
+
+// TestDumpFixtures checks that every synthdef in testdata can be dumped,
+// with one line per ugen.
+func TestDumpFixtures(t *testing.T) {
+	for _, fixture := range readFixtures(t) {
+		path, def := fixture.path, fixture.def
+
+		var buf bytes.Buffer
+		if err := def.Dump(&buf); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if expected, got := len(def.Ugens), bytes.Count(buf.Bytes(), []byte("\n  [")); expected != got {
+			t.Fatalf("%s: expected %d ugens, got %d", path, expected, got)
+		}
+		if bytes.Contains(buf.Bytes(), []byte("<bad")) {
+			t.Fatalf("%s: expected no bad indices, got\n%s", path, buf.String())
+		}
+	}
+}
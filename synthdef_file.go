@@ -0,0 +1,88 @@
+package sc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+)
+
+// SynthdefFile is a synthdef file that contains any number of synthdefs.
+// sclang writes files like this for SynthDescLib, and scsynth
+// loads all the synthdefs in a file that it receives with /d_recv.
+type SynthdefFile struct {
+	// Version is the synthdef file format version that is written.
+	// If it is 0 then SynthdefVersion2 is used.
+	// ReadSynthdefFile leaves it 0, so files that are read
+	// as version 1 are upgraded to version 2 when they are written.
+	Version int32
+
+	Synthdefs []*Synthdef
+}
+
+// NewSynthdefFile creates a synthdef file that contains the provided synthdefs.
+func NewSynthdefFile(defs ...*Synthdef) *SynthdefFile {
+	return &SynthdefFile{Synthdefs: defs}
+}
+
+// ReadSynthdefFile reads a synthdef file from an io.Reader.
+func ReadSynthdefFile(r io.Reader) (*SynthdefFile, error) {
+	// read the type
+	if err := readSynthdefType(r); err != nil {
+		return nil, err
+	}
+	// read version
+	version, err := readSynthdefVersion(r)
+	if err != nil {
+		return nil, err
+	}
+	// read number of synth defs
+	numDefs, err := readNumberOfSynthdefs(r)
+	if err != nil {
+		return nil, err
+	}
+	f := &SynthdefFile{Synthdefs: make([]*Synthdef, numDefs)}
+	for i := range f.Synthdefs {
+		def, err := readSynthdef(r, version)
+		if err != nil {
+			return nil, fmt.Errorf("reading synthdef %d: %s", i, err)
+		}
+		f.Synthdefs[i] = def
+	}
+	return f, nil
+}
+
+// Bytes returns the binary representation of a synthdef file.
+func (f *SynthdefFile) Bytes() ([]byte, error) {
+	if numDefs := len(f.Synthdefs); numDefs > math.MaxInt16 {
+		return nil, fmt.Errorf("a synthdef file can not contain more than %d synthdefs (got %d)", math.MaxInt16, numDefs)
+	}
+	version := f.Version
+	if version == 0 {
+		version = synthdefVersion
+	}
+	if err := checkSynthdefVersion(version); err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := writeSynthdefHead(buf, version, len(f.Synthdefs)); err != nil {
+		return nil, err
+	}
+	for _, def := range f.Synthdefs {
+		if err := def.writeBody(buf, version); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the binary representation of a synthdef file to an io.Writer.
+// The binary representation is the data that scsynth expects at its /d_recv endpoint.
+func (f *SynthdefFile) WriteTo(w io.Writer) (int64, error) {
+	data, err := f.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
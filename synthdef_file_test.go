@@ -0,0 +1,88 @@
+package sc
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSynthdefFile(t *testing.T) {
+	var defs []*Synthdef
+	for _, name := range []string{"SineTone", "Beats", "Envgen1"} {
+		f, err := os.Open("testdata/" + name + ".scsyndef")
+		if err != nil {
+			t.Fatal(err)
+		}
+		def, err := ReadSynthdef(f)
+		_ = f.Close() // Best effort.
+		if err != nil {
+			t.Fatal(err)
+		}
+		defs = append(defs, def)
+	}
+	buf := &bytes.Buffer{}
+	n, err := NewSynthdefFile(defs...).WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int64(buf.Len()), n; expected != got {
+		t.Fatalf("expected %d bytes, got %d", expected, got)
+	}
+	data := buf.Bytes()
+
+	// A file with several synthdefs can not be read with ReadSynthdef.
+	if _, err := ReadSynthdef(bytes.NewReader(data)); err == nil {
+		t.Fatal("expected an error reading a file with 3 synthdefs")
+	}
+	file, err := ReadSynthdefFile(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := len(defs), len(file.Synthdefs); expected != got {
+		t.Fatalf("expected %d synthdefs, got %d", expected, got)
+	}
+	for i, def := range defs {
+		if diff := def.Diff(file.Synthdefs[i]); len(diff) > 0 {
+			t.Fatalf("synthdef %d: %v", i, diff)
+		}
+	}
+}
+
+func TestSynthdefFileSingle(t *testing.T) {
+	def := NewSynthdef("SineTone", func(p Params) Ugen {
+		return Out{Bus: C(0), Channels: SinOsc{}.Rate(AR)}.Rate(AR)
+	})
+	expected, err := def.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewSynthdefFile(def).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, got) {
+		t.Fatal("expected a file with one synthdef to be the same as the synthdef")
+	}
+}
+
+func TestSendDefs(t *testing.T) {
+	client, fs := newFakeClient(t)
+
+	if err := client.SendDefs(
+		NewSynthdef("sine", defSineA),
+		NewSynthdef("saw", defLFSaw),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 1, len(fs.Received()); expected != got {
+		t.Fatalf("expected %d messages, got %d", expected, got)
+	}
+	status, err := client.Status(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(2), status.NumSynthdefs; expected != got {
+		t.Fatalf("expected %d synthdefs, got %d", expected, got)
+	}
+}
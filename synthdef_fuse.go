@@ -0,0 +1,294 @@
+package sc
+
+// fuseUgens rewrites a ugen graph the way sclang's optimizer does
+// when it builds a synthdef:
+//
+//   - a+b+c becomes Sum3(a, b, c)
+//   - a+b+c+d becomes Sum4(a, b, c, d)
+//   - a*b+c becomes MulAdd(a, b, c)
+//
+// An addition or multiplication is only fused into the ugen that
+// consumes it if nothing else uses it.
+// The graph that root belongs to is not modified, ugens that change
+// (and every ugen that depends on them) are copied.
+func fuseUgens(root *Ugen) *Ugen {
+	f := &fuser{
+		refs:  map[*Ugen]int{},
+		fused: map[*Ugen]*Ugen{},
+	}
+	f.count(root, map[*Ugen]bool{})
+
+	return f.rewrite(root)
+}
+
+// fuser fuses the ugens in a ugen graph.
+type fuser struct {
+	refs  map[*Ugen]int   // refs counts how many times each ugen is used as an input
+	fused map[*Ugen]*Ugen // fused maps ugens to the ugens that replace them
+}
+
+// count counts the references to every ugen that u depends on.
+func (f *fuser) count(u *Ugen, visited map[*Ugen]bool) {
+	if visited[u] {
+		return
+	}
+	visited[u] = true
+
+	if u.source != nil {
+		f.count(u.source, visited)
+		return
+	}
+	for _, in := range u.inputs {
+		switch v := in.(type) {
+		case *Ugen:
+			f.refs[v]++
+			f.count(v, visited)
+		case MultiInput:
+			for _, min := range v.InputArray() {
+				if x, ok := min.(*Ugen); ok {
+					f.refs[x]++
+					f.count(x, visited)
+				}
+			}
+		}
+	}
+}
+
+// rewrite returns the fused version of u.
+// Inputs are fused before the ugens that use them, so chains of
+// additions grow from Add to Sum3 to Sum4 as they are visited.
+func (f *fuser) rewrite(u *Ugen) *Ugen {
+	if v, ok := f.fused[u]; ok {
+		return v
+	}
+	if u.source != nil {
+		return f.rewriteChannel(u)
+	}
+	var (
+		inputs  = make([]Input, len(u.inputs))
+		changed bool
+	)
+	for i, in := range u.inputs {
+		var inChanged bool
+		inputs[i], inChanged = f.rewriteInput(in)
+		changed = changed || inChanged
+	}
+	v := u
+	if changed {
+		v = cloneUgen(u)
+		v.inputs = inputs
+	}
+	if fused := f.fuse(v); fused != nil {
+		v = fused
+	}
+	if v != u {
+		f.refs[v] = f.refs[u]
+	}
+	f.fused[u] = v
+	return v
+}
+
+// rewriteChannel returns the fused version of a single channel of a multichannel ugen.
+func (f *fuser) rewriteChannel(u *Ugen) *Ugen {
+	v := u
+	if source := f.rewrite(u.source); source != u.source {
+		v = cloneUgen(u)
+		v.source = source
+		f.refs[v] = f.refs[u]
+	}
+	f.fused[u] = v
+	return v
+}
+
+// rewriteInput returns the fused version of an input
+// and whether it is different from the original.
+func (f *fuser) rewriteInput(in Input) (Input, bool) {
+	switch v := in.(type) {
+	case *Ugen:
+		fused := f.rewrite(v)
+		return fused, fused != v
+	case MultiInput:
+		var (
+			mins    = v.InputArray()
+			ins     = make([]Input, len(mins))
+			changed bool
+		)
+		for i, min := range mins {
+			x, ok := min.(*Ugen)
+			if !ok {
+				ins[i] = min
+				continue
+			}
+			ins[i] = f.rewrite(x)
+			changed = changed || ins[i] != Input(x)
+		}
+		if !changed {
+			return in, false
+		}
+		return Multi(ins...), true
+	}
+	return in, false
+}
+
+// fuse returns the ugen that replaces u, or nil if u can not be fused.
+// The fusions are tried in the same order sclang tries them.
+func (f *fuser) fuse(u *Ugen) *Ugen {
+	if !isBinOp(u, BinOpAdd) || len(u.inputs) != 2 || u.NumOutputs != 1 {
+		return nil
+	}
+	a, b := u.inputs[0], u.inputs[1]
+	if !fusable(a) || !fusable(b) {
+		return nil
+	}
+	if x := f.operand(a, BinOpUgenName, BinOpAdd); x != nil {
+		if sum := newSum("Sum3", x.inputs[0], x.inputs[1], b); sum != nil {
+			return sum
+		}
+	}
+	if x := f.operand(b, BinOpUgenName, BinOpAdd); x != nil {
+		if sum := newSum("Sum3", x.inputs[0], x.inputs[1], a); sum != nil {
+			return sum
+		}
+	}
+	if x := f.operand(a, "Sum3", 0); x != nil {
+		if sum := newSum("Sum4", x.inputs[0], x.inputs[1], x.inputs[2], b); sum != nil {
+			return sum
+		}
+	}
+	if x := f.operand(b, "Sum3", 0); x != nil {
+		if sum := newSum("Sum4", x.inputs[0], x.inputs[1], x.inputs[2], a); sum != nil {
+			return sum
+		}
+	}
+	if x := f.operand(b, BinOpUgenName, BinOpMul); x != nil {
+		if ma := newMulAdd(x.inputs[0], x.inputs[1], a); ma != nil {
+			return ma
+		}
+		if ma := newMulAdd(x.inputs[1], x.inputs[0], a); ma != nil {
+			return ma
+		}
+	}
+	if x := f.operand(a, BinOpUgenName, BinOpMul); x != nil {
+		if ma := newMulAdd(x.inputs[0], x.inputs[1], b); ma != nil {
+			return ma
+		}
+		if ma := newMulAdd(x.inputs[1], x.inputs[0], b); ma != nil {
+			return ma
+		}
+	}
+	return nil
+}
+
+// operand returns in as a ugen if it has the given name and special index,
+// is only used once, and all of its inputs can be fused.
+// Otherwise it returns nil.
+func (f *fuser) operand(in Input, name string, specialIndex int16) *Ugen {
+	u, ok := in.(*Ugen)
+	if !ok || u.Name != name || u.SpecialIndex != specialIndex || u.NumOutputs != 1 {
+		return nil
+	}
+	if f.refs[u] != 1 {
+		return nil
+	}
+	for _, x := range u.inputs {
+		if !fusable(x) {
+			return nil
+		}
+	}
+	return u
+}
+
+// fusable returns true if in can be moved to the inputs of a fused ugen.
+// Multichannel inputs are left alone because all of their outputs
+// would become inputs of the fused ugen.
+func fusable(in Input) bool {
+	switch v := in.(type) {
+	case C, *param:
+		return true
+	case *Ugen:
+		return v.NumOutputs == 1
+	}
+	return false
+}
+
+// isBinOp returns true if u is a BinaryOpUGen for the given operator.
+func isBinOp(u *Ugen, op int16) bool {
+	return u.Name == BinOpUgenName && u.SpecialIndex == op
+}
+
+// newMulAdd creates a MulAdd ugen if the inputs satisfy sclang's rules
+// for MulAdd (see MulAdd.canBeMulAdd in sclang), otherwise it returns nil.
+// Degenerate cases (mul is 0, 1, or -1, or add is 0) return nil
+// because they don't need a MulAdd.
+func newMulAdd(in, mul, add Input) *Ugen {
+	inRate := inputRate(in)
+
+	switch {
+	case inRate == AR:
+	case inRate == KR && inputRate(mul) != AR && inputRate(add) != AR:
+	default:
+		return nil
+	}
+	if isConstant(mul, 0) || isConstant(mul, 1) || isConstant(mul, -1) || isConstant(add, 0) {
+		return nil
+	}
+	return asOutput(NewUgen("MulAdd", maxRate(in, mul, add), 0, 1, in, mul, add))
+}
+
+// newSum creates a Sum3 or Sum4 ugen.
+// It returns nil if any of the inputs is the constant 0,
+// since then there is no need for the sum.
+// Like sclang, the inputs are sorted by rate with audio rate inputs first.
+func newSum(name string, inputs ...Input) *Ugen {
+	for _, in := range inputs {
+		if isConstant(in, 0) {
+			return nil
+		}
+	}
+	sortSumInputs(inputs)
+
+	return asOutput(NewUgen(name, maxRate(inputs...), 0, 1, inputs...))
+}
+
+// sortSumInputs sorts the inputs of a sum by rate, audio rate first.
+// This is an insertion sort that uses the same comparison as sclang,
+// so inputs with the same rate end up in reverse order
+// just like they do in synthdefs written by sclang.
+func sortSumInputs(inputs []Input) {
+	for i := 1; i < len(inputs); i++ {
+		in := inputs[i]
+		j := i
+		for ; j > 0 && inputRate(inputs[j-1]) <= inputRate(in); j-- {
+			inputs[j] = inputs[j-1]
+		}
+		inputs[j] = in
+	}
+}
+
+// isConstant returns true if in is the constant c.
+func isConstant(in Input, c C) bool {
+	v, ok := in.(C)
+	return ok && v == c
+}
+
+// inputRate returns the rate of an input.
+func inputRate(in Input) int8 {
+	switch v := in.(type) {
+	case *Ugen:
+		return v.Rate
+	case *param:
+		return KR
+	}
+	return IR
+}
+
+// maxRate returns the highest rate of a list of inputs.
+func maxRate(inputs ...Input) int8 {
+	rate := int8(IR)
+	for _, in := range inputs {
+		if r := inputRate(in); r > rate {
+			rate = r
+		}
+	}
+	return rate
+}
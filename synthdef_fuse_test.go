@@ -0,0 +1,191 @@
+package sc
+
+import (
+	"testing"
+)
+
+// TestFuseFixtures checks that synthdefs written with Mul and Add
+// match sclang synthdefs that use MulAdd when they are fused.
+func TestFuseFixtures(t *testing.T) {
+	for _, testCase := range []struct {
+		Name      string
+		GraphFunc UgenFunc
+	}{
+		{
+			Name: "BrownNoiseTest",
+			GraphFunc: func(p Params) Ugen {
+				noise := BrownNoise{}.Rate(AR).Mul(C(100)).Add(C(200))
+				return Out{C(0), SinOsc{Freq: noise}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+			},
+		},
+		{
+			Name: "FSinOscExample",
+			GraphFunc: func(p Params) Ugen {
+				line := XLine{C(4), C(401), C(8), 0}.Rate(KR)
+				sin1 := FSinOsc{line, C(0)}.Rate(AR).Mul(C(200)).Add(C(800))
+				sin2 := FSinOsc{Freq: sin1}.Rate(AR).Mul(C(0.2))
+				return Out{C(0), sin2}.Rate(AR)
+			},
+		},
+		{
+			Name: "LFCubTest",
+			GraphFunc: func(p Params) Ugen {
+				lfo1 := LFCub{Freq: C(0.2)}.Rate(KR).Mul(C(8)).Add(C(10))
+				lfo2 := C(800).Add(LFCub{Freq: lfo1}.Rate(KR).Mul(C(400)))
+				return Out{C(0), LFCub{Freq: lfo2}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+			},
+		},
+		{
+			Name: "LFTriExample",
+			GraphFunc: func(p Params) Ugen {
+				freq := LFTri{C(4), C(0)}.Rate(KR).Mul(C(200)).Add(C(400))
+				return Out{C(0), LFTri{freq, C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+			},
+		},
+	} {
+		def := NewSynthdefWithOptions(testCase.Name, testCase.GraphFunc, SynthdefOptions{Fuse: true})
+
+		same, err := def.CompareToFile("testdata/" + testCase.Name + ".scsyndef")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !same {
+			t.Fatalf("%s: synthdef different from sclang version", testCase.Name)
+		}
+	}
+}
+
+func TestFuse(t *testing.T) {
+	for _, testCase := range []struct {
+		Name      string
+		GraphFunc UgenFunc
+		Expected  []string // Expected ugen names, in order.
+	}{
+		{
+			Name: "MulAdd",
+			GraphFunc: func(p Params) Ugen {
+				sig := SinOsc{}.Rate(AR).Mul(p.Add("amp", 0.1)).Add(Saw{}.Rate(AR))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"Control", "SinOsc", "Saw", "MulAdd", "Out"},
+		},
+		{
+			// sclang sorts the inputs of sums by rate,
+			// which reverses inputs that have the same rate.
+			Name: "Sum3",
+			GraphFunc: func(p Params) Ugen {
+				sig := SinOsc{}.Rate(AR).Add(Saw{}.Rate(AR)).Add(Pulse{}.Rate(AR))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"Pulse", "Saw", "SinOsc", "Sum3", "Out"},
+		},
+		{
+			Name: "Sum4",
+			GraphFunc: func(p Params) Ugen {
+				sig := SinOsc{}.Rate(AR).Add(Saw{}.Rate(AR)).Add(Pulse{}.Rate(AR)).Add(Blip{}.Rate(AR))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"Blip", "SinOsc", "Saw", "Pulse", "Sum4", "Out"},
+		},
+		{
+			Name: "Sum4 then Add",
+			GraphFunc: func(p Params) Ugen {
+				sig := SinOsc{}.Rate(AR).Add(Saw{}.Rate(AR)).Add(Pulse{}.Rate(AR)).Add(Blip{}.Rate(AR)).Add(Dust{}.Rate(AR))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"Blip", "SinOsc", "Saw", "Pulse", "Sum4", "Dust", "BinaryOpUGen", "Out"},
+		},
+		{
+			// Control rate inputs to MulAdd can't be mixed with audio rate mul or add.
+			Name: "Control rate with audio rate add",
+			GraphFunc: func(p Params) Ugen {
+				sig := SinOsc{}.Rate(KR).Mul(C(0.5)).Add(Saw{}.Rate(AR))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"SinOsc", "BinaryOpUGen", "Saw", "BinaryOpUGen", "Out"},
+		},
+		{
+			// A product that is used twice is not fused.
+			Name: "Shared Mul",
+			GraphFunc: func(p Params) Ugen {
+				prod := SinOsc{}.Rate(AR).Mul(C(0.5))
+				return Out{C(0), Multi(prod.Add(C(1)), prod)}.Rate(AR)
+			},
+			Expected: []string{"SinOsc", "BinaryOpUGen", "BinaryOpUGen", "Out"},
+		},
+		{
+			// Adding zero is not worth a MulAdd.
+			Name: "Add zero",
+			GraphFunc: func(p Params) Ugen {
+				sig := SinOsc{}.Rate(AR).Mul(C(0.5)).Add(C(0))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"SinOsc", "BinaryOpUGen", "BinaryOpUGen", "Out"},
+		},
+		{
+			// Every channel of a multichannel In can be fused.
+			Name: "In channels",
+			GraphFunc: func(p Params) Ugen {
+				sig := In{NumChannels: 2}.Rate(AR).Mul(C(0.5)).Add(Saw{}.Rate(AR))
+				return Out{C(0), sig}.Rate(AR)
+			},
+			Expected: []string{"In", "Saw", "MulAdd", "MulAdd", "Out"},
+		},
+	} {
+		def := NewSynthdefWithOptions(testCase.Name, testCase.GraphFunc, SynthdefOptions{Fuse: true})
+
+		if expected, got := len(testCase.Expected), len(def.Ugens); expected != got {
+			t.Fatalf("%s: expected %d ugens, got %d", testCase.Name, expected, got)
+		}
+		for i, u := range def.Ugens {
+			if expected, got := testCase.Expected[i], u.Name; expected != got {
+				t.Fatalf("%s: expected ugen %d to be %s, got %s", testCase.Name, i, expected, got)
+			}
+		}
+	}
+}
+
+func TestFuseRates(t *testing.T) {
+	def := NewSynthdefWithOptions("FuseRates", func(p Params) Ugen {
+		var (
+			freq = p.Add("freq", 440)
+			lfo  = SinOsc{Freq: C(2)}.Rate(KR)
+			sine = SinOsc{Freq: freq}.Rate(AR)
+		)
+		return Out{C(0), lfo.Add(freq).Add(sine)}.Rate(AR)
+	}, SynthdefOptions{Fuse: true})
+
+	sum := def.Ugens[len(def.Ugens)-2]
+	if expected, got := "Sum3", sum.Name; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := int8(AR), sum.Rate; expected != got {
+		t.Fatalf("expected rate %d, got %d", expected, got)
+	}
+	// Audio rate inputs come first, then control rate inputs.
+	var rates []int8
+	for _, in := range sum.Inputs {
+		rates = append(rates, def.Ugens[in.UgenIndex].Rate)
+	}
+	if expected, got := []int8{AR, KR, KR}, rates; len(expected) != len(got) || expected[0] != got[0] || expected[1] != got[1] || expected[2] != got[2] {
+		t.Fatalf("expected input rates %v, got %v", expected, got)
+	}
+}
+
+// TestFuseDisabled checks that NewSynthdef does not fuse ugens,
+// and that fusing does not change the ugen graph it is given.
+func TestFuseDisabled(t *testing.T) {
+	var (
+		sig  = SinOsc{}.Rate(AR).Mul(C(0.5)).Add(C(0.5))
+		root = Out{C(0), sig}.Rate(AR)
+		f    = func(p Params) Ugen { return root }
+	)
+	fused := NewSynthdefWithOptions("FuseDisabled", f, SynthdefOptions{Fuse: true})
+	if expected, got := 3, len(fused.Ugens); expected != got {
+		t.Fatalf("expected %d ugens, got %d", expected, got)
+	}
+	plain := NewSynthdef("FuseDisabled", f)
+	if expected, got := 4, len(plain.Ugens); expected != got {
+		t.Fatalf("expected %d ugens, got %d", expected, got)
+	}
+}
@@ -0,0 +1,961 @@
+package sc
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// goUgen describes how to create a ugen with one of the package's ugen structs.
+type goUgen struct {
+	// Struct is the name of the struct type.
+	// If it is empty the struct has the same name as the ugen.
+	Struct string
+
+	// Fields are the struct fields that set the ugen's inputs,
+	// in the order of the inputs.
+	Fields []string
+
+	// Channels is true if the last field is a (possibly multichannel)
+	// signal that takes all the remaining inputs.
+	Channels bool
+
+	// Done is true if the ugen's last input is the struct's Done field.
+	Done bool
+
+	// Set and To select the ugen with a field that is set to one of the
+	// package's constants, e.g. LFNoise1 is LFNoise{Interpolation: NoiseLinear}.
+	Set, To string
+
+	// NumChannels is true if the struct's NumChannels field sets the number of outputs.
+	NumChannels bool
+
+	// Slice is true if the last field is a []Input that takes all the remaining inputs.
+	Slice bool
+
+	// Custom is true if the inputs don't map to fields one by one,
+	// and the fields are written by goWriter.customFields.
+	Custom bool
+}
+
+// goUgens are the ugens that can be created with a ugen struct.
+var goUgens = map[string]goUgen{
+	"AllpassC":       {Struct: "Allpass", Set: "Interpolation", To: "InterpolationCubic", Fields: []string{"In", "MaxDelayTime", "DelayTime", "DecayTime"}},
+	"AllpassL":       {Struct: "Allpass", Set: "Interpolation", To: "InterpolationLinear", Fields: []string{"In", "MaxDelayTime", "DelayTime", "DecayTime"}},
+	"AllpassN":       {Struct: "Allpass", Set: "Interpolation", To: "InterpolationNone", Fields: []string{"In", "MaxDelayTime", "DelayTime", "DecayTime"}},
+	"BAllPass":       {Fields: []string{"In", "Freq", "RQ"}},
+	"BLowPass":       {Fields: []string{"In", "Freq", "RQ"}},
+	"BPF":            {Fields: []string{"In", "Freq", "RQ"}},
+	"BRF":            {Fields: []string{"In", "Freq", "RQ"}},
+	"Ball":           {Fields: []string{"In", "Gravity", "Damp", "Friction"}},
+	"Blip":           {Fields: []string{"Freq", "Harm"}},
+	"BrownNoise":     {},
+	"COsc":           {Fields: []string{"BufNum", "Freq", "Beats"}},
+	"ClipNoise":      {},
+	"CoinGate":       {Fields: []string{"Prob", "In"}},
+	"CombC":          {Struct: "Comb", Set: "Interpolation", To: "InterpolationCubic", Fields: []string{"In", "MaxDelayTime", "DelayTime", "DecayTime"}},
+	"CombL":          {Struct: "Comb", Set: "Interpolation", To: "InterpolationLinear", Fields: []string{"In", "MaxDelayTime", "DelayTime", "DecayTime"}},
+	"CombN":          {Struct: "Comb", Set: "Interpolation", To: "InterpolationNone", Fields: []string{"In", "MaxDelayTime", "DelayTime", "DecayTime"}},
+	"ControlRate":    {},
+	"Crackle":        {Fields: []string{"Chaos"}},
+	"DC":             {Fields: []string{"In"}},
+	"Decay":          {Fields: []string{"In", "Decay"}},
+	"Decay2":         {Fields: []string{"In", "Attack", "Decay"}},
+	"DelayC":         {Struct: "Delay", Set: "Interpolation", To: "InterpolationCubic", Fields: []string{"In", "MaxDelayTime", "DelayTime"}},
+	"DelayL":         {Struct: "Delay", Set: "Interpolation", To: "InterpolationLinear", Fields: []string{"In", "MaxDelayTime", "DelayTime"}},
+	"DelayN":         {Struct: "Delay", Set: "Interpolation", To: "InterpolationNone", Fields: []string{"In", "MaxDelayTime", "DelayTime"}},
+	"DetectSilence":  {Fields: []string{"In", "Amp", "Time"}, Done: true},
+	"DiskOut":        {Fields: []string{"BufNum", "Channels"}, Channels: true},
+	"Dust":           {Fields: []string{"Density"}},
+	"Dust2":          {Fields: []string{"Density"}},
+	"EnvGen":         {Custom: true},
+	"FFT":            {Fields: []string{"Buffer", "In", "Hop", "WinType", "Active", "WinSize"}},
+	"FSinOsc":        {Fields: []string{"Freq", "Phase"}},
+	"Formant":        {Fields: []string{"FundFreq", "FormantFreq", "BWFreq"}},
+	"Formlet":        {Fields: []string{"In", "Freq", "AttackTime", "DecayTime"}},
+	"FreeVerb":       {Fields: []string{"In", "Mix", "Room", "Damp"}},
+	"Gate":           {Fields: []string{"In", "Trig"}},
+	"Gendy1":         {Fields: []string{"AmpDist", "DurDist", "ADParam", "DDParam", "MinFreq", "MaxFreq", "AmpScale", "DurScale", "InitCPs", "KNum"}},
+	"Gendy2":         {Custom: true},
+	"Gendy3":         {Fields: []string{"AmpDist", "DurDist", "ADParam", "DDParam", "Freq", "AmpScale", "DurScale", "InitCPs", "KNum"}},
+	"GrainBuf":       {NumChannels: true, Fields: []string{"Trigger", "Dur", "BufNum", "Speed", "Pos", "Interp", "Pan", "EnvBuf", "MaxGrains"}},
+	"GrainFM":        {NumChannels: true, Fields: []string{"Trigger", "Dur", "CarFreq", "ModFreq", "ModIndex", "Pan", "EnvBuf", "MaxGrains"}},
+	"GrayNoise":      {},
+	"HPF":            {Fields: []string{"In", "Freq"}},
+	"Hasher":         {Fields: []string{"In"}},
+	"IFFT":           {Fields: []string{"Buffer", "WinType", "WinSize"}},
+	"Impulse":        {Fields: []string{"Freq", "Phase"}},
+	"Integrator":     {Fields: []string{"In", "Coef"}},
+	"Klank":          {Custom: true},
+	"LFClipNoise":    {Fields: []string{"Freq"}},
+	"LFCub":          {Fields: []string{"Freq", "Iphase"}},
+	"LFDClipNoise":   {Fields: []string{"Freq"}},
+	"LFGauss":        {Fields: []string{"Duration", "Width", "IPhase", "Loop"}, Done: true},
+	"LFNoise0":       {Struct: "LFNoise", Set: "Interpolation", To: "NoiseStep", Fields: []string{"Freq"}},
+	"LFNoise1":       {Struct: "LFNoise", Set: "Interpolation", To: "NoiseLinear", Fields: []string{"Freq"}},
+	"LFNoise2":       {Struct: "LFNoise", Set: "Interpolation", To: "NoiseQuadratic", Fields: []string{"Freq"}},
+	"LFPar":          {Fields: []string{"Freq", "IPhase"}},
+	"LFPulse":        {Fields: []string{"Freq", "IPhase", "Width"}},
+	"LFSaw":          {Fields: []string{"Freq", "Iphase"}},
+	"LFTri":          {Fields: []string{"Freq", "Iphase"}},
+	"LPF":            {Fields: []string{"In", "Freq"}},
+	"Lag":            {Fields: []string{"In", "LagTime"}},
+	"Latch":          {Fields: []string{"In", "Trig"}},
+	"LeakDC":         {Fields: []string{"In", "Coeff"}},
+	"Limiter":        {Fields: []string{"In", "Level", "Dur"}},
+	"Line":           {Fields: []string{"Start", "End", "Dur"}, Done: true},
+	"Median":         {Fields: []string{"Length", "In"}},
+	"MouseX":         {Fields: []string{"Min", "Max", "Warp", "Lag"}},
+	"MouseY":         {Fields: []string{"Min", "Max", "Warp", "Lag"}},
+	"NumOutputBuses": {},
+	"OffsetOut":      {Fields: []string{"Bus", "Channels"}, Channels: true},
+	"OnePole":        {Fields: []string{"In", "Coeff"}},
+	"OneZero":        {Fields: []string{"In", "Coeff"}},
+	"Osc":            {Fields: []string{"BufNum", "Freq", "Phase"}},
+	"OscN":           {Fields: []string{"BufNum", "Freq", "Phase"}},
+	"Out":            {Fields: []string{"Bus", "Channels"}, Channels: true},
+	"PSinGrain":      {Fields: []string{"Freq", "Dur", "Amp"}},
+	"PV_BrickWall":   {Struct: "PVBrickWall", Fields: []string{"Buffer", "Wipe"}},
+	"PinkNoise":      {},
+	"PlayBuf":        {NumChannels: true, Fields: []string{"BufNum", "Speed", "Trigger", "Start", "Loop"}, Done: true},
+	"Pulse":          {Fields: []string{"Freq", "Width"}},
+	"PulseCount":     {Fields: []string{"Trig", "Reset"}},
+	"PulseDivider":   {Fields: []string{"Trig", "Div", "Start"}},
+	"RLPF":           {Fields: []string{"In", "Freq", "RQ"}},
+	"Rand":           {Fields: []string{"Lo", "Hi"}},
+	"Resonz":         {Fields: []string{"In", "Freq", "BWR"}},
+	"Ringz":          {Fields: []string{"In", "Freq", "DecayTime"}},
+	"RunningSum":     {Fields: []string{"In", "NumSamp"}},
+	"SampleDur":      {},
+	"SampleRate":     {},
+	"Saw":            {Fields: []string{"Freq"}},
+	"Select":         {Fields: []string{"Which", "Inputs"}, Slice: true},
+	"Shaper":         {Fields: []string{"BufNum", "In"}},
+	"SinOsc":         {Fields: []string{"Freq", "Phase"}},
+	"SinOscFB":       {Fields: []string{"Freq", "Feedback"}},
+	"Slew":           {Fields: []string{"In", "Up", "Dn"}},
+	"Slope":          {Fields: []string{"In"}},
+	"Spring":         {Fields: []string{"In", "Spring", "Damp"}},
+	"Sweep":          {Fields: []string{"Trig", "RaiseRate"}},
+	"SyncSaw":        {Fields: []string{"SyncFreq", "SawFreq"}},
+	"TDelay":         {Fields: []string{"In", "Dur"}},
+	"TGrains":        {NumChannels: true, Fields: []string{"Trigger", "BufNum", "GRate", "CenterPos", "Dur", "Pan", "Amp", "Interp"}},
+	"TRand":          {Fields: []string{"Lo", "Hi", "Trig"}},
+	"ToggleFF":       {Fields: []string{"Trig"}},
+	"Trig":           {Fields: []string{"In", "Dur"}},
+	"Trig1":          {Fields: []string{"In", "Dur"}},
+	"VOsc":           {Fields: []string{"BufNum", "Freq", "Phase"}},
+	"VOsc3":          {Fields: []string{"BufNum", "Freq1", "Freq2", "Freq3"}},
+	"VarSaw":         {Fields: []string{"Freq", "IPhase", "Width"}},
+	"Vibrato":        {Fields: []string{"Freq", "Speed", "Depth", "Delay", "Onset", "RateVariation", "DepthVariation", "IPhase"}},
+	"Warp1":          {NumChannels: true, Fields: []string{"BufNum", "Pointer", "FreqScale", "WindowSize", "EnvBufNum", "Overlaps", "WindowRandRatio", "Interp"}},
+	"WhiteNoise":     {},
+	"XFade2":         {Fields: []string{"A", "B", "Pan", "Level"}},
+	"XLine":          {Fields: []string{"Start", "End", "Dur"}, Done: true},
+}
+
+// goEnvShapes are the names of the Env curve shapes, by shape number.
+// The custom shape (5) doesn't have a name.
+var goEnvShapes = map[int]string{
+	0: "step",
+	1: "lin",
+	2: "exp",
+	3: "sine",
+	4: "welch",
+	6: "squared",
+	7: "cubed",
+}
+
+// goKeywords are identifiers that can't be used as variable names in generated code.
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+
+	// Identifiers the generated code uses.
+	"float32": true, "math": true, "p": true, "sc": true,
+}
+
+// WriteGo writes a Go source file that recreates the synthdef.
+// The file belongs to package pkg and contains a UgenFunc
+// named after the synthdef, so
+//
+//	NewSynthdef(def.Name, f)
+//
+// creates a synthdef that is identical to def (see Diff).
+// Ugens are created with the package's ugen structs (e.g. SinOsc{...}.Rate(AR))
+// and operator methods (e.g. Mul) where possible, and with NewInput otherwise.
+// If pkg is not "sc" the file imports this package.
+// It returns an error if the ugen graph can't be expressed as a UgenFunc,
+// e.g. if more than one ugen is not an input to other ugens,
+// or if a ugen runs at a rate other than IR, KR and AR.
+func (def *Synthdef) WriteGo(w io.Writer, pkg string) error {
+	var (
+		body bytes.Buffer
+		g    = newGoWriter(def, pkg != "sc")
+	)
+	if err := g.writeFunc(&body, goName(def.Name)); err != nil {
+		return err
+	}
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated from synthdef %s. DO NOT EDIT.\n\n", strconv.Quote(def.Name))
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+
+	var imports []string
+	if g.math {
+		imports = append(imports, `"math"`)
+	}
+	if g.qualify {
+		imports = append(imports, `"github.com/scgolang/sc"`)
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(&src, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	if _, err := body.WriteTo(&src); err != nil {
+		return err
+	}
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// goWriter writes Go code for a synthdef.
+type goWriter struct {
+	def     *Synthdef
+	qualify bool // qualify says whether identifiers from this package need a package name
+	math    bool // math says whether the code uses the math package
+
+	refs    []int    // refs counts how many times each ugen is used as an input
+	vars    []string // vars are the names of the variables that hold ugens
+	params  []string // params are the names of the variables that hold params
+	names   map[string]bool
+	control int // control is the index of the Control ugen, or -1
+}
+
+// newGoWriter creates a new goWriter.
+func newGoWriter(def *Synthdef, qualify bool) *goWriter {
+	return &goWriter{
+		def:     def,
+		qualify: qualify,
+		refs:    make([]int, len(def.Ugens)),
+		vars:    make([]string, len(def.Ugens)),
+		names:   map[string]bool{},
+		control: -1,
+	}
+}
+
+// writeFunc writes the UgenFunc for the synthdef.
+func (g *goWriter) writeFunc(w io.Writer, name string) error {
+	if err := g.def.validateGraph(); err != nil {
+		return err
+	}
+	root, err := g.analyze()
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+
+	if err := g.writeParams(&body); err != nil {
+		return err
+	}
+	for i, u := range g.def.Ugens {
+		if i == g.control || i == root || g.refs[i] < 2 {
+			continue
+		}
+		expr, err := g.ugen(i)
+		if err != nil {
+			return err
+		}
+		g.vars[i] = g.name(varName(u))
+		fmt.Fprintf(&body, "%s := %s\n", g.vars[i], expr)
+	}
+	expr, err := g.root(root)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&body, "return %s\n", expr)
+
+	fmt.Fprintf(w, "// %s is the ugen graph of the %s synthdef.\n", name, g.def.Name)
+	fmt.Fprintf(w, "func %s(p %s) %s {\n%s}\n", name, g.q("Params"), g.q("Ugen"), body.String())
+	return nil
+}
+
+// analyze counts the references to each ugen, finds the Control ugen,
+// and returns the index of the root ugen.
+func (g *goWriter) analyze() (int, error) {
+	for i, u := range g.def.Ugens {
+		rate, err := goRate(i, u)
+		if err != nil {
+			return -1, err
+		}
+		if _, ok := controlUgens[u.Name]; ok {
+			if u.Name != "Control" || u.Rate != KR || g.control >= 0 {
+				return -1, fmt.Errorf("ugen %d (%s %s): only a single control rate Control is supported", i, u.Name, rate)
+			}
+			g.control = i
+		}
+		args, err := g.args(i)
+		if err != nil {
+			return -1, err
+		}
+		for _, arg := range args {
+			if !arg.IsConstant() && int(arg.UgenIndex) != g.control {
+				g.refs[arg.UgenIndex]++
+			}
+		}
+	}
+	root := -1
+	for i, count := range g.refs {
+		if count > 0 || i == g.control {
+			continue
+		}
+		if root >= 0 {
+			return -1, fmt.Errorf("ugens %d (%s) and %d (%s) are not inputs to other ugens, but a UgenFunc can only return one ugen", root, g.def.Ugens[root].Name, i, g.def.Ugens[i].Name)
+		}
+		root = i
+	}
+	if root < 0 {
+		return -1, fmt.Errorf("synthdef %s does not have a root ugen", g.def.Name)
+	}
+	return root, nil
+}
+
+// args returns the arguments of the ugen at index i.
+// Consecutive inputs that read all the outputs of a multichannel ugen
+// are a single argument, which refers to the ugen's first output.
+func (g *goWriter) args(i int) ([]UgenInput, error) {
+	var (
+		u    = g.def.Ugens[i]
+		args []UgenInput
+	)
+	for j := 0; j < len(u.Inputs); j++ {
+		in := u.Inputs[j]
+		if in.IsConstant() || int(in.UgenIndex) == g.control {
+			args = append(args, in)
+			continue
+		}
+		from := g.def.Ugens[in.UgenIndex]
+		if n := len(from.Outputs); n > 1 {
+			if in.OutputIndex != 0 || j+n > len(u.Inputs) {
+				return nil, g.outputsError(i, int(in.UgenIndex))
+			}
+			for k := 1; k < n; k++ {
+				if next := u.Inputs[j+k]; next.UgenIndex != in.UgenIndex || int(next.OutputIndex) != k {
+					return nil, g.outputsError(i, int(in.UgenIndex))
+				}
+			}
+			j += n - 1
+		}
+		args = append(args, in)
+	}
+	return args, nil
+}
+
+// outputsError returns the error for a ugen that doesn't read all the outputs of another ugen.
+func (g *goWriter) outputsError(i, from int) error {
+	return fmt.Errorf("ugen %d (%s) does not read all the outputs of ugen %d (%s) in order", i, g.def.Ugens[i].Name, from, g.def.Ugens[from].Name)
+}
+
+// writeParams declares variables for the synthdef params.
+func (g *goWriter) writeParams(w io.Writer) error {
+	if g.control < 0 {
+		return nil
+	}
+	var (
+		numParams = len(g.def.InitialParamValues)
+		names     = make([]string, numParams)
+	)
+	if n := len(g.def.Ugens[g.control].Outputs); n != numParams {
+		return fmt.Errorf("Control has %d outputs, but there are %d params", n, numParams)
+	}
+	for _, pn := range g.def.ParamNames {
+		if pn.Index < 0 || int(pn.Index) >= numParams {
+			return fmt.Errorf("param %s has invalid index %d", pn.Name, pn.Index)
+		}
+		names[pn.Index] = pn.Name
+	}
+	used := make([]bool, numParams)
+	for _, u := range g.def.Ugens {
+		for _, in := range u.Inputs {
+			if int(in.UgenIndex) == g.control {
+				used[in.OutputIndex] = true
+			}
+		}
+	}
+	g.params = make([]string, numParams)
+
+	for i, name := range names {
+		if name == "" {
+			return fmt.Errorf("param %d does not have a name", i)
+		}
+		call := fmt.Sprintf("p.Add(%s, %s)", strconv.Quote(name), g.float(g.def.InitialParamValues[i]))
+		if !used[i] {
+			fmt.Fprintln(w, call)
+			continue
+		}
+		g.params[i] = g.name(lowerFirst(identifier(name)))
+		fmt.Fprintf(w, "%s := %s\n", g.params[i], call)
+	}
+	return nil
+}
+
+// root returns the expression for the root ugen, which must be a Ugen.
+func (g *goWriter) root(i int) (string, error) {
+	u := g.def.Ugens[i]
+	if (u.Name == "Out" || u.Name == "DiskOut") && u.SpecialIndex == 0 {
+		if expr, ok, err := g.structUgen(i); ok || err != nil {
+			return expr, err
+		}
+	}
+	rate, err := goRate(i, u)
+	if err != nil {
+		return "", err
+	}
+	args, err := g.argExprs(i)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("*%s(%s, %s, %d, %d%s)", g.q("NewUgen"), strconv.Quote(u.Name), g.q(rate), u.SpecialIndex, numOutputs(u), joinArgs(args)), nil
+}
+
+// input returns the expression for a ugen input.
+func (g *goWriter) input(in UgenInput) (string, error) {
+	if in.IsConstant() {
+		return g.constant(g.def.Constants[in.OutputIndex]), nil
+	}
+	if int(in.UgenIndex) == g.control {
+		return g.params[in.OutputIndex], nil
+	}
+	if v := g.vars[in.UgenIndex]; v != "" {
+		return v, nil
+	}
+	return g.ugen(int(in.UgenIndex))
+}
+
+// argExprs returns the expressions for the arguments of the ugen at index i.
+func (g *goWriter) argExprs(i int) ([]string, error) {
+	args, err := g.args(i)
+	if err != nil {
+		return nil, err
+	}
+	return g.inputs(args)
+}
+
+// ugen returns the expression that creates the ugen at index i.
+func (g *goWriter) ugen(i int) (string, error) {
+	u := g.def.Ugens[i]
+
+	if expr, ok, err := g.structUgen(i); ok || err != nil {
+		return expr, err
+	}
+	if expr, ok, err := g.method(i); ok || err != nil {
+		return expr, err
+	}
+	rate, err := goRate(i, u)
+	if err != nil {
+		return "", err
+	}
+	args, err := g.argExprs(i)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case u.Name == "In" && u.SpecialIndex == 0 && len(args) == 1 && len(u.Outputs) > 1:
+		return fmt.Sprintf("%s{NumChannels: %d, Bus: %s}.Rate(%s)", g.q("In"), numOutputs(u), args[0], g.q(rate)), nil
+	case (u.Name == "Sum3" || u.Name == "Sum4") && u.SpecialIndex == 0 && len(u.Outputs) == 1 && len(args) == len(u.Inputs):
+		return fmt.Sprintf("%s(%s%s)", g.q(u.Name), g.q(rate), joinArgs(args)), nil
+	}
+	return fmt.Sprintf("%s(%s, %s, %s, %d%s)", g.q("NewInput"), g.ugenName(u.Name), g.q(rate), g.specialIndex(u), numOutputs(u), joinArgs(args)), nil
+}
+
+// structUgen returns the expression that creates the ugen at index i
+// with one of the package's ugen structs.
+// The bool is false if there is no struct for the ugen.
+func (g *goWriter) structUgen(i int) (string, bool, error) {
+	var (
+		u      = g.def.Ugens[i]
+		gu, ok = goUgens[u.Name]
+	)
+	if !ok || u.SpecialIndex != 0 || (len(u.Outputs) > 1 && !gu.NumChannels) {
+		return "", false, nil
+	}
+	args, err := g.args(i)
+	if err != nil {
+		return "", false, err
+	}
+	var fields []string
+	if gu.Custom {
+		// Every input of a custom ugen is a separate field or element.
+		if len(args) != len(u.Inputs) {
+			return "", false, nil
+		}
+		fields, ok, err = g.customFields(u.Name, args)
+	} else {
+		fields, ok, err = g.fields(gu, args, len(u.Inputs))
+	}
+	if !ok || err != nil {
+		return "", false, err
+	}
+	if gu.Set != "" {
+		fields = append([]string{gu.Set + ": " + g.q(gu.To)}, fields...)
+	}
+	if gu.NumChannels && len(u.Outputs) > 1 {
+		fields = append([]string{"NumChannels: " + strconv.Itoa(len(u.Outputs))}, fields...)
+	}
+	rate, err := goRate(i, u)
+	if err != nil {
+		return "", false, err
+	}
+	name := gu.Struct
+	if name == "" {
+		name = u.Name
+	}
+	return fmt.Sprintf("%s{%s}.Rate(%s)", g.q(name), strings.Join(fields, ", "), g.q(rate)), true, nil
+}
+
+// fields returns the struct fields that set the ugen args, one field per arg.
+// numInputs is the number of inputs of the ugen.
+// The bool is false if the args don't match the fields.
+func (g *goWriter) fields(gu goUgen, args []UgenInput, numInputs int) ([]string, bool, error) {
+	nfields := len(gu.Fields)
+
+	switch {
+	case gu.Channels && len(args) < nfields:
+		return nil, false, nil
+	case gu.Slice && (len(args) < nfields-1 || len(args) != numInputs):
+		// A multichannel element would expand the ugen instead of
+		// being read as separate inputs.
+		return nil, false, nil
+	case gu.Done && len(args) != nfields+1:
+		return nil, false, nil
+	case !gu.Channels && !gu.Slice && !gu.Done && len(args) != nfields:
+		return nil, false, nil
+	}
+	var done string
+	if gu.Done {
+		v, ok := g.intConstant(args[nfields])
+		if !ok {
+			return nil, false, nil
+		}
+		done = strconv.Itoa(v)
+		args = args[:nfields]
+	}
+	exprs, err := g.inputs(args)
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case gu.Channels && len(exprs) > nfields:
+		exprs[nfields-1] = fmt.Sprintf("%s(%s)", g.q("Multi"), strings.Join(exprs[nfields-1:], ", "))
+		exprs = exprs[:nfields]
+	case gu.Slice:
+		exprs = append(exprs[:nfields-1], g.slice(exprs[nfields-1:]))
+	}
+	fields := make([]string, 0, nfields+1)
+	for j, field := range gu.Fields {
+		fields = append(fields, field+": "+exprs[j])
+	}
+	if done != "" {
+		fields = append(fields, "Done: "+done)
+	}
+	return fields, true, nil
+}
+
+// customFields returns the struct fields for the ugens in goUgens
+// that have Custom set.
+// The bool is false if the args can't be written as struct fields.
+func (g *goWriter) customFields(name string, args []UgenInput) ([]string, bool, error) {
+	switch name {
+	case "EnvGen":
+		return g.envGenFields(args)
+	case "Gendy2":
+		return g.gendy2Fields(args)
+	case "Klank":
+		return g.klankFields(args)
+	}
+	return nil, false, nil
+}
+
+// envGenFields returns the EnvGen fields for the args of an EnvGen.
+// The args are the gate, level scale, level bias, time scale and done action,
+// followed by the inputs of an Env.
+func (g *goWriter) envGenFields(args []UgenInput) ([]string, bool, error) {
+	if len(args) < 9 || (len(args)-5)%4 != 0 {
+		return nil, false, nil
+	}
+	var (
+		env         = args[5:]
+		numSegments = len(env)/4 - 1
+		curves      = make([]string, numSegments)
+	)
+	done, ok := g.intConstant(args[4])
+	if !ok {
+		return nil, false, nil
+	}
+	if n, ok := g.intConstant(env[1]); !ok || n != numSegments {
+		return nil, false, nil
+	}
+	// Named shapes don't have a curvature,
+	// and custom shapes (5) are written as the curvature.
+	for j := range curves {
+		shape, ok := g.intConstant(env[6+4*j])
+		if !ok {
+			return nil, false, nil
+		}
+		if shape == 5 {
+			continue
+		}
+		curve, ok := g.intConstant(env[7+4*j])
+		if !ok || curve != 0 || goEnvShapes[shape] == "" {
+			return nil, false, nil
+		}
+		curves[j] = strconv.Quote(goEnvShapes[shape])
+	}
+	exprs, err := g.inputs(args)
+	if err != nil {
+		return nil, false, err
+	}
+	var (
+		levels = []string{exprs[5]}
+		times  = make([]string, numSegments)
+	)
+	for j := range curves {
+		levels = append(levels, exprs[9+4*j])
+		times[j] = exprs[10+4*j]
+		if curves[j] == "" {
+			curves[j] = exprs[12+4*j]
+		}
+	}
+	envFields := []string{
+		"Levels: " + g.slice(levels),
+		"Times: " + g.slice(times),
+	}
+	if curve, ok := envCurve(curves); ok {
+		envFields = append(envFields, "Curve: "+curve)
+	}
+	if v, ok := g.intConstant(env[2]); !ok || v != -99 {
+		envFields = append(envFields, "ReleaseNode: "+exprs[7])
+	}
+	if v, ok := g.intConstant(env[3]); !ok || v != -99 {
+		envFields = append(envFields, "LoopNode: "+exprs[8])
+	}
+	return []string{
+		fmt.Sprintf("Env: %s{%s}", g.q("Env"), strings.Join(envFields, ", ")),
+		"Gate: " + exprs[0],
+		"LevelScale: " + exprs[1],
+		"LevelBias: " + exprs[2],
+		"TimeScale: " + exprs[3],
+		"Done: " + strconv.Itoa(done),
+	}, true, nil
+}
+
+// envCurve returns the value of the Curve field of an Env.
+// The bool is false if all the curves are linear, which is the default.
+func envCurve(curves []string) (string, bool) {
+	same := true
+	for _, curve := range curves {
+		same = same && curve == curves[0]
+	}
+	switch {
+	case len(curves) == 0 || (same && curves[0] == `"lin"`):
+		return "", false
+	case same:
+		return curves[0], true
+	}
+	return fmt.Sprintf("[]interface{}{%s}", strings.Join(curves, ", ")), true
+}
+
+// gendy2Fields returns the Gendy2 fields for the args of a Gendy2,
+// which are the args of a Gendy1 followed by A and C.
+func (g *goWriter) gendy2Fields(args []UgenInput) ([]string, bool, error) {
+	gendy1 := goUgens["Gendy1"]
+
+	if len(args) != len(gendy1.Fields)+2 {
+		return nil, false, nil
+	}
+	fields, ok, err := g.fields(gendy1, args[:len(gendy1.Fields)], len(gendy1.Fields))
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	exprs, err := g.inputs(args[len(gendy1.Fields):])
+	if err != nil {
+		return nil, false, err
+	}
+	return []string{
+		fmt.Sprintf("Gendy1: %s{%s}", g.q("Gendy1"), strings.Join(fields, ", ")),
+		"A: " + exprs[0],
+		"C: " + exprs[1],
+	}, true, nil
+}
+
+// klankFields returns the Klank fields for the args of a Klank.
+// The args are the input, frequency scale, frequency offset and decay scale,
+// followed by the frequency, amplitude and decay time of every resonator.
+func (g *goWriter) klankFields(args []UgenInput) ([]string, bool, error) {
+	if len(args) < 4 || (len(args)-4)%3 != 0 {
+		return nil, false, nil
+	}
+	exprs, err := g.inputs(args)
+	if err != nil {
+		return nil, false, err
+	}
+	var spec [3][]string
+	for j := 4; j < len(exprs); j += 3 {
+		for k := range spec {
+			spec[k] = append(spec[k], exprs[j+k])
+		}
+	}
+	return []string{
+		"In: " + exprs[0],
+		fmt.Sprintf("Spec: %s{%s, %s, %s}", g.q("ArraySpec"), g.slice(spec[0]), g.slice(spec[1]), g.slice(spec[2])),
+		"FreqScale: " + exprs[1],
+		"FreqOffset: " + exprs[2],
+		"DecayScale: " + exprs[3],
+	}, true, nil
+}
+
+// inputs returns the expressions for ugen args.
+func (g *goWriter) inputs(args []UgenInput) ([]string, error) {
+	exprs := make([]string, len(args))
+	for j, arg := range args {
+		var err error
+		if exprs[j], err = g.input(arg); err != nil {
+			return nil, err
+		}
+	}
+	return exprs, nil
+}
+
+// slice returns the expression for a []Input.
+func (g *goWriter) slice(exprs []string) string {
+	return fmt.Sprintf("[]%s{%s}", g.q("Input"), strings.Join(exprs, ", "))
+}
+
+// intConstant returns the value of an arg that is an integer constant.
+// The bool is false if the arg is not an integer constant.
+func (g *goWriter) intConstant(arg UgenInput) (int, bool) {
+	if !arg.IsConstant() {
+		return 0, false
+	}
+	v := g.def.Constants[arg.OutputIndex]
+	if v != float32(int(v)) {
+		return 0, false
+	}
+	return int(v), true
+}
+
+// method returns the expression that creates the ugen at index i
+// by calling an Input method on its first input.
+// The bool is false if the ugen can't be created with a method.
+func (g *goWriter) method(i int) (string, bool, error) {
+	var (
+		u      = g.def.Ugens[i]
+		op, ok = lookupOp(u)
+		name   string
+	)
+	switch {
+	case ok && u.Name == BinOpUgenName && len(u.Inputs) == 2:
+		name = op.method
+	case ok && u.Name == UnaryOpUgenName && len(u.Inputs) == 1:
+		name = op.method
+	case u.Name == "MulAdd" && u.SpecialIndex == 0 && len(u.Inputs) == 3:
+		name = "MulAdd"
+	}
+	if name == "" || len(u.Outputs) != 1 || !g.isReceiver(u.Inputs[0], u.Rate) {
+		return "", false, nil
+	}
+	exprs := make([]string, len(u.Inputs))
+	for j, in := range u.Inputs {
+		if !in.IsConstant() && int(in.UgenIndex) != g.control && len(g.def.Ugens[in.UgenIndex].Outputs) != 1 {
+			return "", false, nil
+		}
+		var err error
+		if exprs[j], err = g.input(in); err != nil {
+			return "", false, err
+		}
+	}
+	return fmt.Sprintf("%s.%s(%s)", exprs[0], name, strings.Join(exprs[1:], ", ")), true, nil
+}
+
+// isReceiver returns true if calling an operator method on in
+// creates a ugen with the given rate.
+// Methods on constants are evaluated in Go, and methods on params
+// always create control rate ugens.
+func (g *goWriter) isReceiver(in UgenInput, rate int8) bool {
+	if in.IsConstant() {
+		return false
+	}
+	if int(in.UgenIndex) == g.control {
+		return rate == KR
+	}
+	from := g.def.Ugens[in.UgenIndex]
+	return len(from.Outputs) == 1 && from.Rate == rate
+}
+
+// constant returns the expression for a constant.
+func (g *goWriter) constant(v float32) string {
+	return fmt.Sprintf("%s(%s)", g.q("C"), g.float(v))
+}
+
+// float returns a float32 literal.
+func (g *goWriter) float(v float32) string {
+	switch {
+	case math.IsInf(float64(v), 1):
+		g.math = true
+		return "float32(math.Inf(1))"
+	case math.IsInf(float64(v), -1):
+		g.math = true
+		return "float32(math.Inf(-1))"
+	case math.IsNaN(float64(v)):
+		g.math = true
+		return "float32(math.NaN())"
+	}
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+// specialIndex returns the expression for a ugen's special index.
+func (g *goWriter) specialIndex(u *Ugen) string {
+	if op, ok := lookupOp(u); ok && op.method != "" {
+		if u.Name == BinOpUgenName {
+			return g.q("BinOp" + op.method)
+		}
+		return g.q("UnaryOp" + op.method)
+	}
+	return strconv.Itoa(int(u.SpecialIndex))
+}
+
+// ugenName returns the expression for a ugen name.
+func (g *goWriter) ugenName(name string) string {
+	switch name {
+	case BinOpUgenName:
+		return g.q("BinOpUgenName")
+	case UnaryOpUgenName:
+		return g.q("UnaryOpUgenName")
+	}
+	return strconv.Quote(name)
+}
+
+// name returns a variable name that has not been used yet.
+func (g *goWriter) name(base string) string {
+	name := base
+	for i := 2; g.names[name] || goKeywords[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// q qualifies an identifier from this package.
+func (g *goWriter) q(ident string) string {
+	if g.qualify {
+		return "sc." + ident
+	}
+	return ident
+}
+
+// varName returns the base name of the variable that holds a ugen.
+func varName(u *Ugen) string {
+	if op, ok := lookupOp(u); ok && op.method != "" {
+		return lowerFirst(op.method)
+	}
+	switch u.Name {
+	case BinOpUgenName:
+		return "binOp"
+	case UnaryOpUgenName:
+		return "unaryOp"
+	}
+	return lowerFirst(identifier(u.Name))
+}
+
+// goName returns an exported Go identifier for a synthdef name.
+func goName(name string) string {
+	var (
+		b     strings.Builder
+		upper = true
+	)
+	for _, r := range name {
+		if !isIdentRune(r) || r == '_' {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "Synthdef" + s
+	}
+	return s
+}
+
+// identifier replaces the characters of s that can't be used in Go identifiers.
+func identifier(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if !isIdentRune(r) {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if s := b.String(); s != "" && (s[0] < '0' || s[0] > '9') {
+		return s
+	}
+	return "x" + b.String()
+}
+
+// isIdentRune returns true for the ASCII characters that can be used in Go identifiers.
+func isIdentRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// lowerFirst lower-cases the leading upper case letters of an identifier,
+// e.g. SinOsc becomes sinOsc and LFSaw becomes lfSaw.
+func lowerFirst(s string) string {
+	b := []byte(s)
+	for i := 0; i < len(b) && b[i] >= 'A' && b[i] <= 'Z'; i++ {
+		// Keep the last upper case letter of a run if it starts a new word.
+		if i > 0 && i+1 < len(b) && b[i+1] >= 'a' && b[i+1] <= 'z' {
+			break
+		}
+		b[i] += 'a' - 'A'
+	}
+	return string(b)
+}
+
+// joinArgs joins function arguments that follow other arguments.
+func joinArgs(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
+
+// numOutputs returns the number of outputs to create a ugen with.
+func numOutputs(u *Ugen) int {
+	if n := len(u.Outputs); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// goRate returns the name of the constant for the rate of the ugen at index i.
+// It returns an error if the rate can't be used in Go, e.g. demand rate.
+func goRate(i int, u *Ugen) (string, error) {
+	name := rateName(u.Rate, "")
+	if name == "" {
+		return "", fmt.Errorf("ugen %d (%s) has rate %d, but only IR, KR and AR ugens can be created in Go", i, u.Name, u.Rate)
+	}
+	return strings.ToUpper(name), nil
+}
@@ -0,0 +1,1201 @@
+// Code generated by go test -run TestWriteGoFixtures -update. DO NOT EDIT.
+
+package sc
+
+// goFixtureFuncs are the ugen graphs of the synthdefs in testdata, by synthdef name.
+var goFixtureFuncs = map[string]UgenFunc{
+	"AllpassExample":    goFixtureAllpassExample,
+	"AllpassnExample":   goFixtureAllpassnExample,
+	"BAllPassExample":   goFixtureBAllPassExample,
+	"BLowPassTest":      goFixtureBLowPassTest,
+	"BPFExample":        goFixtureBPFExample,
+	"BRFExample":        goFixtureBRFExample,
+	"Balance2Test":      goFixtureBalance2Test,
+	"BallTest":          goFixtureBallTest,
+	"Beats":             goFixtureBeats,
+	"BlipExample":       goFixtureBlipExample,
+	"BrownNoiseTest":    goFixtureBrownNoiseTest,
+	"COscTest":          goFixtureCOscTest,
+	"Cascade":           goFixtureCascade,
+	"CascadeExample":    goFixtureCascadeExample,
+	"ClipNoiseTest":     goFixtureClipNoiseTest,
+	"CoinGateTest":      goFixtureCoinGateTest,
+	"CombCTest":         goFixtureCombCTest,
+	"CombLTest":         goFixtureCombLTest,
+	"CombNTest":         goFixtureCombNTest,
+	"CrackleTest":       goFixtureCrackleTest,
+	"DCTest":            goFixtureDCTest,
+	"Decay2Test":        goFixtureDecay2Test,
+	"DelayCTest":        goFixtureDelayCTest,
+	"DelayLTest":        goFixtureDelayLTest,
+	"DelayNTest":        goFixtureDelayNTest,
+	"Dust2Test":         goFixtureDust2Test,
+	"DustTest":          goFixtureDustTest,
+	"Envgen1":           goFixtureEnvgen1,
+	"EnvgenTest":        goFixtureEnvgenTest,
+	"FFTTest":           goFixtureFFTTest,
+	"FSinOscExample":    goFixtureFSinOscExample,
+	"FittonBubbles":     goFixtureFittonBubbles,
+	"FormantTest":       goFixtureFormantTest,
+	"FormletTest":       goFixtureFormletTest,
+	"FreeVerbExample":   goFixtureFreeVerbExample,
+	"FreeVerbTest":      goFixtureFreeVerbTest,
+	"GVerbExample":      goFixtureGVerbExample,
+	"GateTest":          goFixtureGateTest,
+	"Gendy1Test":        goFixtureGendy1Test,
+	"Gendy2Test":        goFixtureGendy2Test,
+	"Gendy3Test":        goFixtureGendy3Test,
+	"GrainBufTest":      goFixtureGrainBufTest,
+	"GrainFMExample":    goFixtureGrainFMExample,
+	"HPFExample":        goFixtureHPFExample,
+	"HasherTest":        goFixtureHasherTest,
+	"ImpulseExample":    goFixtureImpulseExample,
+	"IntegratorExample": goFixtureIntegratorExample,
+	"KlankTest1":        goFixtureKlankTest1,
+	"LFClipNoiseTest":   goFixtureLFClipNoiseTest,
+	"LFCubTest":         goFixtureLFCubTest,
+	"LFDClipNoiseTest":  goFixtureLFDClipNoiseTest,
+	"LFGaussTest":       goFixtureLFGaussTest,
+	"LFNoise1Example":   goFixtureLFNoise1Example,
+	"LFParTest":         goFixtureLFParTest,
+	"LFPulseTest":       goFixtureLFPulseTest,
+	"LFSawExample":      goFixtureLFSawExample,
+	"LFTriExample":      goFixtureLFTriExample,
+	"LPFExample":        goFixtureLPFExample,
+	"LagTest":           goFixtureLagTest,
+	"LatchTest":         goFixtureLatchTest,
+	"LeakDCTest":        goFixtureLeakDCTest,
+	"LinPan2Test":       goFixtureLinPan2Test,
+	"LinXFade2Test":     goFixtureLinXFade2Test,
+	"LineTest":          goFixtureLineTest,
+	"MedianTest":        goFixtureMedianTest,
+	"MixTest":           goFixtureMixTest,
+	"OnePoleTest":       goFixtureOnePoleTest,
+	"OneZeroTest":       goFixtureOneZeroTest,
+	"OscNTest":          goFixtureOscNTest,
+	"OscTest":           goFixtureOscTest,
+	"PMOscTest":         goFixturePMOscTest,
+	"PSinGrainTest":     goFixturePSinGrainTest,
+	"Pan2Test":          goFixturePan2Test,
+	"Pan4Test":          goFixturePan4Test,
+	"PanAzTest":         goFixturePanAzTest,
+	"PlayBufExample":    goFixturePlayBufExample,
+	"PulseCountTest":    goFixturePulseCountTest,
+	"PulseDividerTest":  goFixturePulseDividerTest,
+	"PulseTest":         goFixturePulseTest,
+	"RLPFTest":          goFixtureRLPFTest,
+	"ResonzTest":        goFixtureResonzTest,
+	"RingzTest":         goFixtureRingzTest,
+	"RunningSumTest":    goFixtureRunningSumTest,
+	"SameSame":          goFixtureSameSame,
+	"SawTone1":          goFixtureSawTone1,
+	"SelectTest":        goFixtureSelectTest,
+	"ShaperTest":        goFixtureShaperTest,
+	"SilentTest":        goFixtureSilentTest,
+	"SimpleMulti":       goFixtureSimpleMulti,
+	"SinOscFBTest":      goFixtureSinOscFBTest,
+	"SineTone":          goFixtureSineTone,
+	"SineTone2":         goFixtureSineTone2,
+	"SineTone3":         goFixtureSineTone3,
+	"SineTone4":         goFixtureSineTone4,
+	"SlewTest":          goFixtureSlewTest,
+	"SlopeTest":         goFixtureSlopeTest,
+	"SoundInTest0":      goFixtureSoundInTest0,
+	"SoundInTest00":     goFixtureSoundInTest00,
+	"SoundInTest01":     goFixtureSoundInTest01,
+	"SoundInTest02":     goFixtureSoundInTest02,
+	"SoundInTest12":     goFixtureSoundInTest12,
+	"SoundInTest20":     goFixtureSoundInTest20,
+	"SpringTest":        goFixtureSpringTest,
+	"Sum3Test":          goFixtureSum3Test,
+	"SweepTest":         goFixtureSweepTest,
+	"SyncSawTest":       goFixtureSyncSawTest,
+	"TDelayTest":        goFixtureTDelayTest,
+	"TGrainsExample":    goFixtureTGrainsExample,
+	"TestEnvADSR":       goFixtureTestEnvADSR,
+	"Trig1Test":         goFixtureTrig1Test,
+	"TrigTest":          goFixtureTrigTest,
+	"UseParam":          goFixtureUseParam,
+	"VOsc3Test":         goFixtureVOsc3Test,
+	"VOscTest":          goFixtureVOscTest,
+	"VarSawTest":        goFixtureVarSawTest,
+	"VibratoTest":       goFixtureVibratoTest,
+	"Warp1Example":      goFixtureWarp1Example,
+	"XFade2Test":        goFixtureXFade2Test,
+	"XLineTest":         goFixtureXLineTest,
+	"absExample":        goFixtureAbsExample,
+	"absdifExample":     goFixtureAbsdifExample,
+	"acosExample":       goFixtureAcosExample,
+	"amclipExample":     goFixtureAmclipExample,
+	"ampdbExample":      goFixtureAmpdbExample,
+	"asinExample":       goFixtureAsinExample,
+	"atan2Example":      goFixtureAtan2Example,
+	"atanExample":       goFixtureAtanExample,
+	"bar":               goFixtureBar,
+	"baz":               goFixtureBaz,
+	"bilinrandExample":  goFixtureBilinrandExample,
+	"ceilExample":       goFixtureCeilExample,
+	"clip2Example":      goFixtureClip2Example,
+	"coinExample":       goFixtureCoinExample,
+	"cosExample":        goFixtureCosExample,
+	"coshExample":       goFixtureCoshExample,
+	"cpsmidiExample":    goFixtureCpsmidiExample,
+	"cpsoctExample":     goFixtureCpsoctExample,
+	"cubedExample":      goFixtureCubedExample,
+	"dbampExample":      goFixtureDbampExample,
+	"defWith2Params":    goFixtureDefWith2Params,
+	"difsqrExample":     goFixtureDifsqrExample,
+	"distortExample":    goFixtureDistortExample,
+	"divExample":        goFixtureDivExample,
+	"excessExample":     goFixtureExcessExample,
+	"expExample":        goFixtureExpExample,
+	"exponExample":      goFixtureExponExample,
+	"floorExample":      goFixtureFloorExample,
+	"fold2Example":      goFixtureFold2Example,
+	"foo":               goFixtureFoo,
+	"fracExample":       goFixtureFracExample,
+	"gcdExample":        goFixtureGcdExample,
+	"gtExample":         goFixtureGtExample,
+	"gteExample":        goFixtureGteExample,
+	"hypotExample":      goFixtureHypotExample,
+	"hypotapxExample":   goFixtureHypotapxExample,
+	"lcmExample":        goFixtureLcmExample,
+	"linrandExample":    goFixtureLinrandExample,
+	"log10Example":      goFixtureLog10Example,
+	"log2Example":       goFixtureLog2Example,
+	"logExample":        goFixtureLogExample,
+	"ltExample":         goFixtureLtExample,
+	"lteExample":        goFixtureLteExample,
+	"midiratioExample":  goFixtureMidiratioExample,
+	"minExample":        goFixtureMinExample,
+	"moddifExample":     goFixtureModdifExample,
+	"moduloExample":     goFixtureModuloExample,
+	"negExample":        goFixtureNegExample,
+	"octcpsExample":     goFixtureOctcpsExample,
+	"powExample":        goFixturePowExample,
+	"rand2Example":      goFixtureRand2Example,
+	"randExample":       goFixtureRandExample,
+	"ratiomidiExample":  goFixtureRatiomidiExample,
+	"reciprocalExample": goFixtureReciprocalExample,
+	"ring1Example":      goFixtureRing1Example,
+	"ring2Example":      goFixtureRing2Example,
+	"ring3Example":      goFixtureRing3Example,
+	"ring4Example":      goFixtureRing4Example,
+	"roundExample":      goFixtureRoundExample,
+	"scalenegExample":   goFixtureScalenegExample,
+	"signExample":       goFixtureSignExample,
+	"sinExample":        goFixtureSinExample,
+	"sinhExample":       goFixtureSinhExample,
+	"sqrdifExample":     goFixtureSqrdifExample,
+	"sqrsumExample":     goFixtureSqrsumExample,
+	"sqrtExample":       goFixtureSqrtExample,
+	"squaredExample":    goFixtureSquaredExample,
+	"sub":               goFixtureSub,
+	"sum3randExample":   goFixtureSum3randExample,
+	"sumsqrExample":     goFixtureSumsqrExample,
+	"tanExample":        goFixtureTanExample,
+	"tanhExample":       goFixtureTanhExample,
+	"threshExample":     goFixtureThreshExample,
+	"truncExample":      goFixtureTruncExample,
+	"wrap2Example":      goFixtureWrap2Example,
+}
+
+// goFixtureAllpassExample is the ugen graph of the AllpassExample synthdef.
+func goFixtureAllpassExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Allpass{Interpolation: InterpolationCubic, In: Decay{In: Dust{Density: C(1)}.Rate(AR).Mul(C(0.5)), Decay: C(0.2)}.Rate(AR).Mul(WhiteNoise{}.Rate(AR)), MaxDelayTime: C(0.2), DelayTime: C(0.2), DecayTime: C(3)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureAllpassnExample is the ugen graph of the AllpassnExample synthdef.
+func goFixtureAllpassnExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Allpass{Interpolation: InterpolationNone, In: Decay{In: Dust{Density: C(1)}.Rate(AR).Mul(C(0.5)), Decay: C(0.2)}.Rate(AR).Mul(WhiteNoise{}.Rate(AR)), MaxDelayTime: C(0.2), DelayTime: C(0.2), DecayTime: C(3)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureBAllPassExample is the ugen graph of the BAllPassExample synthdef.
+func goFixtureBAllPassExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: BAllPass{In: Saw{Freq: C(440)}.Rate(AR), Freq: MouseX{Min: C(10), Max: C(18000), Warp: C(1), Lag: C(0.2)}.Rate(KR), RQ: C(0.8)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureBLowPassTest is the ugen graph of the BLowPassTest synthdef.
+func goFixtureBLowPassTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: BLowPass{In: Blip{Freq: C(400), Harm: C(4)}.Rate(AR), Freq: C(300), RQ: C(0.5)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureBPFExample is the ugen graph of the BPFExample synthdef.
+func goFixtureBPFExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: BPF{In: Saw{Freq: C(200)}.Rate(AR).Mul(C(0.5)), Freq: FSinOsc{Freq: XLine{Start: C(0.7), End: C(300), Dur: C(20), Done: 0}.Rate(KR), Phase: C(0)}.Rate(KR).MulAdd(C(3600), C(4000)), RQ: C(0.3)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureBRFExample is the ugen graph of the BRFExample synthdef.
+func goFixtureBRFExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: BRF{In: Saw{Freq: C(200)}.Rate(AR).Mul(C(0.5)), Freq: FSinOsc{Freq: XLine{Start: C(0.7), End: C(300), Dur: C(20), Done: 0}.Rate(KR), Phase: C(0)}.Rate(KR).MulAdd(C(3800), C(4000)), RQ: C(0.3)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureBalance2Test is the ugen graph of the Balance2Test synthdef.
+func goFixtureBalance2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("Balance2", AR, 0, 2, LFSaw{Freq: C(44), Iphase: C(0)}.Rate(AR), Pulse{Freq: C(33), Width: C(0.5)}.Rate(AR), FSinOsc{Freq: C(0.5), Phase: C(0)}.Rate(KR), C(0.1))}.Rate(AR)
+}
+
+// goFixtureBallTest is the ugen graph of the BallTest synthdef.
+func goFixtureBallTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Ball{In: LFNoise{Interpolation: NoiseStep, Freq: MouseX{Min: C(1), Max: C(100), Warp: C(1), Lag: C(0.2)}.Rate(KR)}.Rate(AR), Gravity: MouseY{Min: C(0.1), Max: C(10), Warp: C(1), Lag: C(0.2)}.Rate(KR), Damp: C(0.01), Friction: C(0.01)}.Rate(AR).MulAdd(C(140), C(500)), Phase: C(0)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureBeats is the ugen graph of the Beats synthdef.
+func goFixtureBeats(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: SinOsc{Freq: C(0.2), Phase: C(0)}.Rate(KR).Add(C(440)), Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureBlipExample is the ugen graph of the BlipExample synthdef.
+func goFixtureBlipExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Blip{Freq: XLine{Start: C(20000), End: C(200), Dur: C(6), Done: 0}.Rate(KR), Harm: C(100)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureBrownNoiseTest is the ugen graph of the BrownNoiseTest synthdef.
+func goFixtureBrownNoiseTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: BrownNoise{}.Rate(AR).MulAdd(C(100), C(200)), Phase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureCOscTest is the ugen graph of the COscTest synthdef.
+func goFixtureCOscTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: COsc{BufNum: C(0), Freq: C(200), Beats: C(0.7)}.Rate(AR).Mul(C(0.25))}.Rate(AR)
+}
+
+// goFixtureCascade is the ugen graph of the Cascade synthdef.
+func goFixtureCascade(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Multi(SinOsc{Freq: SinOsc{Freq: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR), SinOsc{Freq: SinOsc{Freq: SinOsc{Freq: C(441), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureCascadeExample is the ugen graph of the CascadeExample synthdef.
+func goFixtureCascadeExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Multi(SinOsc{Freq: SinOsc{Freq: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR), SinOsc{Freq: SinOsc{Freq: SinOsc{Freq: C(441), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureClipNoiseTest is the ugen graph of the ClipNoiseTest synthdef.
+func goFixtureClipNoiseTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: ClipNoise{}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureCoinGateTest is the ugen graph of the CoinGateTest synthdef.
+func goFixtureCoinGateTest(p Params) Ugen {
+	out := p.Add("out", 0)
+	prob := p.Add("prob", 0.5)
+	return Out{Bus: out, Channels: SinOsc{Freq: TRand{Lo: C(300), Hi: C(400), Trig: CoinGate{Prob: prob, In: Impulse{Freq: C(10), Phase: C(0)}.Rate(KR)}.Rate(KR)}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureCombCTest is the ugen graph of the CombCTest synthdef.
+func goFixtureCombCTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Comb{Interpolation: InterpolationCubic, In: WhiteNoise{}.Rate(AR).Mul(C(0.01)), MaxDelayTime: C(0.01), DelayTime: XLine{Start: C(0.0001), End: C(0.01), Dur: C(20), Done: 0}.Rate(KR), DecayTime: C(0.2)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureCombLTest is the ugen graph of the CombLTest synthdef.
+func goFixtureCombLTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Comb{Interpolation: InterpolationLinear, In: WhiteNoise{}.Rate(AR).Mul(C(0.01)), MaxDelayTime: C(0.01), DelayTime: XLine{Start: C(0.0001), End: C(0.01), Dur: C(20), Done: 0}.Rate(KR), DecayTime: C(0.2)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureCombNTest is the ugen graph of the CombNTest synthdef.
+func goFixtureCombNTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Comb{Interpolation: InterpolationNone, In: WhiteNoise{}.Rate(AR).Mul(C(0.01)), MaxDelayTime: C(0.01), DelayTime: XLine{Start: C(0.0001), End: C(0.01), Dur: C(20), Done: 0}.Rate(KR), DecayTime: C(0.2)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureCrackleTest is the ugen graph of the CrackleTest synthdef.
+func goFixtureCrackleTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Crackle{Chaos: Line{Start: C(1), End: C(2), Dur: C(3), Done: 0}.Rate(KR)}.Rate(AR).MulAdd(C(0.5), C(0.5))}.Rate(AR)
+}
+
+// goFixtureDCTest is the ugen graph of the DCTest synthdef.
+func goFixtureDCTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: DC{In: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureDecay2Test is the ugen graph of the Decay2Test synthdef.
+func goFixtureDecay2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Decay2{In: Impulse{Freq: XLine{Start: C(1), End: C(50), Dur: C(20), Done: 0}.Rate(KR), Phase: C(0.25)}.Rate(AR), Attack: C(0.01), Decay: C(0.2)}.Rate(AR).Mul(FSinOsc{Freq: C(600), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureDelayCTest is the ugen graph of the DelayCTest synthdef.
+func goFixtureDelayCTest(p Params) Ugen {
+	mul := Decay{In: Dust{Density: C(1)}.Rate(AR).Mul(C(0.5)), Decay: C(0.3)}.Rate(AR).Mul(WhiteNoise{}.Rate(AR))
+	return Out{Bus: C(0), Channels: Delay{Interpolation: InterpolationCubic, In: mul, MaxDelayTime: C(0.2), DelayTime: C(0.2)}.Rate(AR).Add(mul)}.Rate(AR)
+}
+
+// goFixtureDelayLTest is the ugen graph of the DelayLTest synthdef.
+func goFixtureDelayLTest(p Params) Ugen {
+	mul := Decay{In: Dust{Density: C(1)}.Rate(AR).Mul(C(0.5)), Decay: C(0.3)}.Rate(AR).Mul(WhiteNoise{}.Rate(AR))
+	return Out{Bus: C(0), Channels: Delay{Interpolation: InterpolationLinear, In: mul, MaxDelayTime: C(0.2), DelayTime: C(0.2)}.Rate(AR).Add(mul)}.Rate(AR)
+}
+
+// goFixtureDelayNTest is the ugen graph of the DelayNTest synthdef.
+func goFixtureDelayNTest(p Params) Ugen {
+	mul := Decay{In: Dust{Density: C(1)}.Rate(AR).Mul(C(0.5)), Decay: C(0.3)}.Rate(AR).Mul(WhiteNoise{}.Rate(AR))
+	return Out{Bus: C(0), Channels: Delay{Interpolation: InterpolationNone, In: mul, MaxDelayTime: C(0.2), DelayTime: C(0.2)}.Rate(AR).Add(mul)}.Rate(AR)
+}
+
+// goFixtureDust2Test is the ugen graph of the Dust2Test synthdef.
+func goFixtureDust2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Dust2{Density: XLine{Start: C(20000), End: C(2), Dur: C(10), Done: 0}.Rate(KR)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixtureDustTest is the ugen graph of the DustTest synthdef.
+func goFixtureDustTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Dust{Density: XLine{Start: C(20000), End: C(2), Dur: C(10), Done: 0}.Rate(KR)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixtureEnvgen1 is the ugen graph of the Envgen1 synthdef.
+func goFixtureEnvgen1(p Params) Ugen {
+	return Out{Bus: C(0), Channels: PinkNoise{}.Rate(AR).Mul(EnvGen{Env: Env{Levels: []Input{C(0), C(1), C(0)}, Times: []Input{C(0.01), C(1)}, Curve: C(-4)}, Gate: C(1), LevelScale: C(1), LevelBias: C(0), TimeScale: C(1), Done: 2}.Rate(KR))}.Rate(AR)
+}
+
+// goFixtureEnvgenTest is the ugen graph of the EnvgenTest synthdef.
+func goFixtureEnvgenTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: PinkNoise{}.Rate(AR).Mul(EnvGen{Env: Env{Levels: []Input{C(0), C(1), C(0)}, Times: []Input{C(0.01), C(1)}, Curve: C(-4)}, Gate: C(1), LevelScale: C(1), LevelBias: C(0), TimeScale: C(1), Done: 2}.Rate(KR))}.Rate(AR)
+}
+
+// goFixtureFFTTest is the ugen graph of the FFTTest synthdef.
+func goFixtureFFTTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: IFFT{Buffer: PVBrickWall{Buffer: FFT{Buffer: NewInput("LocalBuf", IR, 0, 1, C(1), C(2048), NewInput("MaxLocalBufs", IR, 0, 1, C(1))), In: WhiteNoise{}.Rate(AR).Mul(C(0.2)), Hop: C(0.5), WinType: C(0), Active: C(1), WinSize: C(0)}.Rate(KR), Wipe: SinOsc{Freq: C(0.1), Phase: C(0)}.Rate(KR)}.Rate(KR), WinType: C(0), WinSize: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureFSinOscExample is the ugen graph of the FSinOscExample synthdef.
+func goFixtureFSinOscExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: FSinOsc{Freq: FSinOsc{Freq: XLine{Start: C(4), End: C(401), Dur: C(8), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).MulAdd(C(200), C(800)), Phase: C(0)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureFittonBubbles is the ugen graph of the FittonBubbles synthdef.
+func goFixtureFittonBubbles(p Params) Ugen {
+	lfSaw := LFSaw{Freq: C(0.4), Iphase: C(0)}.Rate(AR)
+	return Out{Bus: C(0), Channels: Multi(Comb{Interpolation: InterpolationCubic, In: SinOsc{Freq: lfSaw.MulAdd(C(24), LFSaw{Freq: C(8), Iphase: C(0)}.Rate(AR).MulAdd(C(3), C(80))).Midicps(), Phase: C(0)}.Rate(AR).Mul(C(0.04)), MaxDelayTime: C(0.2), DelayTime: C(0.2), DecayTime: C(4)}.Rate(AR), Comb{Interpolation: InterpolationCubic, In: SinOsc{Freq: lfSaw.MulAdd(C(24), LFSaw{Freq: C(7.23), Iphase: C(0)}.Rate(AR).MulAdd(C(3), C(80))).Midicps(), Phase: C(0)}.Rate(AR).Mul(C(0.04)), MaxDelayTime: C(0.2), DelayTime: C(0.2), DecayTime: C(4)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureFormantTest is the ugen graph of the FormantTest synthdef.
+func goFixtureFormantTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Formant{FundFreq: XLine{Start: C(400), End: C(1000), Dur: C(8), Done: 0}.Rate(KR), FormantFreq: C(2000), BWFreq: C(800)}.Rate(AR).Mul(C(0.125))}.Rate(AR)
+}
+
+// goFixtureFormletTest is the ugen graph of the FormletTest synthdef.
+func goFixtureFormletTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Formlet{In: Blip{Freq: SinOsc{Freq: C(5), Phase: C(0)}.Rate(KR).MulAdd(C(20), C(300)), Harm: C(1000)}.Rate(AR).Mul(C(0.1)), Freq: XLine{Start: C(1500), End: C(700), Dur: C(8), Done: 0}.Rate(KR), AttackTime: C(0.005), DecayTime: C(0.4)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureFreeVerbExample is the ugen graph of the FreeVerbExample synthdef.
+func goFixtureFreeVerbExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: FreeVerb{In: SinOsc{Freq: C(220), Phase: C(0)}.Rate(AR), Mix: C(0.33), Room: C(0.5), Damp: C(0.5)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureFreeVerbTest is the ugen graph of the FreeVerbTest synthdef.
+func goFixtureFreeVerbTest(p Params) Ugen {
+	mix := p.Add("mix", 0.25)
+	room := p.Add("room", 0.15)
+	damp := p.Add("damp", 0.5)
+	return Out{Bus: C(0), Channels: FreeVerb{In: Decay{In: Impulse{Freq: C(1), Phase: C(0)}.Rate(AR), Decay: C(0.25)}.Rate(AR).Mul(LFCub{Freq: C(1200), Iphase: C(0)}.Rate(AR).Mul(C(0.1))), Mix: mix, Room: room, Damp: damp}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureGVerbExample is the ugen graph of the GVerbExample synthdef.
+func goFixtureGVerbExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("GVerb", AR, 0, 2, SinOsc{Freq: C(220), Phase: C(0)}.Rate(AR), C(10), C(3), C(0.5), C(0.5), C(15), C(1), C(0.7), C(0.5), C(300))}.Rate(AR)
+}
+
+// goFixtureGateTest is the ugen graph of the GateTest synthdef.
+func goFixtureGateTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Gate{In: WhiteNoise{}.Rate(KR), Trig: LFPulse{Freq: C(1.333), IPhase: C(0.5), Width: C(0.5)}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureGendy1Test is the ugen graph of the Gendy1Test synthdef.
+func goFixtureGendy1Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("Pan2", AR, 0, 2, Gendy1{AmpDist: C(1), DurDist: C(1), ADParam: C(1), DDParam: C(1), MinFreq: C(440), MaxFreq: C(660), AmpScale: C(0.5), DurScale: C(0.5), InitCPs: C(12), KNum: C(12)}.Rate(AR), C(0), C(1))}.Rate(AR)
+}
+
+// goFixtureGendy2Test is the ugen graph of the Gendy2Test synthdef.
+func goFixtureGendy2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("Pan2", AR, 0, 2, Gendy2{Gendy1: Gendy1{AmpDist: C(1), DurDist: C(1), ADParam: C(1), DDParam: C(1), MinFreq: C(440), MaxFreq: C(660), AmpScale: C(0.5), DurScale: C(0.5), InitCPs: C(12), KNum: C(12)}, A: C(1.17), C: C(0.31)}.Rate(AR), C(0), C(1))}.Rate(AR)
+}
+
+// goFixtureGendy3Test is the ugen graph of the Gendy3Test synthdef.
+func goFixtureGendy3Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("Pan2", AR, 0, 2, Gendy3{AmpDist: C(1), DurDist: C(1), ADParam: C(1), DDParam: C(1), Freq: C(440), AmpScale: C(0.5), DurScale: C(0.5), InitCPs: C(12), KNum: C(12)}.Rate(AR), C(0), C(1))}.Rate(AR)
+}
+
+// goFixtureGrainBufTest is the ugen graph of the GrainBufTest synthdef.
+func goFixtureGrainBufTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: GrainBuf{Trigger: C(0), Dur: C(1), BufNum: C(0), Speed: C(1), Pos: C(0), Interp: C(2), Pan: C(0), EnvBuf: C(-1), MaxGrains: C(512)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureGrainFMExample is the ugen graph of the GrainFMExample synthdef.
+func goFixtureGrainFMExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: GrainFM{Trigger: C(0), Dur: C(1), CarFreq: C(440), ModFreq: C(200), ModIndex: C(1), Pan: C(0), EnvBuf: C(-1), MaxGrains: C(512)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureHPFExample is the ugen graph of the HPFExample synthdef.
+func goFixtureHPFExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: HPF{In: SinOsc{Freq: C(220), Phase: C(0)}.Rate(AR), Freq: C(440)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureHasherTest is the ugen graph of the HasherTest synthdef.
+func goFixtureHasherTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Hasher{In: MouseX{Min: C(0), Max: C(10), Warp: C(0), Lag: C(0.2)}.Rate(KR)}.Rate(KR).MulAdd(C(300), C(500)), Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureImpulseExample is the ugen graph of the ImpulseExample synthdef.
+func goFixtureImpulseExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Impulse{Freq: XLine{Start: C(800), End: C(100), Dur: C(5), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixtureIntegratorExample is the ugen graph of the IntegratorExample synthdef.
+func goFixtureIntegratorExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Integrator{In: LFPulse{Freq: C(375), IPhase: C(0.2), Width: C(0.1)}.Rate(AR), Coef: MouseX{Min: C(0.01), Max: C(0.999), Warp: C(1), Lag: C(0.2)}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureKlankTest1 is the ugen graph of the KlankTest1 synthdef.
+func goFixtureKlankTest1(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Klank{In: PinkNoise{}.Rate(AR).Mul(C(0.007)), Spec: ArraySpec{[]Input{C(800), C(1071), C(1353), C(1723)}, []Input{C(1), C(1), C(1), C(1)}, []Input{C(1), C(1), C(1), C(1)}}, FreqScale: C(1), FreqOffset: C(0), DecayScale: C(1)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureLFClipNoiseTest is the ugen graph of the LFClipNoiseTest synthdef.
+func goFixtureLFClipNoiseTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: LFClipNoise{Freq: C(4)}.Rate(AR).MulAdd(C(200), C(600)), Phase: C(0)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureLFCubTest is the ugen graph of the LFCubTest synthdef.
+func goFixtureLFCubTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFCub{Freq: LFCub{Freq: LFCub{Freq: C(0.2), Iphase: C(0)}.Rate(KR).MulAdd(C(8), C(10)), Iphase: C(0)}.Rate(KR).MulAdd(C(400), C(800)), Iphase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureLFDClipNoiseTest is the ugen graph of the LFDClipNoiseTest synthdef.
+func goFixtureLFDClipNoiseTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: LFDClipNoise{Freq: C(4)}.Rate(AR).MulAdd(C(200), C(600)), Phase: C(0)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureLFGaussTest is the ugen graph of the LFGaussTest synthdef.
+func goFixtureLFGaussTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFGauss{Duration: C(0.01), Width: NewInput(BinOpUgenName, KR, BinOpMul, 1, SampleDur{}.Rate(IR), MouseX{Min: C(10), Max: C(3000), Warp: C(1), Lag: C(0.2)}.Rate(KR)), IPhase: C(0), Loop: C(1), Done: 0}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureLFNoise1Example is the ugen graph of the LFNoise1Example synthdef.
+func goFixtureLFNoise1Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: XLine{Start: C(1000), End: C(10000), Dur: C(10), Done: 0}.Rate(KR)}.Rate(AR).Mul(C(0.25))}.Rate(AR)
+}
+
+// goFixtureLFParTest is the ugen graph of the LFParTest synthdef.
+func goFixtureLFParTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFPar{Freq: XLine{Start: C(100), End: C(8000), Dur: C(30), Done: 0}.Rate(KR), IPhase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureLFPulseTest is the ugen graph of the LFPulseTest synthdef.
+func goFixtureLFPulseTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFPulse{Freq: LFPulse{Freq: C(3), IPhase: C(0), Width: C(0.3)}.Rate(KR).MulAdd(C(200), C(200)), IPhase: C(0), Width: C(0.2)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureLFSawExample is the ugen graph of the LFSawExample synthdef.
+func goFixtureLFSawExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFSaw{Freq: LFSaw{Freq: C(4), Iphase: C(0)}.Rate(KR).MulAdd(C(200), C(400)), Iphase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureLFTriExample is the ugen graph of the LFTriExample synthdef.
+func goFixtureLFTriExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFTri{Freq: LFTri{Freq: C(4), Iphase: C(0)}.Rate(KR).MulAdd(C(200), C(400)), Iphase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureLPFExample is the ugen graph of the LPFExample synthdef.
+func goFixtureLPFExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LPF{In: SinOsc{Freq: C(220), Phase: C(0)}.Rate(AR), Freq: C(440)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureLagTest is the ugen graph of the LagTest synthdef.
+func goFixtureLagTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Lag{In: LFPulse{Freq: C(4), IPhase: C(0), Width: C(0.5)}.Rate(KR).MulAdd(C(50), C(400)), LagTime: Line{Start: C(0), End: C(1), Dur: C(15), Done: 0}.Rate(KR)}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.3))}.Rate(AR)
+}
+
+// goFixtureLatchTest is the ugen graph of the LatchTest synthdef.
+func goFixtureLatchTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Blip{Freq: Latch{In: WhiteNoise{}.Rate(AR), Trig: Impulse{Freq: C(9), Phase: C(0)}.Rate(AR)}.Rate(AR).MulAdd(C(400), C(500)), Harm: C(4)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureLeakDCTest is the ugen graph of the LeakDCTest synthdef.
+func goFixtureLeakDCTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LeakDC{In: LFPulse{Freq: C(800), IPhase: C(0.5), Width: C(0.5)}.Rate(AR).Mul(C(0.5)), Coeff: C(0.995)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureLinPan2Test is the ugen graph of the LinPan2Test synthdef.
+func goFixtureLinPan2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("LinPan2", AR, 0, 2, FSinOsc{Freq: C(800), Phase: C(0)}.Rate(AR).Mul(C(0.1)), FSinOsc{Freq: C(3), Phase: C(0)}.Rate(KR), C(1))}.Rate(AR)
+}
+
+// goFixtureLinXFade2Test is the ugen graph of the LinXFade2Test synthdef.
+func goFixtureLinXFade2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("LinXFade2", AR, 0, 1, Saw{Freq: C(440)}.Rate(AR), SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), LFTri{Freq: C(0.1), Iphase: C(0)}.Rate(KR))}.Rate(AR)
+}
+
+// goFixtureLineTest is the ugen graph of the LineTest synthdef.
+func goFixtureLineTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Line{Start: C(200), End: C(17000), Dur: C(10), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureMedianTest is the ugen graph of the MedianTest synthdef.
+func goFixtureMedianTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LeakDC{In: Median{Length: C(31), In: WhiteNoise{}.Rate(AR).MulAdd(C(0.1), SinOsc{Freq: C(800), Phase: C(0)}.Rate(AR).Mul(C(0.1)))}.Rate(AR), Coeff: C(0.9)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureMixTest is the ugen graph of the MixTest synthdef.
+func goFixtureMixTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Sum4(AR, Pulse{Freq: C(436), Width: C(0.5)}.Rate(AR), LFSaw{Freq: C(40), Iphase: C(0.1)}.Rate(AR), FSinOsc{Freq: C(801), Phase: C(0.1)}.Rate(AR), PinkNoise{}.Rate(AR).Mul(C(0.1))).Add(Dust{Density: C(4)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureOnePoleTest is the ugen graph of the OnePoleTest synthdef.
+func goFixtureOnePoleTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: OnePole{In: WhiteNoise{}.Rate(AR).Mul(C(0.5)), Coeff: Line{Start: C(-0.99), End: C(0.99), Dur: C(10), Done: 0}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureOneZeroTest is the ugen graph of the OneZeroTest synthdef.
+func goFixtureOneZeroTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: OneZero{In: WhiteNoise{}.Rate(AR).Mul(C(0.5)), Coeff: Line{Start: C(-0.5), End: C(0.5), Dur: C(10), Done: 0}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureOscNTest is the ugen graph of the OscNTest synthdef.
+func goFixtureOscNTest(p Params) Ugen {
+	bufnum := p.Add("bufnum", 0)
+	return Out{Bus: C(0), Channels: OscN{BufNum: bufnum, Freq: XLine{Start: C(2000), End: C(200), Dur: C(1), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixtureOscTest is the ugen graph of the OscTest synthdef.
+func goFixtureOscTest(p Params) Ugen {
+	bufnum := p.Add("bufnum", 0)
+	return Out{Bus: C(0), Channels: Osc{BufNum: bufnum, Freq: XLine{Start: C(2000), End: C(200), Dur: C(1), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixturePMOscTest is the ugen graph of the PMOscTest synthdef.
+func goFixturePMOscTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Line{Start: C(600), End: C(900), Dur: C(5), Done: 0}.Rate(KR), Phase: SinOsc{Freq: C(600), Phase: C(0)}.Rate(AR).Mul(C(3))}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixturePSinGrainTest is the ugen graph of the PSinGrainTest synthdef.
+func goFixturePSinGrainTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: PSinGrain{Freq: C(880), Dur: C(0.1), Amp: C(0.7)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixturePan2Test is the ugen graph of the Pan2Test synthdef.
+func goFixturePan2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("Pan2", AR, 0, 2, PinkNoise{}.Rate(AR).Mul(C(0.4)), FSinOsc{Freq: C(2), Phase: C(0)}.Rate(KR), C(0.3))}.Rate(AR)
+}
+
+// goFixturePan4Test is the ugen graph of the Pan4Test synthdef.
+func goFixturePan4Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("Pan4", AR, 0, 4, PinkNoise{}.Rate(AR), FSinOsc{Freq: C(2), Phase: C(0)}.Rate(KR), FSinOsc{Freq: C(1.2), Phase: C(0)}.Rate(KR), C(0.3))}.Rate(AR)
+}
+
+// goFixturePanAzTest is the ugen graph of the PanAzTest synthdef.
+func goFixturePanAzTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("PanAz", AR, 0, 2, DC{In: C(1)}.Rate(AR), Line{Start: C(0), End: C(0.5), Dur: C(0.1), Done: 0}.Rate(AR), C(1), C(2), C(0.5))}.Rate(AR)
+}
+
+// goFixturePlayBufExample is the ugen graph of the PlayBufExample synthdef.
+func goFixturePlayBufExample(p Params) Ugen {
+	bufnum := p.Add("bufnum", 0)
+	return Out{Bus: C(0), Channels: PlayBuf{BufNum: bufnum, Speed: C(1), Trigger: C(1), Start: C(0), Loop: C(0), Done: 2}.Rate(AR)}.Rate(AR)
+}
+
+// goFixturePulseCountTest is the ugen graph of the PulseCountTest synthdef.
+func goFixturePulseCountTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: PulseCount{Trig: Impulse{Freq: C(10), Phase: C(0)}.Rate(AR), Reset: Impulse{Freq: C(0.4), Phase: C(0)}.Rate(AR)}.Rate(AR).Mul(C(200)), Phase: C(0)}.Rate(AR).Mul(C(0.05))}.Rate(AR)
+}
+
+// goFixturePulseDividerTest is the ugen graph of the PulseDividerTest synthdef.
+func goFixturePulseDividerTest(p Params) Ugen {
+	out := p.Add("out", 0)
+	impulse := Impulse{Freq: C(8), Phase: C(0)}.Rate(AR)
+	return Out{Bus: out, Channels: SinOsc{Freq: C(1200), Phase: C(0)}.Rate(AR).MulAdd(Decay2{In: impulse, Attack: C(0.005), Decay: C(0.1)}.Rate(AR), SinOsc{Freq: C(600), Phase: C(0)}.Rate(AR).Mul(Decay2{In: PulseDivider{Trig: impulse, Div: C(4), Start: C(0)}.Rate(AR), Attack: C(0.005), Decay: C(0.5)}.Rate(AR))).Mul(C(0.4))}.Rate(AR)
+}
+
+// goFixturePulseTest is the ugen graph of the PulseTest synthdef.
+func goFixturePulseTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Pulse{Freq: XLine{Start: C(40), End: C(4000), Dur: C(6), Done: 0}.Rate(KR), Width: C(0.1)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureRLPFTest is the ugen graph of the RLPFTest synthdef.
+func goFixtureRLPFTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: RLPF{In: Saw{Freq: C(200)}.Rate(AR).Mul(C(0.1)), Freq: FSinOsc{Freq: XLine{Start: C(0.7), End: C(300), Dur: C(20), Done: 0}.Rate(KR), Phase: C(0)}.Rate(KR).MulAdd(C(3600), C(4000)), RQ: C(0.2)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureResonzTest is the ugen graph of the ResonzTest synthdef.
+func goFixtureResonzTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Resonz{In: WhiteNoise{}.Rate(AR).Mul(C(0.5)), Freq: XLine{Start: C(1000), End: C(8000), Dur: C(10), Done: 0}.Rate(KR), BWR: C(0.05)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureRingzTest is the ugen graph of the RingzTest synthdef.
+func goFixtureRingzTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Ringz{In: Impulse{Freq: C(6), Phase: C(0)}.Rate(AR).Mul(C(0.3)), Freq: C(2000), DecayTime: XLine{Start: C(4), End: C(0.04), Dur: C(8), Done: 0}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureRunningSumTest is the ugen graph of the RunningSumTest synthdef.
+func goFixtureRunningSumTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: RunningSum{In: LFSaw{Freq: C(440), Iphase: C(0)}.Rate(AR), NumSamp: C(100)}.Rate(AR).Mul(C(0.01))}.Rate(AR)
+}
+
+// goFixtureSameSame is the ugen graph of the SameSame synthdef.
+func goFixtureSameSame(p Params) Ugen {
+	sinOsc := SinOsc{Freq: C(220), Phase: C(0)}.Rate(AR)
+	return Out{Bus: C(0), Channels: Multi(sinOsc, sinOsc)}.Rate(AR)
+}
+
+// goFixtureSawTone1 is the ugen graph of the SawTone1 synthdef.
+func goFixtureSawTone1(p Params) Ugen {
+	freq := p.Add("freq", 440)
+	cutoff := p.Add("cutoff", 1200)
+	q := p.Add("q", 0.5)
+	return Out{Bus: C(0), Channels: RLPF{In: Saw{Freq: freq}.Rate(AR), Freq: cutoff, RQ: q}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSelectTest is the ugen graph of the SelectTest synthdef.
+func goFixtureSelectTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Select{Which: LFSaw{Freq: C(1), Iphase: C(0)}.Rate(KR).MulAdd(C(1.5), C(1.5)), Inputs: []Input{SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), Saw{Freq: C(440)}.Rate(AR), Pulse{Freq: C(440), Width: C(0.5)}.Rate(AR)}}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureShaperTest is the ugen graph of the ShaperTest synthdef.
+func goFixtureShaperTest(p Params) Ugen {
+	bufnum := p.Add("bufnum", 0)
+	return Out{Bus: C(0), Channels: Shaper{BufNum: bufnum, In: SinOsc{Freq: C(440), Phase: C(0.5)}.Rate(AR).Mul(Line{Start: C(0), End: C(0.9), Dur: C(6), Done: 0}.Rate(KR))}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSilentTest is the ugen graph of the SilentTest synthdef.
+func goFixtureSilentTest(p Params) Ugen {
+	dc := DC{In: C(0)}.Rate(AR)
+	return Out{Bus: C(0), Channels: Multi(dc, dc)}.Rate(AR)
+}
+
+// goFixtureSimpleMulti is the ugen graph of the SimpleMulti synthdef.
+func goFixtureSimpleMulti(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Multi(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), SinOsc{Freq: C(441), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureSinOscFBTest is the ugen graph of the SinOscFBTest synthdef.
+func goFixtureSinOscFBTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOscFB{Freq: SinOscFB{Freq: MouseY{Min: C(1), Max: C(1000), Warp: C(1), Lag: C(0.2)}.Rate(KR), Feedback: C(0)}.Rate(AR).MulAdd(C(100), C(200)), Feedback: MouseX{Min: C(1.5707964), Max: C(3.1415927), Warp: C(0), Lag: C(0.2)}.Rate(KR)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureSineTone is the ugen graph of the SineTone synthdef.
+func goFixtureSineTone(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSineTone2 is the ugen graph of the SineTone2 synthdef.
+func goFixtureSineTone2(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: C(440), Phase: SinOsc{Freq: C(0.1), Phase: C(0)}.Rate(AR)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixtureSineTone3 is the ugen graph of the SineTone3 synthdef.
+func goFixtureSineTone3(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: C(440), Phase: SinOsc{Freq: C(0.1), Phase: C(0)}.Rate(AR)}.Rate(AR).Add(C(0.5))}.Rate(AR)
+}
+
+// goFixtureSineTone4 is the ugen graph of the SineTone4 synthdef.
+func goFixtureSineTone4(p Params) Ugen {
+	freq := p.Add("freq", 440)
+	return Out{Bus: C(0), Channels: SinOsc{Freq: freq, Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSlewTest is the ugen graph of the SlewTest synthdef.
+func goFixtureSlewTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Slew{In: Saw{Freq: C(800)}.Rate(AR).Mul(C(0.2)), Up: C(400), Dn: C(400)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSlopeTest is the ugen graph of the SlopeTest synthdef.
+func goFixtureSlopeTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Slope{In: LFNoise{Interpolation: NoiseQuadratic, Freq: C(10)}.Rate(AR)}.Rate(AR), Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSoundInTest0 is the ugen graph of the SoundInTest0 synthdef.
+func goFixtureSoundInTest0(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("In", AR, 0, 1, NumOutputBuses{}.Rate(IR))}.Rate(AR)
+}
+
+// goFixtureSoundInTest00 is the ugen graph of the SoundInTest00 synthdef.
+func goFixtureSoundInTest00(p Params) Ugen {
+	numOutputBuses := NumOutputBuses{}.Rate(IR)
+	return Out{Bus: C(0), Channels: Multi(NewInput("In", AR, 0, 1, numOutputBuses), NewInput("In", AR, 0, 1, numOutputBuses))}.Rate(AR)
+}
+
+// goFixtureSoundInTest01 is the ugen graph of the SoundInTest01 synthdef.
+func goFixtureSoundInTest01(p Params) Ugen {
+	return Out{Bus: C(0), Channels: In{NumChannels: 2, Bus: NumOutputBuses{}.Rate(IR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSoundInTest02 is the ugen graph of the SoundInTest02 synthdef.
+func goFixtureSoundInTest02(p Params) Ugen {
+	numOutputBuses := NumOutputBuses{}.Rate(IR)
+	return Out{Bus: C(0), Channels: Multi(NewInput("In", AR, 0, 1, numOutputBuses), NewInput("In", AR, 0, 1, numOutputBuses.Add(C(2))))}.Rate(AR)
+}
+
+// goFixtureSoundInTest12 is the ugen graph of the SoundInTest12 synthdef.
+func goFixtureSoundInTest12(p Params) Ugen {
+	return Out{Bus: C(0), Channels: In{NumChannels: 2, Bus: NumOutputBuses{}.Rate(IR).Add(C(1))}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureSoundInTest20 is the ugen graph of the SoundInTest20 synthdef.
+func goFixtureSoundInTest20(p Params) Ugen {
+	numOutputBuses := NumOutputBuses{}.Rate(IR)
+	return Out{Bus: C(0), Channels: Multi(NewInput("In", AR, 0, 1, numOutputBuses.Add(C(2))), NewInput("In", AR, 0, 1, numOutputBuses))}.Rate(AR)
+}
+
+// goFixtureSpringTest is the ugen graph of the SpringTest synthdef.
+func goFixtureSpringTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Spring{In: LFNoise{Interpolation: NoiseStep, Freq: MouseX{Min: C(1), Max: C(100), Warp: C(1), Lag: C(0.2)}.Rate(KR)}.Rate(AR), Spring: MouseY{Min: C(0.1), Max: C(10), Warp: C(1), Lag: C(0.2)}.Rate(KR), Damp: C(0.01)}.Rate(AR).MulAdd(C(140), C(500)), Phase: C(0)}.Rate(AR).Mul(C(0.2))}.Rate(AR)
+}
+
+// goFixtureSum3Test is the ugen graph of the Sum3Test synthdef.
+func goFixtureSum3Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Sum3(AR, LFSaw{Freq: C(40), Iphase: C(0.1)}.Rate(AR), FSinOsc{Freq: C(801), Phase: C(0.1)}.Rate(AR), PinkNoise{}.Rate(AR).Mul(C(0.1)))}.Rate(AR)
+}
+
+// goFixtureSweepTest is the ugen graph of the SweepTest synthdef.
+func goFixtureSweepTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFPulse{Freq: C(440), IPhase: C(0), Width: C(0.5)}.Rate(AR).Mul(Sweep{Trig: C(0), RaiseRate: C(1)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureSyncSawTest is the ugen graph of the SyncSawTest synthdef.
+func goFixtureSyncSawTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SyncSaw{SyncFreq: C(800), SawFreq: Line{Start: C(800), End: C(1600), Dur: C(0.01), Done: 0}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureTDelayTest is the ugen graph of the TDelayTest synthdef.
+func goFixtureTDelayTest(p Params) Ugen {
+	impulse := Impulse{Freq: C(2), Phase: C(0)}.Rate(AR)
+	return Out{Bus: C(0), Channels: Multi(impulse.Mul(C(0.1)), ToggleFF{Trig: TDelay{In: impulse, Dur: C(0.5)}.Rate(AR)}.Rate(AR).Mul(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR).Mul(C(0.1))))}.Rate(AR)
+}
+
+// goFixtureTGrainsExample is the ugen graph of the TGrainsExample synthdef.
+func goFixtureTGrainsExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: TGrains{NumChannels: 2, Trigger: Impulse{Freq: C(4), Phase: C(0)}.Rate(AR), BufNum: C(0), GRate: C(1), CenterPos: C(0), Dur: C(0.1), Pan: C(0), Amp: C(0.1), Interp: C(4)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureTestEnvADSR is the ugen graph of the TestEnvADSR synthdef.
+func goFixtureTestEnvADSR(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR).Mul(EnvGen{Env: Env{Levels: []Input{C(0), C(1), C(0.5), C(0)}, Times: []Input{C(0.01), C(0.3), C(1)}, Curve: C(-4), ReleaseNode: C(2)}, Gate: C(1), LevelScale: C(1), LevelBias: C(0), TimeScale: C(1), Done: 2}.Rate(KR))}.Rate(AR)
+}
+
+// goFixtureTrig1Test is the ugen graph of the Trig1Test synthdef.
+func goFixtureTrig1Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Trig1{In: Dust{Density: C(1)}.Rate(AR), Dur: C(0.2)}.Rate(AR).Mul(FSinOsc{Freq: C(800), Phase: C(0.5)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureTrigTest is the ugen graph of the TrigTest synthdef.
+func goFixtureTrigTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Trig{In: Dust{Density: C(1)}.Rate(AR), Dur: C(0.2)}.Rate(AR).Mul(FSinOsc{Freq: C(800), Phase: C(0.5)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureUseParam is the ugen graph of the UseParam synthdef.
+func goFixtureUseParam(p Params) Ugen {
+	freq := p.Add("freq", 200)
+	return Out{Bus: C(0), Channels: SinOsc{Freq: freq.Add(C(20)), Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureVOsc3Test is the ugen graph of the VOsc3Test synthdef.
+func goFixtureVOsc3Test(p Params) Ugen {
+	bufnum := p.Add("bufnum", 0)
+	return Out{Bus: C(0), Channels: VOsc3{BufNum: bufnum, Freq1: XLine{Start: C(2000), End: C(200), Dur: C(0.5), Done: 0}.Rate(KR), Freq2: XLine{Start: C(2000), End: C(200), Dur: C(1.5), Done: 0}.Rate(KR), Freq3: XLine{Start: C(2000), End: C(200), Dur: C(4.5), Done: 0}.Rate(KR)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureVOscTest is the ugen graph of the VOscTest synthdef.
+func goFixtureVOscTest(p Params) Ugen {
+	bufnum := p.Add("bufnum", 0)
+	return Out{Bus: C(0), Channels: VOsc{BufNum: bufnum, Freq: XLine{Start: C(2000), End: C(200), Dur: C(1), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.5))}.Rate(AR)
+}
+
+// goFixtureVarSawTest is the ugen graph of the VarSawTest synthdef.
+func goFixtureVarSawTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: VarSaw{Freq: LFPulse{Freq: C(3), IPhase: C(0), Width: C(0.3)}.Rate(KR).MulAdd(C(200), C(200)), IPhase: C(0), Width: C(0.2)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureVibratoTest is the ugen graph of the VibratoTest synthdef.
+func goFixtureVibratoTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: Vibrato{Freq: DC{In: C(400)}.Rate(AR), Speed: MouseX{Min: C(2), Max: C(100), Warp: C(0), Lag: C(0.2)}.Rate(KR), Depth: C(0.1), Delay: C(1), Onset: C(1), RateVariation: MouseY{Min: C(0), Max: C(1), Warp: C(0), Lag: C(0.2)}.Rate(KR), DepthVariation: C(0.1), IPhase: C(0)}.Rate(AR), Phase: C(0)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureWarp1Example is the ugen graph of the Warp1Example synthdef.
+func goFixtureWarp1Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Warp1{NumChannels: 2, BufNum: C(0), Pointer: C(0), FreqScale: C(1), WindowSize: C(0.2), EnvBufNum: C(-1), Overlaps: C(8), WindowRandRatio: C(0), Interp: C(1)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureXFade2Test is the ugen graph of the XFade2Test synthdef.
+func goFixtureXFade2Test(p Params) Ugen {
+	return Out{Bus: C(0), Channels: XFade2{A: Saw{Freq: C(440)}.Rate(AR), B: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), Pan: LFTri{Freq: C(0.1), Iphase: C(0)}.Rate(KR), Level: C(1)}.Rate(AR)}.Rate(AR)
+}
+
+// goFixtureXLineTest is the ugen graph of the XLineTest synthdef.
+func goFixtureXLineTest(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: XLine{Start: C(200), End: C(17000), Dur: C(10), Done: 0}.Rate(KR), Phase: C(0)}.Rate(AR).Mul(C(0.1))}.Rate(AR)
+}
+
+// goFixtureAbsExample is the ugen graph of the absExample synthdef.
+func goFixtureAbsExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Abs()}.Rate(AR)
+}
+
+// goFixtureAbsdifExample is the ugen graph of the absdifExample synthdef.
+func goFixtureAbsdifExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Absdif(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureAcosExample is the ugen graph of the acosExample synthdef.
+func goFixtureAcosExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Acos()}.Rate(AR)
+}
+
+// goFixtureAmclipExample is the ugen graph of the amclipExample synthdef.
+func goFixtureAmclipExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Amclip(C(0.5))}.Rate(AR)
+}
+
+// goFixtureAmpdbExample is the ugen graph of the ampdbExample synthdef.
+func goFixtureAmpdbExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).AmpDb()}.Rate(AR)
+}
+
+// goFixtureAsinExample is the ugen graph of the asinExample synthdef.
+func goFixtureAsinExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Asin()}.Rate(AR)
+}
+
+// goFixtureAtan2Example is the ugen graph of the atan2Example synthdef.
+func goFixtureAtan2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Atan2(C(0.5))}.Rate(AR)
+}
+
+// goFixtureAtanExample is the ugen graph of the atanExample synthdef.
+func goFixtureAtanExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Atan()}.Rate(AR)
+}
+
+// goFixtureBar is the ugen graph of the bar synthdef.
+func goFixtureBar(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR).Mul(Blip{Freq: C(440), Harm: C(200)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureBaz is the ugen graph of the baz synthdef.
+func goFixtureBaz(p Params) Ugen {
+	return Out{Bus: C(0), Channels: Blip{Freq: C(440), Harm: C(200)}.Rate(AR).Mul(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureBilinrandExample is the ugen graph of the bilinrandExample synthdef.
+func goFixtureBilinrandExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Bilinrand()}.Rate(AR)
+}
+
+// goFixtureCeilExample is the ugen graph of the ceilExample synthdef.
+func goFixtureCeilExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Ceil()}.Rate(AR)
+}
+
+// goFixtureClip2Example is the ugen graph of the clip2Example synthdef.
+func goFixtureClip2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Clip2(C(0.5))}.Rate(AR)
+}
+
+// goFixtureCoinExample is the ugen graph of the coinExample synthdef.
+func goFixtureCoinExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Coin()}.Rate(AR)
+}
+
+// goFixtureCosExample is the ugen graph of the cosExample synthdef.
+func goFixtureCosExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Cos()}.Rate(AR)
+}
+
+// goFixtureCoshExample is the ugen graph of the coshExample synthdef.
+func goFixtureCoshExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Cosh()}.Rate(AR)
+}
+
+// goFixtureCpsmidiExample is the ugen graph of the cpsmidiExample synthdef.
+func goFixtureCpsmidiExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Cpsmidi()}.Rate(AR)
+}
+
+// goFixtureCpsoctExample is the ugen graph of the cpsoctExample synthdef.
+func goFixtureCpsoctExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Cpsoct()}.Rate(AR)
+}
+
+// goFixtureCubedExample is the ugen graph of the cubedExample synthdef.
+func goFixtureCubedExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Cubed()}.Rate(AR)
+}
+
+// goFixtureDbampExample is the ugen graph of the dbampExample synthdef.
+func goFixtureDbampExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).DbAmp()}.Rate(AR)
+}
+
+// goFixtureDefWith2Params is the ugen graph of the defWith2Params synthdef.
+func goFixtureDefWith2Params(p Params) Ugen {
+	freq := p.Add("freq", 440)
+	gain := p.Add("gain", 0.5)
+	return Out{Bus: C(0), Channels: SinOsc{Freq: freq, Phase: C(0)}.Rate(AR).Mul(EnvGen{Env: Env{Levels: []Input{C(0), C(1), C(0)}, Times: []Input{C(0.01), C(1)}, Curve: C(-4)}, Gate: C(1), LevelScale: gain, LevelBias: C(0), TimeScale: C(1), Done: 2}.Rate(KR))}.Rate(AR)
+}
+
+// goFixtureDifsqrExample is the ugen graph of the difsqrExample synthdef.
+func goFixtureDifsqrExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Difsqr(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureDistortExample is the ugen graph of the distortExample synthdef.
+func goFixtureDistortExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Distort()}.Rate(AR)
+}
+
+// goFixtureDivExample is the ugen graph of the divExample synthdef.
+func goFixtureDivExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Div(C(2))}.Rate(AR)
+}
+
+// goFixtureExcessExample is the ugen graph of the excessExample synthdef.
+func goFixtureExcessExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Excess(C(2))}.Rate(AR)
+}
+
+// goFixtureExpExample is the ugen graph of the expExample synthdef.
+func goFixtureExpExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Exp()}.Rate(AR)
+}
+
+// goFixtureExponExample is the ugen graph of the exponExample synthdef.
+func goFixtureExponExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Pow(C(2))}.Rate(AR)
+}
+
+// goFixtureFloorExample is the ugen graph of the floorExample synthdef.
+func goFixtureFloorExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Floor()}.Rate(AR)
+}
+
+// goFixtureFold2Example is the ugen graph of the fold2Example synthdef.
+func goFixtureFold2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Fold2(C(2))}.Rate(AR)
+}
+
+// goFixtureFoo is the ugen graph of the foo synthdef.
+func goFixtureFoo(p Params) Ugen {
+	return Out{Bus: C(0), Channels: SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR).Mul(Blip{Freq: C(440), Harm: C(200)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureFracExample is the ugen graph of the fracExample synthdef.
+func goFixtureFracExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Frac()}.Rate(AR)
+}
+
+// goFixtureGcdExample is the ugen graph of the gcdExample synthdef.
+func goFixtureGcdExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).GCD(C(2))}.Rate(AR)
+}
+
+// goFixtureGtExample is the ugen graph of the gtExample synthdef.
+func goFixtureGtExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).GT(C(2))}.Rate(AR)
+}
+
+// goFixtureGteExample is the ugen graph of the gteExample synthdef.
+func goFixtureGteExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).GTE(C(2))}.Rate(AR)
+}
+
+// goFixtureHypotExample is the ugen graph of the hypotExample synthdef.
+func goFixtureHypotExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Hypot(C(2))}.Rate(AR)
+}
+
+// goFixtureHypotapxExample is the ugen graph of the hypotapxExample synthdef.
+func goFixtureHypotapxExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).HypotApx(C(2))}.Rate(AR)
+}
+
+// goFixtureLcmExample is the ugen graph of the lcmExample synthdef.
+func goFixtureLcmExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).LCM(C(2))}.Rate(AR)
+}
+
+// goFixtureLinrandExample is the ugen graph of the linrandExample synthdef.
+func goFixtureLinrandExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Linrand()}.Rate(AR)
+}
+
+// goFixtureLog10Example is the ugen graph of the log10Example synthdef.
+func goFixtureLog10Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Log10()}.Rate(AR)
+}
+
+// goFixtureLog2Example is the ugen graph of the log2Example synthdef.
+func goFixtureLog2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Log2()}.Rate(AR)
+}
+
+// goFixtureLogExample is the ugen graph of the logExample synthdef.
+func goFixtureLogExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Log()}.Rate(AR)
+}
+
+// goFixtureLtExample is the ugen graph of the ltExample synthdef.
+func goFixtureLtExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).LT(C(2))}.Rate(AR)
+}
+
+// goFixtureLteExample is the ugen graph of the lteExample synthdef.
+func goFixtureLteExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).LTE(C(2))}.Rate(AR)
+}
+
+// goFixtureMidiratioExample is the ugen graph of the midiratioExample synthdef.
+func goFixtureMidiratioExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Midiratio()}.Rate(AR)
+}
+
+// goFixtureMinExample is the ugen graph of the minExample synthdef.
+func goFixtureMinExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Min(C(0.5))}.Rate(AR)
+}
+
+// goFixtureModdifExample is the ugen graph of the moddifExample synthdef.
+func goFixtureModdifExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput("ModDif", AR, 0, 1, LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR), SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), C(1))}.Rate(AR)
+}
+
+// goFixtureModuloExample is the ugen graph of the moduloExample synthdef.
+func goFixtureModuloExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Modulo(C(0.5))}.Rate(AR)
+}
+
+// goFixtureNegExample is the ugen graph of the negExample synthdef.
+func goFixtureNegExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Neg()}.Rate(AR)
+}
+
+// goFixtureOctcpsExample is the ugen graph of the octcpsExample synthdef.
+func goFixtureOctcpsExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Octcps()}.Rate(AR)
+}
+
+// goFixturePowExample is the ugen graph of the powExample synthdef.
+func goFixturePowExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Pow(C(2))}.Rate(AR)
+}
+
+// goFixtureRand2Example is the ugen graph of the rand2Example synthdef.
+func goFixtureRand2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Rand2()}.Rate(AR)
+}
+
+// goFixtureRandExample is the ugen graph of the randExample synthdef.
+func goFixtureRandExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Rand()}.Rate(AR)
+}
+
+// goFixtureRatiomidiExample is the ugen graph of the ratiomidiExample synthdef.
+func goFixtureRatiomidiExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Ratiomidi()}.Rate(AR)
+}
+
+// goFixtureReciprocalExample is the ugen graph of the reciprocalExample synthdef.
+func goFixtureReciprocalExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Reciprocal()}.Rate(AR)
+}
+
+// goFixtureRing1Example is the ugen graph of the ring1Example synthdef.
+func goFixtureRing1Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Ring1(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureRing2Example is the ugen graph of the ring2Example synthdef.
+func goFixtureRing2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Ring2(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureRing3Example is the ugen graph of the ring3Example synthdef.
+func goFixtureRing3Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Ring3(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureRing4Example is the ugen graph of the ring4Example synthdef.
+func goFixtureRing4Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Ring4(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureRoundExample is the ugen graph of the roundExample synthdef.
+func goFixtureRoundExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Round(C(0.5))}.Rate(AR)
+}
+
+// goFixtureScalenegExample is the ugen graph of the scalenegExample synthdef.
+func goFixtureScalenegExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Scaleneg(C(0.5))}.Rate(AR)
+}
+
+// goFixtureSignExample is the ugen graph of the signExample synthdef.
+func goFixtureSignExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sign()}.Rate(AR)
+}
+
+// goFixtureSinExample is the ugen graph of the sinExample synthdef.
+func goFixtureSinExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sin()}.Rate(AR)
+}
+
+// goFixtureSinhExample is the ugen graph of the sinhExample synthdef.
+func goFixtureSinhExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sinh()}.Rate(AR)
+}
+
+// goFixtureSqrdifExample is the ugen graph of the sqrdifExample synthdef.
+func goFixtureSqrdifExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sqrdif(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureSqrsumExample is the ugen graph of the sqrsumExample synthdef.
+func goFixtureSqrsumExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sqrsum(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureSqrtExample is the ugen graph of the sqrtExample synthdef.
+func goFixtureSqrtExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sqrt()}.Rate(AR)
+}
+
+// goFixtureSquaredExample is the ugen graph of the squaredExample synthdef.
+func goFixtureSquaredExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Squared()}.Rate(AR)
+}
+
+// goFixtureSub is the ugen graph of the sub synthdef.
+func goFixtureSub(p Params) Ugen {
+	return Out{Bus: C(0), Channels: NewInput(BinOpUgenName, AR, 1, 1, SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR), Blip{Freq: C(440), Harm: C(200)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureSum3randExample is the ugen graph of the sum3randExample synthdef.
+func goFixtureSum3randExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sum3rand()}.Rate(AR)
+}
+
+// goFixtureSumsqrExample is the ugen graph of the sumsqrExample synthdef.
+func goFixtureSumsqrExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Sumsqr(SinOsc{Freq: C(440), Phase: C(0)}.Rate(AR))}.Rate(AR)
+}
+
+// goFixtureTanExample is the ugen graph of the tanExample synthdef.
+func goFixtureTanExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Tan()}.Rate(AR)
+}
+
+// goFixtureTanhExample is the ugen graph of the tanhExample synthdef.
+func goFixtureTanhExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Tanh()}.Rate(AR)
+}
+
+// goFixtureThreshExample is the ugen graph of the threshExample synthdef.
+func goFixtureThreshExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Thresh(C(0.5))}.Rate(AR)
+}
+
+// goFixtureTruncExample is the ugen graph of the truncExample synthdef.
+func goFixtureTruncExample(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Trunc(C(0.5))}.Rate(AR)
+}
+
+// goFixtureWrap2Example is the ugen graph of the wrap2Example synthdef.
+func goFixtureWrap2Example(p Params) Ugen {
+	return Out{Bus: C(0), Channels: LFNoise{Interpolation: NoiseLinear, Freq: C(1500)}.Rate(AR).Wrap2(C(0.5))}.Rate(AR)
+}
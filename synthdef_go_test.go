@@ -0,0 +1,316 @@
+package sc
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the decompiler tests")
+
+// goFixturesFile contains the Go code generated for the synthdefs in testdata.
+const goFixturesFile = "synthdef_go_fixtures_test.go"
+
+// goFixtures returns the synthdefs in testdata that can be written as Go,
+// and the errors for the ones that can't.
+func goFixtures(t *testing.T) ([]*Synthdef, map[string]error) {
+	var (
+		defs []*Synthdef
+		errs = map[string]error{}
+	)
+	for _, fixture := range readFixtures(t) {
+		def := fixture.def
+
+		if err := newGoWriter(def, false).writeFunc(ioutil.Discard, "f"); err != nil {
+			errs[def.Name] = err
+			continue
+		}
+		defs = append(defs, def)
+	}
+	return defs, errs
+}
+
+// writeGoFixtures writes the contents of goFixturesFile.
+func writeGoFixtures(t *testing.T, defs []*Synthdef) []byte {
+	var (
+		funcs   bytes.Buffer
+		entries bytes.Buffer
+		useMath bool
+	)
+	for _, def := range defs {
+		var (
+			g    = newGoWriter(def, false)
+			name = "goFixture" + goName(def.Name)
+		)
+		if err := g.writeFunc(&funcs, name); err != nil {
+			t.Fatalf("%s: %s", def.Name, err)
+		}
+		funcs.WriteString("\n")
+		fmt.Fprintf(&entries, "%q: %s,\n", def.Name, name)
+		useMath = useMath || g.math
+	}
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by go test -run TestWriteGoFixtures -update. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package sc\n\n")
+	if useMath {
+		fmt.Fprintf(&src, "import \"math\"\n\n")
+	}
+	fmt.Fprintf(&src, "// goFixtureFuncs are the ugen graphs of the synthdefs in testdata, by synthdef name.\n")
+	fmt.Fprintf(&src, "var goFixtureFuncs = map[string]UgenFunc{\n%s}\n\n", entries.String())
+	src.Write(funcs.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return formatted
+}
+
+// TestWriteGoFixtures checks that goFixturesFile is up to date.
+// Run the test with -update to rewrite it.
+func TestWriteGoFixtures(t *testing.T) {
+	defs, _ := goFixtures(t)
+	expected := writeGoFixtures(t, defs)
+
+	if *update {
+		if err := ioutil.WriteFile(goFixturesFile, expected, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	got, err := ioutil.ReadFile(goFixturesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("%s is out of date, run go test -run TestWriteGoFixtures -update", goFixturesFile)
+	}
+}
+
+// TestWriteGoRoundTrip checks that the generated code creates
+// the same synthdefs that sclang does.
+func TestWriteGoRoundTrip(t *testing.T) {
+	defs, errs := goFixtures(t)
+
+	if expected, got := len(defs), len(goFixtureFuncs); expected != got {
+		t.Fatalf("expected %d generated funcs, got %d", expected, got)
+	}
+	for _, def := range defs {
+		f, ok := goFixtureFuncs[def.Name]
+		if !ok {
+			t.Fatalf("%s: no generated func", def.Name)
+		}
+		if diff := NewSynthdef(def.Name, f).Diff(def); len(diff) > 0 {
+			t.Fatalf("%s: generated synthdef is different: %v", def.Name, diff)
+		}
+	}
+	for _, name := range []string{
+		"DetectSilence", // DetectSilence and Out are both roots.
+		"InTest",        // SinOsc reads one channel of a stereo In.
+	} {
+		if errs[name] == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWriteGo(t *testing.T) {
+	def := NewSynthdef("sine-tone", func(p Params) Ugen {
+		var (
+			freq = p.Add("freq", 440)
+			amp  = p.Add("amp", 0.1)
+			sine = SinOsc{Freq: freq}.Rate(AR)
+		)
+		p.Add("unused", -1)
+		return Out{Bus: C(0), Channels: Multi(sine.Mul(amp), sine.Mul(amp).Neg())}.Rate(AR)
+	})
+	var buf bytes.Buffer
+	if err := def.WriteGo(&buf, "synths"); err != nil {
+		t.Fatal(err)
+	}
+	src := buf.String()
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "sine_tone.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %s\n%s", err, src)
+	}
+	for _, expected := range []string{
+		"package synths",
+		`"github.com/scgolang/sc"`,
+		"func SineTone(p sc.Params) sc.Ugen {",
+		`freq := p.Add("freq", 440)`,
+		`amp := p.Add("amp", 0.1)`,
+		"\tp.Add(\"unused\", -1)\n",
+		"sinOsc := sc.SinOsc{Freq: freq, Phase: sc.C(0)}.Rate(sc.AR)",
+		"sc.Out{Bus: sc.C(0), Channels: sc.Multi(sinOsc.Mul(amp), sinOsc.Mul(amp).Neg())}.Rate(sc.AR)",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Fatalf("expected generated code to contain %q, got\n%s", expected, src)
+		}
+	}
+}
+
+func TestWriteGoErrors(t *testing.T) {
+	def := NewSynthdef("WriteGoErrors", func(p Params) Ugen {
+		return Out{Bus: C(0), Channels: SinOsc{}.Rate(AR)}.Rate(AR)
+	})
+	def.Ugens[1].Inputs[1] = UgenInput{UgenIndex: 3, OutputIndex: 0}
+
+	if err := def.WriteGo(ioutil.Discard, "sc"); err == nil {
+		t.Fatal("expected an error for an invalid ugen index")
+	}
+}
+
+// TestWriteGoDemandRate checks that ugens at rates that can't be
+// created in Go are an error instead of being written at audio rate.
+func TestWriteGoDemandRate(t *testing.T) {
+	def := NewSynthdef("WriteGoDemandRate", func(p Params) Ugen {
+		return Out{Bus: C(0), Channels: SinOsc{}.Rate(AR)}.Rate(AR)
+	})
+	def.Ugens[0].Rate = 3
+
+	err := def.WriteGo(ioutil.Discard, "sc")
+	if err == nil {
+		t.Fatal("expected an error for a demand rate ugen")
+	}
+	if expected, got := "ugen 0 (SinOsc) has rate 3", err.Error(); !strings.Contains(got, expected) {
+		t.Fatalf("expected error to contain %q, got %q", expected, got)
+	}
+}
+
+// TestWriteGoOps checks that the Input methods used by the generated code
+// create the operators they are supposed to.
+func TestWriteGoOps(t *testing.T) {
+	var (
+		x = SinOsc{}.Rate(AR)
+		y = C(2)
+	)
+	for _, testCase := range []struct {
+		Name string
+		Ops  []operator
+		Args []reflect.Value
+	}{
+		{Name: BinOpUgenName, Ops: binOps, Args: []reflect.Value{reflect.ValueOf(y)}},
+		{Name: UnaryOpUgenName, Ops: unaryOps},
+	} {
+		for specialIndex, op := range testCase.Ops {
+			method := op.method
+			if method == "" {
+				continue
+			}
+			m := reflect.ValueOf(x).MethodByName(method)
+			if !m.IsValid() {
+				t.Fatalf("Input does not have a %s method", method)
+			}
+			u, ok := m.Call(testCase.Args)[0].Interface().(*Ugen)
+			if !ok {
+				t.Fatalf("expected %s to return a *Ugen", method)
+			}
+			if expected, got := testCase.Name, u.Name; expected != got {
+				t.Fatalf("%s: expected %s, got %s", method, expected, got)
+			}
+			if expected, got := int16(specialIndex), u.SpecialIndex; expected != got {
+				t.Fatalf("%s: expected special index %d, got %d", method, expected, got)
+			}
+		}
+	}
+}
+
+// TestWriteGoUgens checks that the fields in goUgens exist
+// and set the ugen inputs in the right order.
+func TestWriteGoUgens(t *testing.T) {
+	for name, gu := range goUgens {
+		if gu.Custom {
+			continue // Checked by TestWriteGoRoundTrip.
+		}
+		typeName := gu.Struct
+		if typeName == "" {
+			typeName = name
+		}
+		typ, ok := goUgenTypes[typeName]
+		if !ok {
+			t.Fatalf("%s: add %s to goUgenTypes", name, typeName)
+		}
+		v := reflect.New(typ).Elem()
+		for i, field := range gu.Fields {
+			f := v.FieldByName(field)
+			if !f.IsValid() {
+				t.Fatalf("%s does not have a %s field", typeName, field)
+			}
+			if gu.Slice && i == len(gu.Fields)-1 {
+				f.Set(reflect.ValueOf([]Input{C(100 + i)}))
+				continue
+			}
+			f.Set(reflect.ValueOf(C(100 + i)))
+		}
+		if gu.Set != "" {
+			to, ok := goConstants[gu.To]
+			if !ok {
+				t.Fatalf("%s: add %s to goConstants", name, gu.To)
+			}
+			v.FieldByName(gu.Set).Set(reflect.ValueOf(to))
+		}
+		rate := reflect.ValueOf(int8(AR))
+		if name == "ControlRate" || name == "SampleDur" || name == "SampleRate" || name == "NumOutputBuses" {
+			rate = reflect.ValueOf(int8(IR))
+		}
+		out := v.MethodByName("Rate").Call([]reflect.Value{rate})[0].Interface()
+
+		var u *Ugen
+		switch x := out.(type) {
+		case *Ugen:
+			u = x
+		case Ugen:
+			u = &x
+		default:
+			t.Fatalf("%s: unexpected type %T", typeName, out)
+		}
+		if expected, got := name, u.Name; expected != got {
+			t.Fatalf("%s: expected ugen %s, got %s", typeName, expected, got)
+		}
+		for i := range gu.Fields {
+			if expected, got := C(100+i), u.inputs[i]; expected != got {
+				t.Fatalf("%s: expected input %d to be %v, got %v", name, i, expected, got)
+			}
+		}
+	}
+}
+
+// goConstants are the values of the constants in goUgens.
+var goConstants = map[string]interface{}{
+	"InterpolationCubic":  InterpolationCubic,
+	"InterpolationLinear": InterpolationLinear,
+	"InterpolationNone":   InterpolationNone,
+	"NoiseLinear":         NoiseLinear,
+	"NoiseQuadratic":      NoiseQuadratic,
+	"NoiseStep":           NoiseStep,
+}
+
+// goUgenTypes are the types of the ugen structs in goUgens.
+var goUgenTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []interface{}{
+		Allpass{}, BAllPass{}, BLowPass{}, BPF{}, BRF{}, Ball{}, Blip{}, BrownNoise{}, COsc{},
+		ClipNoise{}, CoinGate{}, Comb{}, ControlRate{}, Crackle{}, DC{}, Decay{}, Decay2{}, Delay{},
+		DetectSilence{}, DiskOut{}, Dust{}, Dust2{}, FFT{}, FSinOsc{}, Formant{}, Formlet{}, FreeVerb{},
+		Gate{}, Gendy1{}, Gendy3{}, GrainBuf{}, GrainFM{}, GrayNoise{}, HPF{}, Hasher{}, IFFT{},
+		Impulse{}, Integrator{}, LFClipNoise{}, LFCub{}, LFDClipNoise{}, LFGauss{}, LFNoise{}, LFPar{},
+		LFPulse{}, LFSaw{}, LFTri{}, LPF{}, Lag{}, Latch{}, LeakDC{}, Limiter{}, Line{}, Median{},
+		MouseX{}, MouseY{}, NumOutputBuses{}, OffsetOut{}, OnePole{}, OneZero{}, Osc{}, OscN{}, Out{},
+		PSinGrain{}, PVBrickWall{}, PinkNoise{}, PlayBuf{}, Pulse{}, PulseCount{}, PulseDivider{},
+		RLPF{}, Rand{}, Resonz{}, Ringz{}, RunningSum{}, SampleDur{}, SampleRate{}, Saw{}, Select{},
+		Shaper{}, SinOsc{}, SinOscFB{}, Slew{}, Slope{}, Spring{}, Sweep{}, SyncSaw{}, TDelay{},
+		TGrains{}, TRand{}, ToggleFF{}, Trig{}, Trig1{}, VOsc{}, VOsc3{}, VarSaw{}, Vibrato{}, Warp1{},
+		WhiteNoise{}, XFade2{}, XLine{},
+	} {
+		typ := reflect.TypeOf(v)
+		goUgenTypes[typ.Name()] = typ
+	}
+}
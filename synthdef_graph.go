@@ -0,0 +1,187 @@
+package sc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of ugen graph nodes.
+const (
+	ugenNode = iota
+	constantNode
+	paramNode
+)
+
+// ugenGraph is the ugen graph of a synthdef, ready to be drawn.
+// Constants and params are leaves of the graph.
+// Each constant input gets its own node, each param has a single node
+// that is shared by all the ugens that read it.
+type ugenGraph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+// graphNode is a node of a ugen graph.
+type graphNode struct {
+	id    string
+	kind  int
+	label []string // label has one element per line
+}
+
+// graphEdge connects the output of a node to the input of another node.
+type graphEdge struct {
+	from, to int    // from and to are node indices
+	label    string // label is the output index, or the index of a value of an array param
+}
+
+// graph returns the ugen graph of the synthdef.
+// Nodes come after the nodes they depend on.
+func (def *Synthdef) graph() (*ugenGraph, error) {
+	if err := def.validateGraph(); err != nil {
+		return nil, err
+	}
+	var (
+		g         = &ugenGraph{}
+		numValues = len(def.InitialParamValues)
+		params    = make([]int, numValues) // params are the node indices of the params, by value index
+		first     = make([]int, numValues) // first are the first value indices of the params, by value index
+		ugens     = make([]int, len(def.Ugens))
+	)
+	names := append([]ParamName{}, def.ParamNames...)
+	sort.SliceStable(names, func(i, j int) bool { return names[i].Index < names[j].Index })
+
+	for i, pn := range names {
+		end := numValues
+		if i+1 < len(names) {
+			end = int(names[i+1].Index)
+		}
+		label := []string{pn.Name, graphNumbers(def.InitialParamValues[pn.Index:end])}
+		g.nodes = append(g.nodes, graphNode{id: "p" + strconv.Itoa(int(pn.Index)), kind: paramNode, label: label})
+
+		for j := int(pn.Index); j < end; j++ {
+			params[j], first[j] = len(g.nodes)-1, int(pn.Index)
+		}
+	}
+	for i, u := range def.Ugens {
+		if _, ok := controlUgens[u.Name]; ok {
+			ugens[i] = -1
+			continue
+		}
+		var edges []graphEdge
+
+		for j, in := range u.Inputs {
+			if in.IsConstant() {
+				g.nodes = append(g.nodes, graphNode{
+					id:    fmt.Sprintf("u%d_%d", i, j),
+					kind:  constantNode,
+					label: []string{graphNumber(def.Constants[in.OutputIndex])},
+				})
+				edges = append(edges, graphEdge{from: len(g.nodes) - 1})
+				continue
+			}
+			from := def.Ugens[in.UgenIndex]
+			if ugens[in.UgenIndex] >= 0 {
+				edges = append(edges, graphEdge{from: ugens[in.UgenIndex], label: strconv.Itoa(int(in.OutputIndex))})
+				continue
+			}
+			k := int(from.SpecialIndex) + int(in.OutputIndex)
+			if k >= numValues || len(names) == 0 || int(names[0].Index) > k {
+				return nil, fmt.Errorf("ugen %d (%s) reads param value %d, which does not belong to a param", i, u.Name, k)
+			}
+			edge := graphEdge{from: params[k]}
+			if k != first[k] || (k+1 < numValues && first[k+1] == first[k]) {
+				edge.label = strconv.Itoa(k - first[k])
+			}
+			edges = append(edges, edge)
+		}
+		g.nodes = append(g.nodes, graphNode{id: "u" + strconv.Itoa(i), kind: ugenNode, label: ugenLabel(u)})
+		ugens[i] = len(g.nodes) - 1
+
+		for _, e := range edges {
+			e.to = ugens[i]
+			g.edges = append(g.edges, e)
+		}
+	}
+	return g, nil
+}
+
+// ugenLabel returns the label of a ugen node.
+// Operators are labelled with the operator instead of the special index.
+func ugenLabel(u *Ugen) []string {
+	label := []string{u.Name, rateName(u.Rate, "dr")}
+
+	switch op, ok := lookupOp(u); {
+	case ok:
+		label[0] = op.sclang
+	case u.SpecialIndex != 0:
+		label = append(label, "special index "+strconv.Itoa(int(u.SpecialIndex)))
+	}
+	return label
+}
+
+// graphNumber formats a number for a graph label.
+func graphNumber(v float32) string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+// graphNumbers formats the values of a param for a graph label.
+func graphNumbers(values []float32) string {
+	if len(values) == 1 {
+		return graphNumber(values[0])
+	}
+	texts := make([]string, len(values))
+	for i, v := range values {
+		texts[i] = graphNumber(v)
+	}
+	return "[" + strings.Join(texts, ", ") + "]"
+}
+
+// WriteDOT writes the synthdef's ugen graph in the Graphviz DOT language,
+// e.g. to make an svg with
+//
+//	dot -Tsvg -o SineTone.svg SineTone.dot
+//
+// Ugens are labelled with their name (or operator) and rate, constants and
+// params are leaves, and edges are labelled with the output index they read.
+func (def *Synthdef) WriteDOT(w io.Writer) error {
+	g, err := def.graph()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotString(def.Name))
+	fmt.Fprintf(&b, "\tnode [fontname=\"sans-serif\" fontsize=12];\n")
+
+	for _, n := range g.nodes {
+		var attrs string
+		switch n.kind {
+		case ugenNode:
+			attrs = "shape=box style=rounded"
+		case constantNode:
+			attrs = "shape=plaintext"
+		case paramNode:
+			attrs = "shape=ellipse"
+		}
+		fmt.Fprintf(&b, "\t%s [%s label=%s];\n", n.id, attrs, dotString(strings.Join(n.label, "\n")))
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "\t%s -> %s", g.nodes[e.from].id, g.nodes[e.to].id)
+		if e.label != "" {
+			fmt.Fprintf(&b, " [label=%s]", dotString(e.label))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// dotString returns a quoted DOT string.
+func dotString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
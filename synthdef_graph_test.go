@@ -0,0 +1,148 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	def := NewSynthdef("Graph \"Test\"", func(p Params) Ugen {
+		var (
+			freq = p.Add("freq", 440)
+			sine = SinOsc{Freq: freq}.Rate(AR)
+		)
+		return Out{Bus: C(0), Channels: sine.Mul(C(0.5)).Abs()}.Rate(AR)
+	})
+	var buf bytes.Buffer
+	if err := def.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph "Graph \"Test\"" {
+	node [fontname="sans-serif" fontsize=12];
+	p0 [shape=ellipse label="freq\n440"];
+	u1_1 [shape=plaintext label="0"];
+	u1 [shape=box style=rounded label="SinOsc\nar"];
+	u2_1 [shape=plaintext label="0.5"];
+	u2 [shape=box style=rounded label="*\nar"];
+	u3 [shape=box style=rounded label="abs\nar"];
+	u4_0 [shape=plaintext label="0"];
+	u4 [shape=box style=rounded label="Out\nar"];
+	p0 -> u1;
+	u1_1 -> u1;
+	u1 -> u2 [label="0"];
+	u2_1 -> u2;
+	u2 -> u3 [label="0"];
+	u4_0 -> u4;
+	u3 -> u4 [label="0"];
+}
+`
+	if got := buf.String(); expected != got {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestWriteDOTArrayParam(t *testing.T) {
+	def := &Synthdef{
+		Name:               "ArrayParam",
+		Constants:          []float32{0},
+		InitialParamValues: []float32{200, 300},
+		ParamNames:         []ParamName{{Name: "freqs", Index: 0}},
+		Ugens: []*Ugen{
+			{Name: "Control", Rate: KR, Outputs: []Output{KR, KR}},
+			{Name: "Saw", Rate: AR, SpecialIndex: 3, Inputs: []UgenInput{{0, 1}}, Outputs: []Output{AR}},
+			{Name: "Out", Rate: AR, Inputs: []UgenInput{{-1, 0}, {1, 0}}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := def.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`p0 [shape=ellipse label="freqs\n[200, 300]"];`,
+		`u1 [shape=box style=rounded label="Saw\nar\nspecial index 3"];`,
+		`p0 -> u1 [label="1"];`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+			t.Fatalf("expected output to contain %s, got\n%s", expected, buf.String())
+		}
+	}
+}
+
+// TestWriteSVG checks that every synthdef in testdata can be drawn,
+// and that the nodes don't overlap.
+func TestWriteSVG(t *testing.T) {
+	for _, fixture := range readFixtures(t) {
+		path, def := fixture.path, fixture.def
+
+		var buf1, buf2 bytes.Buffer
+		if err := def.WriteSVG(&buf1); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if err := def.WriteSVG(&buf2); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+			t.Fatalf("%s: expected the same svg every time", path)
+		}
+		elements := map[string]int{}
+		for dec := xml.NewDecoder(&buf1); ; {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %s", path, err)
+			}
+			if start, ok := tok.(xml.StartElement); ok {
+				elements[start.Name.Local]++
+			}
+		}
+		var numUgens int
+		for _, u := range def.Ugens {
+			if _, ok := controlUgens[u.Name]; !ok {
+				numUgens++
+			}
+		}
+		if expected, got := numUgens, elements["rect"]; expected != got {
+			t.Fatalf("%s: expected %d ugen boxes, got %d", path, expected, got)
+		}
+		if expected, got := len(def.ParamNames), elements["ellipse"]; expected != got {
+			t.Fatalf("%s: expected %d param ellipses, got %d", path, expected, got)
+		}
+		g, err := def.graph()
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := newSVGLayout(g)
+		for _, layer := range l.layers {
+			for i := 1; i < len(layer); i++ {
+				if left, right := l.boxes[layer[i-1]], l.boxes[layer[i]]; left.x+left.w > right.x {
+					t.Fatalf("%s: nodes %s and %s overlap", path, g.nodes[layer[i-1]].id, g.nodes[layer[i]].id)
+				}
+			}
+		}
+	}
+}
+
+// TestWriteSVGEmpty checks that a synthdef without ugens is drawn as an empty image.
+func TestWriteSVGEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Synthdef{Name: "empty"}).WriteSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var svg struct {
+		Width  int `xml:"width,attr"`
+		Height int `xml:"height,attr"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2*svgMargin, svg.Width; expected != got {
+		t.Fatalf("expected width %d, got %d", expected, got)
+	}
+	if expected, got := 2*svgMargin, svg.Height; expected != got {
+		t.Fatalf("expected height %d, got %d", expected, got)
+	}
+}
@@ -0,0 +1,482 @@
+package sc
+
+import (
+	"fmt"
+	"math"
+)
+
+// BinaryOpUGen special indices that are not exposed as ugen methods.
+const (
+	binOpRandRange    = 47
+	binOpExpRandRange = 48
+)
+
+// pureUgens are ugens that the optimizer may merge or remove.
+// They are deterministic, have no side effects, and only read their inputs,
+// so two of them with the same inputs compute the same outputs.
+// Ugens that are not in this list are never merged or removed, since they
+// may write to buses or buffers, have a done action, read buses or buffers
+// (which depends on the order ugens run in), or be non-deterministic.
+var pureUgens = map[string]struct{}{
+	// Operators.
+	BinOpUgenName:   {},
+	UnaryOpUgenName: {},
+	"MulAdd":        {},
+	"Sum3":          {},
+	"Sum4":          {},
+
+	// Oscillators.
+	"Blip":     {},
+	"FSinOsc":  {},
+	"Formant":  {},
+	"Impulse":  {},
+	"Klang":    {},
+	"LFCub":    {},
+	"LFPar":    {},
+	"LFPulse":  {},
+	"LFSaw":    {},
+	"LFTri":    {},
+	"Pulse":    {},
+	"Saw":      {},
+	"SinOsc":   {},
+	"SinOscFB": {},
+	"SyncSaw":  {},
+	"VarSaw":   {},
+
+	// Filters and delays.
+	"AllpassC":   {},
+	"AllpassL":   {},
+	"AllpassN":   {},
+	"BAllPass":   {},
+	"BLowPass":   {},
+	"BPF":        {},
+	"BRF":        {},
+	"Ball":       {},
+	"CombC":      {},
+	"CombL":      {},
+	"CombN":      {},
+	"Decay":      {},
+	"Decay2":     {},
+	"DelayC":     {},
+	"DelayL":     {},
+	"DelayN":     {},
+	"Formlet":    {},
+	"FreeVerb":   {},
+	"HPF":        {},
+	"Integrator": {},
+	"Klank":      {},
+	"LPF":        {},
+	"Lag":        {},
+	"LeakDC":     {},
+	"Limiter":    {},
+	"Median":     {},
+	"OnePole":    {},
+	"OneZero":    {},
+	"RLPF":       {},
+	"Resonz":     {},
+	"Ringz":      {},
+	"Slew":       {},
+	"Slope":      {},
+	"Spring":     {},
+
+	// Triggers.
+	"Gate":         {},
+	"Latch":        {},
+	"PulseCount":   {},
+	"PulseDivider": {},
+	"RunningSum":   {},
+	"Sweep":        {},
+	"TDelay":       {},
+	"ToggleFF":     {},
+	"Trig":         {},
+	"Trig1":        {},
+
+	// Panners and mixers.
+	"Balance2":  {},
+	"DecodeB2":  {},
+	"LinPan2":   {},
+	"LinXFade2": {},
+	"Pan2":      {},
+	"Pan4":      {},
+	"PanAz":     {},
+	"PanB2":     {},
+	"Rotate2":   {},
+	"XFade2":    {},
+
+	// Utilities.
+	"ControlRate":    {},
+	"DC":             {},
+	"Hasher":         {},
+	"ModDif":         {},
+	"NumOutputBuses": {},
+	"SampleDur":      {},
+	"SampleRate":     {},
+	"Select":         {},
+}
+
+// Optimize returns an optimized copy of the synthdef.
+// The optimizer
+//   - replaces BinaryOpUGen and UnaryOpUGen ugens whose inputs are all constants with a constant,
+//   - merges ugens that have the same name, rate, special index, and inputs,
+//   - removes ugens whose outputs are not used.
+//
+// Only ugens that are known to be pure (see pureUgens) are merged or removed.
+// Ugens that have side effects (e.g. Out and DetectSilence), are
+// non-deterministic (e.g. WhiteNoise and Rand), or are not known are kept.
+// The receiver is not modified.
+func (def *Synthdef) Optimize() *Synthdef {
+	opt := &Synthdef{
+		Name:               def.Name,
+		InitialParamValues: append([]float32{}, def.InitialParamValues...),
+		ParamNames:         append([]ParamName{}, def.ParamNames...),
+		Variants:           copyVariants(def.Variants),
+	}
+	var (
+		// outputs maps the outputs of each ugen in def to inputs in opt.
+		outputs = make([][]UgenInput, len(def.Ugens))
+
+		// merged maps the keys of pure ugens to their index in opt.
+		merged = map[string]int{}
+	)
+	for i, u := range def.Ugens {
+		inputs := make([]UgenInput, len(u.Inputs))
+		for j, in := range u.Inputs {
+			if in.IsConstant() {
+				inputs[j] = opt.constantInput(def.Constants[in.OutputIndex])
+			} else {
+				inputs[j] = outputs[in.UgenIndex][in.OutputIndex]
+			}
+		}
+		if val, ok := opt.fold(u, inputs); ok {
+			outputs[i] = []UgenInput{opt.constantInput(val)}
+			continue
+		}
+		pure := !u.isImpure()
+		key := u.mergeKey(inputs)
+		if idx, ok := merged[key]; ok && pure {
+			outputs[i] = ugenOutputs(idx, len(opt.Ugens[idx].Outputs))
+			continue
+		}
+		ugen := cloneUgen(u)
+		ugen.Inputs = inputs
+		ugen.Outputs = append([]Output{}, u.Outputs...)
+
+		idx := len(opt.Ugens)
+		opt.Ugens = append(opt.Ugens, ugen)
+		if pure {
+			merged[key] = idx
+		}
+		outputs[i] = ugenOutputs(idx, len(ugen.Outputs))
+	}
+	opt.removeDeadUgens()
+	opt.removeUnusedConstants()
+	return opt
+}
+
+// copyVariants returns a copy of variants that doesn't share any memory with them.
+func copyVariants(variants []*Variant) []*Variant {
+	if variants == nil {
+		return nil
+	}
+	copies := make([]*Variant, len(variants))
+	for i, v := range variants {
+		copies[i] = &Variant{
+			Name:               v.Name,
+			InitialParamValues: append([]float32{}, v.InitialParamValues...),
+		}
+	}
+	return copies
+}
+
+// constantInput returns an input for a constant, adding the constant if necessary.
+func (def *Synthdef) constantInput(val float32) UgenInput {
+	return UgenInput{
+		UgenIndex:   constantUgenIndex,
+		OutputIndex: int32(def.addConstant(C(val))),
+	}
+}
+
+// fold returns the value of a BinaryOpUGen or UnaryOpUGen whose inputs are all constants.
+// It returns false if the ugen can not be folded.
+func (def *Synthdef) fold(u *Ugen, inputs []UgenInput) (float32, bool) {
+	vals := make([]float64, len(inputs))
+	for i, in := range inputs {
+		if !in.IsConstant() {
+			return 0, false
+		}
+		vals[i] = float64(def.Constants[in.OutputIndex])
+	}
+	var (
+		val float64
+		ok  bool
+	)
+	switch {
+	case u.Name == UnaryOpUgenName && len(vals) == 1:
+		val, ok = foldUnaryOp(u.SpecialIndex, vals[0])
+	case u.Name == BinOpUgenName && len(vals) == 2:
+		val, ok = foldBinOp(u.SpecialIndex, vals[0], vals[1])
+	}
+	if !ok || math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, false
+	}
+	return float32(val), true
+}
+
+// removeDeadUgens removes pure ugens whose outputs are not used.
+// It relies on the ugens being sorted so that every ugen
+// comes after the ugens it uses as inputs.
+func (def *Synthdef) removeDeadUgens() {
+	live := make([]bool, len(def.Ugens))
+	for i := len(def.Ugens) - 1; i >= 0; i-- {
+		u := def.Ugens[i]
+		if !live[i] && !u.isImpure() {
+			continue
+		}
+		live[i] = true
+		for _, in := range u.Inputs {
+			if !in.IsConstant() {
+				live[in.UgenIndex] = true
+			}
+		}
+	}
+	var (
+		ugens   = []*Ugen{}
+		indices = make([]int32, len(def.Ugens))
+	)
+	for i, u := range def.Ugens {
+		if !live[i] {
+			continue
+		}
+		indices[i] = int32(len(ugens))
+		ugens = append(ugens, u)
+	}
+	for _, u := range ugens {
+		for j, in := range u.Inputs {
+			if !in.IsConstant() {
+				u.Inputs[j].UgenIndex = indices[in.UgenIndex]
+			}
+		}
+	}
+	def.Ugens = ugens
+}
+
+// removeUnusedConstants removes constants that are not used as inputs.
+// The remaining constants keep the order they were added in.
+func (def *Synthdef) removeUnusedConstants() {
+	used := make([]bool, len(def.Constants))
+	for _, u := range def.Ugens {
+		for _, in := range u.Inputs {
+			if in.IsConstant() {
+				used[in.OutputIndex] = true
+			}
+		}
+	}
+	var (
+		constants = []float32{}
+		indices   = make([]int32, len(def.Constants))
+	)
+	for i, c := range def.Constants {
+		if !used[i] {
+			continue
+		}
+		indices[i] = int32(len(constants))
+		constants = append(constants, c)
+	}
+	for _, u := range def.Ugens {
+		for j, in := range u.Inputs {
+			if in.IsConstant() {
+				u.Inputs[j].OutputIndex = indices[in.OutputIndex]
+			}
+		}
+	}
+	def.Constants = constants
+}
+
+// isImpure returns true if the optimizer must not merge or remove the ugen.
+func (u *Ugen) isImpure() bool {
+	if len(u.Outputs) == 0 {
+		return true // Ugens without outputs only exist for their side effects.
+	}
+	switch u.Name {
+	case UnaryOpUgenName:
+		switch u.SpecialIndex {
+		case UnaryOpRand, UnaryOpRand2, UnaryOpLinrand, UnaryOpBilinrand, UnaryOpSum3rand, UnaryOpCoin:
+			return true
+		}
+	case BinOpUgenName:
+		switch u.SpecialIndex {
+		case binOpRandRange, binOpExpRandRange:
+			return true
+		}
+	}
+	_, pure := pureUgens[u.Name]
+	return !pure
+}
+
+// mergeKey returns a key that is the same for ugens that compute the same outputs.
+// The inputs of commutative operators are sorted so that e.g. a+b and b+a have the same key.
+func (u *Ugen) mergeKey(inputs []UgenInput) string {
+	if !u.inputsOrdered() && len(inputs) == 2 {
+		a, b := inputs[0], inputs[1]
+		if b.UgenIndex < a.UgenIndex || (b.UgenIndex == a.UgenIndex && b.OutputIndex < a.OutputIndex) {
+			inputs = []UgenInput{b, a}
+		}
+	}
+	return fmt.Sprintf("%s %d %d %d %v", u.Name, u.Rate, u.SpecialIndex, len(u.Outputs), inputs)
+}
+
+// ugenOutputs returns inputs for all the outputs of a ugen.
+func ugenOutputs(idx, numOutputs int) []UgenInput {
+	inputs := make([]UgenInput, numOutputs)
+	for i := range inputs {
+		inputs[i] = UgenInput{
+			UgenIndex:   int32(idx),
+			OutputIndex: int32(i),
+		}
+	}
+	return inputs
+}
+
+// foldUnaryOp computes a UnaryOpUGen.
+// It returns false for operators that can not be folded.
+func foldUnaryOp(op int16, x float64) (float64, bool) {
+	switch op {
+	case UnaryOpNeg:
+		return -x, true
+	case UnaryOpAbs:
+		return math.Abs(x), true
+	case UnaryOpCeil:
+		return math.Ceil(x), true
+	case UnaryOpFloor:
+		return math.Floor(x), true
+	case UnaryOpFrac:
+		return x - math.Floor(x), true
+	case UnaryOpSign:
+		switch {
+		case x > 0:
+			return 1, true
+		case x < 0:
+			return -1, true
+		}
+		return 0, true
+	case UnaryOpSquared:
+		return x * x, true
+	case UnaryOpCubed:
+		return x * x * x, true
+	case UnaryOpSqrt:
+		if x < 0 {
+			return -math.Sqrt(-x), true
+		}
+		return math.Sqrt(x), true
+	case UnaryOpExp:
+		return math.Exp(x), true
+	case UnaryOpReciprocal:
+		return 1 / x, true
+	case UnaryOpMidicps:
+		return 440 * math.Pow(2, (x-69)/12), true
+	case UnaryOpCpsmidi:
+		return math.Log2(x/440)*12 + 69, true
+	case UnaryOpMidiratio:
+		return math.Pow(2, x/12), true
+	case UnaryOpRatiomidi:
+		return 12 * math.Log2(x), true
+	case UnaryOpDbAmp:
+		return math.Pow(10, x/20), true
+	case UnaryOpAmpDb:
+		return 20 * math.Log10(x), true
+	case UnaryOpOctcps:
+		return 440 * math.Pow(2, x-4.75), true
+	case UnaryOpCpsoct:
+		return math.Log2(x/440) + 4.75, true
+	case UnaryOpLog:
+		return math.Log(x), true
+	case UnaryOpLog2:
+		return math.Log2(x), true
+	case UnaryOpLog10:
+		return math.Log10(x), true
+	case UnaryOpSin:
+		return math.Sin(x), true
+	case UnaryOpCos:
+		return math.Cos(x), true
+	case UnaryOpTan:
+		return math.Tan(x), true
+	case UnaryOpAsin:
+		return math.Asin(x), true
+	case UnaryOpAcos:
+		return math.Acos(x), true
+	case UnaryOpAtan:
+		return math.Atan(x), true
+	case UnaryOpSinh:
+		return math.Sinh(x), true
+	case UnaryOpCosh:
+		return math.Cosh(x), true
+	case UnaryOpTanh:
+		return math.Tanh(x), true
+	}
+	return 0, false
+}
+
+// foldBinOp computes a BinaryOpUGen.
+// It returns false for operators that can not be folded.
+func foldBinOp(op int16, x, y float64) (float64, bool) {
+	switch op {
+	case BinOpAdd:
+		return x + y, true
+	case BinOpMul:
+		return x * y, true
+	case BinOpDiv:
+		return x / y, true
+	case BinOpModulo:
+		if y == 0 {
+			return 0, false
+		}
+		return x - y*math.Floor(x/y), true
+	case BinOpMin:
+		return math.Min(x, y), true
+	case BinOpMax:
+		return math.Max(x, y), true
+	case BinOpLT:
+		return boolToFloat(x < y), true
+	case BinOpGT:
+		return boolToFloat(x > y), true
+	case BinOpLTE:
+		return boolToFloat(x <= y), true
+	case BinOpGTE:
+		return boolToFloat(x >= y), true
+	case BinOpPow:
+		if x < 0 {
+			return -math.Pow(-x, y), true
+		}
+		return math.Pow(x, y), true
+	case BinOpAtan2:
+		return math.Atan2(x, y), true
+	case BinOpHypot:
+		return math.Hypot(x, y), true
+	case BinOpAbsdif:
+		return math.Abs(x - y), true
+	case BinOpSumsqr:
+		return x*x + y*y, true
+	case BinOpDifsqr:
+		return x*x - y*y, true
+	case BinOpSqrsum:
+		return (x + y) * (x + y), true
+	case BinOpSqrdif:
+		return (x - y) * (x - y), true
+	case BinOpThresh:
+		if x < y {
+			return 0, true
+		}
+		return x, true
+	case BinOpClip2:
+		return math.Max(-y, math.Min(x, y)), true
+	}
+	return 0, false
+}
+
+// boolToFloat returns 1 for true and 0 for false.
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
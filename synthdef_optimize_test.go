@@ -0,0 +1,231 @@
+package sc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOptimizeMerge(t *testing.T) {
+	def := NewSynthdef("OptimizeMerge", func(p Params) Ugen {
+		var (
+			amp  = p.Add("amp", 0.5)
+			sig1 = SinOsc{Freq: C(440)}.Rate(AR).Mul(amp.Mul(C(2)))
+			sig2 = SinOsc{Freq: C(440)}.Rate(AR).Mul(amp.Mul(C(2)))
+		)
+		return Out{Bus: C(0), Channels: sig1.Add(sig2)}.Rate(AR)
+	})
+	opt := def.Optimize()
+
+	// Control, SinOsc, amp*2, SinOsc*(amp*2), Add, Out
+	if expected, got := 6, len(opt.Ugens); expected != got {
+		t.Fatalf("expected %d ugens, got %d", expected, got)
+	}
+	if expected, got := 9, len(def.Ugens); expected != got {
+		t.Fatalf("expected the receiver to be unchanged (%d ugens), got %d ugens", expected, got)
+	}
+	checkOptimizedDef(t, opt)
+}
+
+func TestOptimizeVariants(t *testing.T) {
+	def := NewSynthdef("OptimizeVariants", func(p Params) Ugen {
+		return Out{Bus: C(0), Channels: SinOsc{Freq: p.Add("freq", 440)}.Rate(AR)}.Rate(AR)
+	})
+	def.Variants = []*Variant{{Name: "low", InitialParamValues: []float32{220}}}
+
+	opt := def.Optimize()
+	opt.Variants[0].Name = "high"
+	opt.Variants[0].InitialParamValues[0] = 880
+
+	// The receiver's variants are not changed.
+	if expected, got := "low", def.Variants[0].Name; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := float32(220), def.Variants[0].InitialParamValues[0]; expected != got {
+		t.Fatalf("expected %g, got %g", expected, got)
+	}
+}
+
+func TestOptimizeImpure(t *testing.T) {
+	def := NewSynthdef("OptimizeImpure", func(p Params) Ugen {
+		var (
+			noise1 = WhiteNoise{}.Rate(AR)
+			noise2 = WhiteNoise{}.Rate(AR)
+		)
+		return Out{Bus: C(0), Channels: noise1.Add(noise2)}.Rate(AR)
+	})
+	opt := def.Optimize()
+
+	if expected, got := len(def.Ugens), len(opt.Ugens); expected != got {
+		t.Fatalf("expected %d ugens, got %d", expected, got)
+	}
+	checkOptimizedDef(t, opt)
+}
+
+func TestOptimizeDoneAction(t *testing.T) {
+	// An LFGauss that frees the synth when it is done is kept even though
+	// its output is not used.
+	def := &Synthdef{
+		Name:      "OptimizeDoneAction",
+		Constants: []float32{1, 0.1, 0, float32(FreeEnclosing), 440},
+		Ugens: []*Ugen{
+			{
+				Name: "LFGauss",
+				Rate: KR,
+				Inputs: []UgenInput{
+					{UgenIndex: -1, OutputIndex: 0},
+					{UgenIndex: -1, OutputIndex: 1},
+					{UgenIndex: -1, OutputIndex: 2},
+					{UgenIndex: -1, OutputIndex: 2},
+					{UgenIndex: -1, OutputIndex: 3},
+				},
+				Outputs: []Output{KR},
+			},
+			{
+				Name: "SinOsc",
+				Rate: AR,
+				Inputs: []UgenInput{
+					{UgenIndex: -1, OutputIndex: 4},
+					{UgenIndex: -1, OutputIndex: 2},
+				},
+				Outputs: []Output{AR},
+			},
+			{
+				Name: "Out",
+				Rate: AR,
+				Inputs: []UgenInput{
+					{UgenIndex: -1, OutputIndex: 2},
+					{UgenIndex: 1, OutputIndex: 0},
+				},
+			},
+		},
+	}
+	opt := def.Optimize()
+
+	if expected, got := len(def.Ugens), len(opt.Ugens); expected != got {
+		t.Fatalf("expected %d ugens, got %d", expected, got)
+	}
+	if expected, got := "LFGauss", opt.Ugens[0].Name; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	checkOptimizedDef(t, opt)
+}
+
+func TestOptimizeFold(t *testing.T) {
+	// Ugens that are created by tools that don't fold constants,
+	// and ugens that are not used.
+	def := &Synthdef{
+		Name:      "OptimizeFold",
+		Constants: []float32{69, 2, 0},
+		Ugens: []*Ugen{
+			{
+				Name:         UnaryOpUgenName,
+				Rate:         IR,
+				SpecialIndex: UnaryOpMidicps,
+				Inputs:       []UgenInput{{UgenIndex: -1, OutputIndex: 0}},
+				Outputs:      []Output{IR},
+			},
+			{
+				Name:         BinOpUgenName,
+				Rate:         IR,
+				SpecialIndex: BinOpDiv,
+				Inputs:       []UgenInput{{UgenIndex: 0, OutputIndex: 0}, {UgenIndex: -1, OutputIndex: 1}},
+				Outputs:      []Output{IR},
+			},
+			{
+				Name:    "LFSaw",
+				Rate:    AR,
+				Inputs:  []UgenInput{{UgenIndex: -1, OutputIndex: 1}, {UgenIndex: -1, OutputIndex: 2}},
+				Outputs: []Output{AR},
+			},
+			{
+				Name:    "WhiteNoise",
+				Rate:    AR,
+				Outputs: []Output{AR},
+			},
+			{
+				Name:    "SinOsc",
+				Rate:    AR,
+				Inputs:  []UgenInput{{UgenIndex: 1, OutputIndex: 0}, {UgenIndex: -1, OutputIndex: 2}},
+				Outputs: []Output{AR},
+			},
+			{
+				Name:   "Out",
+				Rate:   AR,
+				Inputs: []UgenInput{{UgenIndex: -1, OutputIndex: 2}, {UgenIndex: 4, OutputIndex: 0}},
+			},
+		},
+	}
+	opt := def.Optimize()
+
+	expected := &Synthdef{
+		Name:      "OptimizeFold",
+		Constants: []float32{220, 0},
+		Ugens: []*Ugen{
+			{
+				Name:    "WhiteNoise",
+				Rate:    AR,
+				Inputs:  []UgenInput{},
+				Outputs: []Output{AR},
+			},
+			{
+				Name:    "SinOsc",
+				Rate:    AR,
+				Inputs:  []UgenInput{{UgenIndex: -1, OutputIndex: 0}, {UgenIndex: -1, OutputIndex: 1}},
+				Outputs: []Output{AR},
+			},
+			{
+				Name:    "Out",
+				Rate:    AR,
+				Inputs:  []UgenInput{{UgenIndex: -1, OutputIndex: 1}, {UgenIndex: 1, OutputIndex: 0}},
+				Outputs: []Output{},
+			},
+		},
+	}
+	same, err := expected.CompareToDef(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same {
+		buf := &bytes.Buffer{}
+		_ = opt.WriteJSON(buf)
+		t.Fatalf("unexpected optimized synthdef %s", buf.String())
+	}
+}
+
+func TestOptimizeFixtures(t *testing.T) {
+	for _, fixture := range readFixtures(t) {
+		path, def := fixture.path, fixture.def
+
+		opt := def.Optimize()
+		if len(opt.Ugens) > len(def.Ugens) {
+			t.Fatalf("%s: optimized synthdef has more ugens (%d) than the original (%d)", path, len(opt.Ugens), len(def.Ugens))
+		}
+		checkOptimizedDef(t, opt)
+	}
+}
+
+// checkOptimizedDef checks that an optimized synthdef can be written and read back.
+func checkOptimizedDef(t *testing.T, def *Synthdef) {
+	data, err := def.Bytes()
+	if err != nil {
+		t.Fatalf("%s: %s", def.Name, err)
+	}
+	read, err := ReadSynthdef(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: %s", def.Name, err)
+	}
+	same, err := def.CompareToDef(read)
+	if err != nil {
+		t.Fatalf("%s: %s", def.Name, err)
+	}
+	if !same {
+		t.Fatalf("%s: optimized synthdef changed when it was read back", def.Name)
+	}
+	for i, u := range def.Ugens {
+		for _, in := range u.Inputs {
+			if !in.IsConstant() && in.UgenIndex >= int32(i) {
+				t.Fatalf("%s: ugen %d uses ugen %d as an input", def.Name, i, in.UgenIndex)
+			}
+		}
+	}
+}
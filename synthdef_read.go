@@ -3,41 +3,100 @@ package sc
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 )
 
-// ReadSynthdef reads a synthdef from an io.Reader
+// ReadSynthdef reads a synthdef from an io.Reader.
+// The synthdef file must contain exactly one synthdef,
+// use ReadSynthdefFile to read files that contain more than one.
 func ReadSynthdef(r io.Reader) (*Synthdef, error) {
-	// read the type
-	if err := readSynthdefType(r); err != nil {
+	f, err := ReadSynthdefFile(r)
+	if err != nil {
 		return nil, err
 	}
-	// read version
-	if err := readSynthdefVersion(r); err != nil {
+	if numDefs := len(f.Synthdefs); numDefs != 1 {
+		return nil, fmt.Errorf("expected 1 synthdef, got %d (use ReadSynthdefFile)", numDefs)
+	}
+	return f.Synthdefs[0], nil
+}
+
+// ReadSynthdefJSON reads a synthdef that was written with WriteJSON.
+func ReadSynthdefJSON(r io.Reader) (*Synthdef, error) {
+	doc := synthdefDoc{Synthdef: &Synthdef{}}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if err := doc.check(); err != nil {
+		return nil, err
+	}
+	return doc.Synthdef, nil
+}
+
+// ReadSynthdefXML reads a synthdef that was written with WriteXML.
+func ReadSynthdefXML(r io.Reader) (*Synthdef, error) {
+	doc := synthdefDoc{Synthdef: &Synthdef{}}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
 		return nil, err
 	}
-	// read number of synth defs
-	if err := readNumberOfSynthdefs(r); err != nil {
+	if err := doc.check(); err != nil {
 		return nil, err
 	}
+	return doc.Synthdef, nil
+}
+
+// check returns an error if a synthdef that was read from json or xml
+// can not be written as a synthdef file.
+// Problems that scsynth tolerates, like rate mismatches, are left to Validate.
+func (doc synthdefDoc) check() error {
+	if doc.Schema < 1 || doc.Schema > SynthdefSchema {
+		return fmt.Errorf("unsupported synthdef schema %d (expected 1 to %d)", doc.Schema, SynthdefSchema)
+	}
+	def := doc.Synthdef
+
+	for i, u := range def.Ugens {
+		if u == nil {
+			return fmt.Errorf("ugen %d is missing", i)
+		}
+	}
+	for i, v := range def.Variants {
+		if v == nil {
+			return fmt.Errorf("variant %d is missing", i)
+		}
+		if expected, got := len(def.InitialParamValues), len(v.InitialParamValues); expected != got {
+			return fmt.Errorf("variant %s has %d param values, expected %d", v.Name, got, expected)
+		}
+	}
+	for _, issue := range def.Validate() {
+		if issue.Kind == InvalidIndex {
+			return issue
+		}
+	}
+	return nil
+}
+
+// readSynthdef reads a single synthdef from a synthdef file.
+// It expects the reader to be positioned at the synthdef name.
+func readSynthdef(r io.Reader, version int32) (*Synthdef, error) {
 	// read synthdef name
 	defName, err := readPstring(r)
 	if err != nil {
 		return nil, err
 	}
 	// read constants
-	constants, err := readSynthdefConstants(r)
+	constants, err := readSynthdefConstants(r, version)
 	if err != nil {
 		return nil, err
 	}
 	// read param initial values and names
-	initialValues, paramNames, err := readSynthdefParams(r)
+	initialValues, paramNames, err := readSynthdefParams(r, version)
 	if err != nil {
 		return nil, err
 	}
 	// read ugens
-	ugens, err := readSynthdefUgens(r)
+	ugens, err := readSynthdefUgens(r, version)
 	if err != nil {
 		return nil, err
 	}
@@ -77,38 +136,35 @@ func readSynthdefType(r io.Reader) error {
 
 // readSynthdefVersion reads the version of a synthdef file
 // and returns an error if it is an unsupported version.
-// Otherwise it returns nil.
-func readSynthdefVersion(r io.Reader) error {
+func readSynthdefVersion(r io.Reader) (int32, error) {
 	var version int32
 	if err := binary.Read(r, byteOrder, &version); err != nil {
-		return err
+		return 0, err
 	}
-	if version != synthdefVersion {
-		return fmt.Errorf("bad synthdef version %d", version)
+	if err := checkSynthdefVersion(version); err != nil {
+		return 0, err
 	}
-	return nil
+	return version, nil
 }
 
 // readNumberOfSynthdefs reads the number of synthdefs in
 // a particular synthdef file.
-// We only support 1 synthdef per file, so if this number is not 1
-// this func will return an error.
-func readNumberOfSynthdefs(r io.Reader) error {
+func readNumberOfSynthdefs(r io.Reader) (int, error) {
 	var numDefs int16
 	if err := binary.Read(r, byteOrder, &numDefs); err != nil {
-		return err
+		return 0, err
 	}
-	if numDefs != 1 {
-		return fmt.Errorf("multiple synthdefs not supported")
+	if numDefs < 0 {
+		return 0, fmt.Errorf("bad number of synthdefs %d", numDefs)
 	}
-	return nil
+	return int(numDefs), nil
 }
 
 // readSynthdefConstants reads the constants of a synthdef.
-func readSynthdefConstants(r io.Reader) ([]float32, error) {
+func readSynthdefConstants(r io.Reader, version int32) ([]float32, error) {
 	// read number of constants
-	var numConstants int32
-	if err := binary.Read(r, byteOrder, &numConstants); err != nil {
+	numConstants, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, err
 	}
 	// read constants
@@ -123,10 +179,10 @@ func readSynthdefConstants(r io.Reader) ([]float32, error) {
 
 // readSynthdefParams reads the initial param values and param names
 // of a synthdef.
-func readSynthdefParams(r io.Reader) ([]float32, []ParamName, error) {
+func readSynthdefParams(r io.Reader, version int32) ([]float32, []ParamName, error) {
 	// read number of parameters
-	var numParams int32
-	if err := binary.Read(r, byteOrder, &numParams); err != nil {
+	numParams, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, nil, err
 	}
 	// read initial parameter values
@@ -137,14 +193,14 @@ func readSynthdefParams(r io.Reader) ([]float32, []ParamName, error) {
 		}
 	}
 	// read number of parameter names
-	var numParamNames int32
-	if err := binary.Read(r, byteOrder, &numParamNames); err != nil {
+	numParamNames, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, nil, err
 	}
 	// read param names
 	paramNames := make([]ParamName, numParamNames)
 	for i := 0; int32(i) < numParamNames; i++ {
-		pn, err := readParamName(r)
+		pn, err := readParamName(r, version)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -154,16 +210,16 @@ func readSynthdefParams(r io.Reader) ([]float32, []ParamName, error) {
 }
 
 // readSynthdefUgens reads the ugens of a synthdef.
-func readSynthdefUgens(r io.Reader) ([]*Ugen, error) {
+func readSynthdefUgens(r io.Reader, version int32) ([]*Ugen, error) {
 	// read number of ugens
-	var numUgens int32
-	if err := binary.Read(r, byteOrder, &numUgens); err != nil {
+	numUgens, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, err
 	}
 	// read ugens
 	ugens := make([]*Ugen, numUgens)
 	for i := 0; int32(i) < numUgens; i++ {
-		ugen, err := readUgen(r)
+		ugen, err := readUgen(r, version)
 		if err != nil {
 			return nil, err
 		}
@@ -190,3 +246,16 @@ func readSynthdefVariants(r io.Reader, numParams int) ([]*Variant, error) {
 	}
 	return variants, nil
 }
+
+// readSynthdefInt reads a count or an index from a synthdef file.
+// They are int16 in version 1 files and int32 in version 2 files.
+func readSynthdefInt(r io.Reader, version int32) (int32, error) {
+	if version == SynthdefVersion1 {
+		var val int16
+		err := binary.Read(r, byteOrder, &val)
+		return int32(val), err
+	}
+	var val int32
+	err := binary.Read(r, byteOrder, &val)
+	return val, err
+}
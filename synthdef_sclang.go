@@ -0,0 +1,662 @@
+package sc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// sclangInfix are the binary operators that are written between their operands.
+// == and != are missing because they compare UGens instead of creating a BinaryOpUGen.
+var sclangInfix = map[string]string{
+	"+":   "sum",
+	"-":   "difference",
+	"*":   "product",
+	"/":   "quotient",
+	"%":   "modulo",
+	"<":   "lessThan",
+	">":   "greaterThan",
+	"<=":  "lessOrEqual",
+	">=":  "greaterOrEqual",
+	"pow": "power",
+}
+
+// sclangNumChannels are multichannel ugens whose sclang constructor takes
+// the number of channels as an argument that is not a ugen input.
+// The value says whether the number of channels comes first (true)
+// or after the inputs (false).
+var sclangNumChannels = map[string]bool{
+	"BufRd":      true,
+	"DecodeB2":   true,
+	"DiskIn":     true,
+	"GrainBuf":   true,
+	"GrainFM":    true,
+	"GrainIn":    true,
+	"GrainSin":   true,
+	"In":         false,
+	"InFeedback": false,
+	"InTrig":     false,
+	"LagIn":      false,
+	"LocalIn":    true,
+	"PanAz":      true,
+	"PlayBuf":    true,
+	"TGrains":    true,
+	"VDiskIn":    true,
+	"Warp1":      true,
+}
+
+// sclangArrays are ugens whose sclang constructor takes an array argument
+// that becomes all the ugen inputs after the given index.
+var sclangArrays = map[string]int{
+	"DiskOut":    1,
+	"LocalOut":   0,
+	"OffsetOut":  1,
+	"Out":        1,
+	"ReplaceOut": 1,
+	"Select":     1,
+	"XOut":       2,
+}
+
+// sclangEnvShapes are the names of envelope segment shapes, indexed by shape number.
+// Shape 5 uses the curve value of the segment instead of a name.
+var sclangEnvShapes = []string{`\step`, `\lin`, `\exp`, `\sin`, `\wel`, "", `\sqr`, `\cub`, `\hold`}
+
+// sclangKeywords are identifiers that can't be used as variable names in sclang.
+var sclangKeywords = map[string]bool{
+	"arg": true, "classvar": true, "const": true, "false": true, "inf": true,
+	"nil": true, "pi": true, "super": true, "this": true, "thisFunction": true,
+	"thisFunctionDef": true, "thisMethod": true, "thisProcess": true, "thisThread": true,
+	"true": true, "var": true,
+}
+
+// sclangLineLength is the length of an expression above which
+// the expression is assigned to a variable instead of being inlined.
+const sclangLineLength = 60
+
+// WriteSclang writes the synthdef as sclang SynthDef source, e.g.
+//
+//	SynthDef(\SineTone, { |freq = 440|
+//	    Out.ar(0, SinOsc.ar(freq, 0));
+//	})
+//
+// Params become arguments of the ugen graph function, ugens that are used
+// more than once or that have more than one output are assigned to variables,
+// operators are written with infix or method syntax, and constants are inlined.
+// The output only depends on the synthdef, so it can be used in golden files.
+// It is meant to be read by people, sclang may not build the exact same
+// synthdef from it.
+func (def *Synthdef) WriteSclang(w io.Writer) error {
+	s := &sclangWriter{
+		def:    def,
+		refs:   make([]int, len(def.Ugens)),
+		exprs:  make([]sclangExpr, len(def.Ugens)),
+		vars:   make([]string, len(def.Ugens)),
+		names:  map[string]bool{},
+		params: make([]string, len(def.InitialParamValues)),
+		sizes:  make([]int, len(def.InitialParamValues)),
+	}
+	var buf bytes.Buffer
+	if err := s.write(&buf); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// sclangExpr is an sclang expression.
+type sclangExpr struct {
+	text  string
+	infix bool // infix says whether the expression is an infix operation
+}
+
+// sclangWriter writes sclang code for a synthdef.
+type sclangWriter struct {
+	def    *Synthdef
+	refs   []int        // refs counts how many times each ugen is used as an input
+	exprs  []sclangExpr // exprs are the expressions that create each ugen
+	vars   []string     // vars are the names of the variables that hold ugens
+	names  map[string]bool
+	params []string // params are the param names by value index, "" for values inside array params
+	sizes  []int    // sizes are the number of values of each param, by value index
+}
+
+// write writes the SynthDef.
+func (s *sclangWriter) write(w io.Writer) error {
+	if err := s.def.validateGraph(); err != nil {
+		return err
+	}
+	if err := s.readParams(); err != nil {
+		return err
+	}
+	for _, u := range s.def.Ugens {
+		for _, in := range u.Inputs {
+			if !in.IsConstant() {
+				s.refs[in.UgenIndex]++
+			}
+		}
+	}
+	var decls, stmts bytes.Buffer
+
+	for i, u := range s.def.Ugens {
+		if _, ok := controlUgens[u.Name]; ok {
+			continue
+		}
+		expr, err := s.ugen(i)
+		if err != nil {
+			return err
+		}
+		if s.refs[i] == 0 {
+			fmt.Fprintf(&stmts, "    %s;\n", expr.text)
+			continue
+		}
+		if s.refs[i] > 1 || len(u.Outputs) > 1 || len(expr.text) > sclangLineLength {
+			s.vars[i] = s.name(s.varName(u))
+			fmt.Fprintf(&decls, "    var %s = %s;\n", s.vars[i], expr.text)
+			continue
+		}
+		s.exprs[i] = expr
+	}
+	fmt.Fprintf(w, "SynthDef(%s, {%s\n", sclangSymbol(s.def.Name), s.argList())
+	_, _ = decls.WriteTo(w)
+	_, _ = stmts.WriteTo(w)
+	fmt.Fprintf(w, "}%s)\n", s.options())
+	return nil
+}
+
+// readParams reads the names and sizes of the params.
+func (s *sclangWriter) readParams() error {
+	numValues := len(s.def.InitialParamValues)
+
+	for _, pn := range s.def.ParamNames {
+		if pn.Index < 0 || int(pn.Index) >= numValues {
+			return fmt.Errorf("param %s has index %d, but there are %d param values", pn.Name, pn.Index, numValues)
+		}
+		if !isSclangIdentifier(pn.Name) {
+			return fmt.Errorf("param %s can not be used as an sclang argument name", pn.Name)
+		}
+		if s.params[pn.Index] != "" {
+			return fmt.Errorf("params %s and %s have the same index", s.params[pn.Index], pn.Name)
+		}
+		s.params[pn.Index] = pn.Name
+		s.names[pn.Name] = true
+	}
+	for i := 0; i < numValues; i++ {
+		if s.params[i] == "" {
+			return fmt.Errorf("param value %d does not belong to a param", i)
+		}
+		j := i + 1
+		for ; j < numValues && s.params[j] == ""; j++ {
+		}
+		s.sizes[i] = j - i
+		i = j - 1
+	}
+	return nil
+}
+
+// argList returns the argument list of the ugen graph function.
+func (s *sclangWriter) argList() string {
+	var args []string
+
+	for i := 0; i < len(s.params); i += s.sizes[i] {
+		values := s.def.InitialParamValues[i : i+s.sizes[i]]
+		args = append(args, s.params[i]+" = "+sclangLiteral(values))
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return " |" + strings.Join(args, ", ") + "|"
+}
+
+// options returns the SynthDef arguments that follow the ugen graph function.
+func (s *sclangWriter) options() string {
+	var (
+		opts   []string
+		rates  = make([]string, len(s.params))
+		custom bool
+	)
+	for i := range rates {
+		rates[i] = "0"
+	}
+	for _, u := range s.def.Ugens {
+		var rate string
+
+		switch {
+		case u.Name == "TrigControl":
+			rate = `\tr`
+		case u.Name == "AudioControl" || (u.Name == "Control" && u.Rate == AR):
+			rate = `\ar`
+		case u.Name == "Control" && u.Rate == IR:
+			rate = `\ir`
+		case u.Name == "LagControl":
+			for j, in := range u.Inputs {
+				if k := int(u.SpecialIndex) + j; in.IsConstant() && k < len(rates) && s.params[k] != "" {
+					rates[k] = sclangNumber(s.def.Constants[in.OutputIndex])
+					custom = custom || rates[k] != "0"
+				}
+			}
+			continue
+		default:
+			continue
+		}
+		for j := range u.Outputs {
+			if k := int(u.SpecialIndex) + j; k < len(rates) && s.params[k] != "" {
+				rates[k] = rate
+				custom = true
+			}
+		}
+	}
+	if custom {
+		var rs []string
+		for i, p := range s.params {
+			if p != "" {
+				rs = append(rs, rates[i])
+			}
+		}
+		opts = append(opts, "rates: ["+strings.Join(rs, ", ")+"]")
+	}
+	if len(s.def.Variants) > 0 {
+		var variants []string
+
+		for _, v := range s.def.Variants {
+			var values []string
+			for i := 0; i < len(s.params) && i+s.sizes[i] <= len(v.InitialParamValues); i += s.sizes[i] {
+				var (
+					defaults = s.def.InitialParamValues[i : i+s.sizes[i]]
+					vals     = v.InitialParamValues[i : i+s.sizes[i]]
+				)
+				if equalFloats(defaults, vals) {
+					continue
+				}
+				values = append(values, s.params[i]+": "+sclangLiteral(vals))
+			}
+			variants = append(variants, sclangKey(v.Name)+": ["+strings.Join(values, ", ")+"]")
+		}
+		opts = append(opts, "variants: ("+strings.Join(variants, ", ")+")")
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(opts, ", ")
+}
+
+// ugen returns the expression that creates the ugen at index i.
+func (s *sclangWriter) ugen(i int) (sclangExpr, error) {
+	u := s.def.Ugens[i]
+
+	args, err := s.inputs(i)
+	if err != nil {
+		return sclangExpr{}, err
+	}
+	operator, isOp := lookupOp(u)
+
+	switch {
+	case isOp && u.Name == BinOpUgenName && len(args) == 2:
+		op := operator.sclang
+		if _, ok := sclangInfix[op]; ok {
+			if op == "pow" {
+				op = "**"
+			}
+			return infix(op, args...), nil
+		}
+		if op != "==" && op != "!=" {
+			return method(args[0], op, args[1:]...), nil
+		}
+		return call(BinOpUgenName, "", append([]sclangExpr{{text: sclangSymbolLiteral(op)}}, args...)), nil
+
+	case isOp && u.Name == UnaryOpUgenName && len(args) == 1:
+		return method(args[0], operator.sclang), nil
+
+	case u.Name == "MulAdd" && len(args) == 3:
+		return infix("+", infix("*", args[0], args[1]), args[2]), nil
+
+	case (u.Name == "Sum3" || u.Name == "Sum4") && len(args) == len(u.Inputs):
+		return infix("+", args...), nil
+
+	case u.Name == "EnvGen" && len(args) == len(u.Inputs) && len(args) > 5:
+		if env, ok := s.env(u.Inputs[5:], args[5:]); ok {
+			return call(u.Name, rateName(u.Rate, "new"), append([]sclangExpr{env}, args[:5]...)), nil
+		}
+
+	case (u.Name == "Klang" || u.Name == "Klank") && len(args) == len(u.Inputs):
+		n := 2
+		if u.Name == "Klank" {
+			n = 4
+		}
+		if len(args) >= n && (len(args)-n)%3 == 0 {
+			return call(u.Name, rateName(u.Rate, "new"), append([]sclangExpr{specs(args[n:])}, args[:n]...)), nil
+		}
+
+	case u.Name == "LocalBuf" && len(args) >= 2:
+		return call(u.Name, "", []sclangExpr{args[1], args[0]}), nil
+	}
+	if first, ok := sclangArrays[u.Name]; ok && len(args) > first+1 {
+		array := sclangExpr{text: "[" + joinExprs(args[first:]) + "]"}
+		args = append(args[:first:first], array)
+	}
+	if first, ok := sclangNumChannels[u.Name]; ok {
+		n := sclangExpr{text: strconv.Itoa(len(u.Outputs))}
+		if first {
+			args = append([]sclangExpr{n}, args...)
+		} else {
+			args = append(args, n)
+		}
+	}
+	return call(u.Name, rateName(u.Rate, "new"), args), nil
+}
+
+// inputs returns the expressions for the inputs of the ugen at index i.
+// Consecutive inputs that read all the outputs of a multichannel ugen
+// or all the values of an array param are a single argument.
+func (s *sclangWriter) inputs(i int) ([]sclangExpr, error) {
+	var (
+		u    = s.def.Ugens[i]
+		args []sclangExpr
+	)
+	for j := 0; j < len(u.Inputs); j++ {
+		in := u.Inputs[j]
+		if in.IsConstant() {
+			args = append(args, sclangExpr{text: sclangNumber(s.def.Constants[in.OutputIndex])})
+			continue
+		}
+		var (
+			from     = s.def.Ugens[in.UgenIndex]
+			name     string
+			first, n int // first is the index of the first output of the group, n is its size
+		)
+		if _, ok := controlUgens[from.Name]; ok {
+			k := int(from.SpecialIndex) + int(in.OutputIndex)
+			if k >= len(s.params) {
+				return nil, fmt.Errorf("ugen %d (%s) reads param value %d, but there are %d param values", i, u.Name, k, len(s.params))
+			}
+			for first = k; s.params[first] == ""; first-- {
+			}
+			name, n = s.params[first], s.sizes[first]
+			first -= int(from.SpecialIndex)
+		} else if s.vars[in.UgenIndex] != "" {
+			name, n = s.vars[in.UgenIndex], len(from.Outputs)
+		} else {
+			args = append(args, s.exprs[in.UgenIndex])
+			continue
+		}
+		if n > 1 && s.readsAll(u, j, in, first, n) {
+			args = append(args, sclangExpr{text: name})
+			j += n - 1
+			continue
+		}
+		if n > 1 {
+			name = fmt.Sprintf("%s[%d]", name, int(in.OutputIndex)-first)
+		}
+		args = append(args, sclangExpr{text: name})
+	}
+	return args, nil
+}
+
+// readsAll returns true if the inputs of u that start at index j
+// read the outputs first to first+n-1 of the same ugen, in order.
+func (s *sclangWriter) readsAll(u *Ugen, j int, in UgenInput, first, n int) bool {
+	if int(in.OutputIndex) != first || j+n > len(u.Inputs) {
+		return false
+	}
+	for k := 1; k < n; k++ {
+		if next := u.Inputs[j+k]; next.UgenIndex != in.UgenIndex || int(next.OutputIndex) != first+k {
+			return false
+		}
+	}
+	return true
+}
+
+// sclangNumber returns the literal for a number.
+func sclangNumber(v float32) string {
+	switch {
+	case math.IsInf(float64(v), 1):
+		return "inf"
+	case math.IsInf(float64(v), -1):
+		return "-inf"
+	case math.IsNaN(float64(v)):
+		return "(0/0)"
+	}
+	text := strconv.FormatFloat(float64(v), 'g', -1, 32)
+
+	// sclang does not accept a plus sign or leading zeros in exponents.
+	if i := strings.IndexByte(text, 'e'); i >= 0 {
+		exp := strings.TrimPrefix(text[i+1:], "+")
+		sign := ""
+		if strings.HasPrefix(exp, "-") {
+			sign, exp = "-", exp[1:]
+		}
+		text = text[:i+1] + sign + strings.TrimLeft(exp, "0")
+	}
+	return text
+}
+
+// varName returns the base name of the variable that holds a ugen.
+func (s *sclangWriter) varName(u *Ugen) string {
+	operator, isOp := lookupOp(u)
+
+	switch {
+	case isOp && u.Name == BinOpUgenName:
+		op := operator.sclang
+		if name, ok := sclangInfix[op]; ok {
+			return name
+		}
+		if isSclangIdentifier(op) {
+			return op
+		}
+	case isOp && u.Name == UnaryOpUgenName:
+		return operator.sclang
+	case u.Name == "MulAdd":
+		return "scaled"
+	case u.Name == "Sum3" || u.Name == "Sum4":
+		return "sum"
+	}
+	return lowerFirst(identifier(u.Name))
+}
+
+// name returns a variable name that has not been used yet.
+// Names that end with a digit are numbered after an underscore,
+// so the second Pan2 is pan2_2 instead of pan22.
+func (s *sclangWriter) name(base string) string {
+	var (
+		name = base
+		sep  string
+	)
+	if last := base[len(base)-1]; last >= '0' && last <= '9' {
+		sep = "_"
+	}
+	for i := 2; s.names[name] || sclangKeywords[name]; i++ {
+		name = base + sep + strconv.Itoa(i)
+	}
+	s.names[name] = true
+	return name
+}
+
+// env returns an Env for the envelope inputs of an EnvGen.
+// The bool is false if the inputs are not an envelope with constant
+// segment shapes.
+func (s *sclangWriter) env(ins []UgenInput, args []sclangExpr) (sclangExpr, bool) {
+	constant := func(i int) (int, bool) {
+		if !ins[i].IsConstant() {
+			return 0, false
+		}
+		v := s.def.Constants[ins[i].OutputIndex]
+		return int(v), v == float32(int(v))
+	}
+	numSegs, ok := constant(1)
+	if !ok || numSegs < 1 || len(ins) != 4+4*numSegs {
+		return sclangExpr{}, false
+	}
+	var (
+		levels = []sclangExpr{args[0]}
+		times  []sclangExpr
+		curves []sclangExpr
+	)
+	for i := 4; i < len(ins); i += 4 {
+		levels = append(levels, args[i])
+		times = append(times, args[i+1])
+
+		shape, ok := constant(i + 2)
+		if !ok || shape < 0 || shape >= len(sclangEnvShapes) {
+			return sclangExpr{}, false
+		}
+		if shape == 5 {
+			curves = append(curves, args[i+3])
+		} else {
+			curves = append(curves, sclangExpr{text: sclangEnvShapes[shape]})
+		}
+	}
+	curve := curves[0]
+	for _, c := range curves[1:] {
+		if c.text != curve.text {
+			curve = sclangExpr{text: "[" + joinExprs(curves) + "]"}
+			break
+		}
+	}
+	envArgs := []sclangExpr{
+		{text: "[" + joinExprs(levels) + "]"},
+		{text: "[" + joinExprs(times) + "]"},
+		curve,
+	}
+	// -99 means the envelope does not have a release node or loop node.
+	for _, node := range []sclangExpr{args[2], args[3]} {
+		if node.text == "-99" {
+			node.text = "nil"
+		}
+		envArgs = append(envArgs, node)
+	}
+	for len(envArgs) > 3 && envArgs[len(envArgs)-1].text == "nil" {
+		envArgs = envArgs[:len(envArgs)-1]
+	}
+	if len(envArgs) == 3 && curve.text == `\lin` {
+		envArgs = envArgs[:2]
+	}
+	return call("Env", "", envArgs), true
+}
+
+// specs returns the specification array of a Klang or Klank,
+// whose inputs are (freq, amp, phase or ring time) triples.
+func specs(triples []sclangExpr) sclangExpr {
+	var columns [3][]sclangExpr
+	for i, arg := range triples {
+		columns[i%3] = append(columns[i%3], arg)
+	}
+	return sclangExpr{text: "`[[" + joinExprs(columns[0]) + "], [" + joinExprs(columns[1]) + "], [" + joinExprs(columns[2]) + "]]"}
+}
+
+// call returns a ugen constructor call, e.g. SinOsc.ar(440, 0).
+// Ugens without a rate selector are created with new, e.g. BinaryOpUGen('==', a, b),
+// and ugens without arguments don't need parentheses, e.g. WhiteNoise.ar.
+func call(name, rate string, args []sclangExpr) sclangExpr {
+	if rate == "" {
+		return sclangExpr{text: name + "(" + joinExprs(args) + ")"}
+	}
+	name += "." + rate
+	if len(args) == 0 {
+		return sclangExpr{text: name}
+	}
+	return sclangExpr{text: name + "(" + joinExprs(args) + ")"}
+}
+
+// method returns a method call, e.g. x.min(y).
+func method(receiver sclangExpr, selector string, args ...sclangExpr) sclangExpr {
+	text := receiver.text
+	if receiver.infix {
+		text = "(" + text + ")"
+	}
+	text += "." + selector
+	if len(args) > 0 {
+		text += "(" + joinExprs(args) + ")"
+	}
+	return sclangExpr{text: text}
+}
+
+// infix returns an infix operation on operands.
+// sclang evaluates binary operators from left to right, so only
+// operands after the first one need parentheses.
+func infix(op string, operands ...sclangExpr) sclangExpr {
+	text := operands[0].text
+	for _, operand := range operands[1:] {
+		if operand.infix {
+			text += " " + op + " (" + operand.text + ")"
+		} else {
+			text += " " + op + " " + operand.text
+		}
+	}
+	return sclangExpr{text: text, infix: true}
+}
+
+// joinExprs joins expressions with commas.
+func joinExprs(exprs []sclangExpr) string {
+	texts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		texts[i] = expr.text
+	}
+	return strings.Join(texts, ", ")
+}
+
+// sclangLiteral returns the literal for param values.
+// Params with more than one value are literal arrays.
+func sclangLiteral(values []float32) string {
+	texts := make([]string, len(values))
+	for i, v := range values {
+		texts[i] = sclangNumber(v)
+	}
+	if len(texts) == 1 {
+		return texts[0]
+	}
+	return "#[" + strings.Join(texts, ", ") + "]"
+}
+
+// sclangSymbol returns the literal for a symbol, e.g. \foo or 'foo bar'.
+func sclangSymbol(name string) string {
+	for _, r := range name {
+		if !isIdentRune(r) {
+			return sclangSymbolLiteral(name)
+		}
+	}
+	if name == "" {
+		return "''"
+	}
+	return `\` + name
+}
+
+// sclangSymbolLiteral returns the quoted literal for a symbol, e.g. 'foo bar'.
+func sclangSymbolLiteral(name string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(name) + "'"
+}
+
+// sclangKey returns an Event key, e.g. foo: or 'foo bar':.
+func sclangKey(name string) string {
+	if isSclangIdentifier(name) {
+		return name
+	}
+	return sclangSymbolLiteral(name)
+}
+
+// isSclangIdentifier returns true if s can be used as an sclang variable name.
+func isSclangIdentifier(s string) bool {
+	if s == "" || s[0] < 'a' || s[0] > 'z' || sclangKeywords[s] {
+		return false
+	}
+	for _, r := range s {
+		if !isIdentRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalFloats returns true if two slices contain the same values.
+func equalFloats(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package sc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// sclangGoldenFile contains the sclang code written for the synthdefs in testdata.
+const sclangGoldenFile = "testdata/decompiled.scd"
+
+// TestWriteSclangFixtures checks the sclang code written for every synthdef in testdata.
+// Run the test with -update to rewrite the golden file.
+func TestWriteSclangFixtures(t *testing.T) {
+	var buf bytes.Buffer
+	for i, fixture := range readFixtures(t) {
+		path, def := fixture.path, fixture.def
+
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if err := def.WriteSclang(&buf); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+	}
+	if *update {
+		if err := ioutil.WriteFile(sclangGoldenFile, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(sclangGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.Bytes(); !bytes.Equal(expected, got) {
+		t.Fatalf("%s is out of date, run go test -run TestWriteSclangFixtures -update", sclangGoldenFile)
+	}
+}
+
+func TestWriteSclang(t *testing.T) {
+	for _, testCase := range []struct {
+		Name     string
+		Def      func() *Synthdef
+		Expected string
+	}{
+		{
+			Name: "params and variants",
+			Def: func() *Synthdef {
+				def := NewSynthdef("sine tone", func(p Params) Ugen {
+					var (
+						freq = p.Add("freq", 440)
+						amp  = p.Add("amp", 0.1)
+						sine = SinOsc{Freq: freq}.Rate(AR)
+					)
+					return Out{Bus: C(0), Channels: Multi(sine.Mul(amp), sine.Mul(amp).Neg())}.Rate(AR)
+				})
+				def.Variants = []*Variant{{Name: "high", InitialParamValues: []float32{880, 0.1}}}
+				return def
+			},
+			Expected: `SynthDef('sine tone', { |freq = 440, amp = 0.1|
+    var sinOsc = SinOsc.ar(freq, 0);
+    Out.ar(0, [sinOsc * amp, (sinOsc * amp).neg]);
+}, variants: (high: [freq: 880]))
+`,
+		},
+		{
+			// sclang evaluates binary operators from left to right.
+			Name: "operators",
+			Def: func() *Synthdef {
+				return NewSynthdef("operators", func(p Params) Ugen {
+					var (
+						a = SinOsc{}.Rate(AR)
+						b = Saw{}.Rate(AR)
+					)
+					return Out{Bus: C(0), Channels: a.Mul(b.Add(C(1))).Add(C(1)).Abs().Max(C(1e-5))}.Rate(AR)
+				})
+			},
+			Expected: `SynthDef(\operators, {
+    Out.ar(0, (SinOsc.ar(440, 0) * (Saw.ar(440) + 1) + 1).abs.max(1e-5));
+})
+`,
+		},
+		{
+			Name: "envelope",
+			Def: func() *Synthdef {
+				return NewSynthdef("envelope", func(p Params) Ugen {
+					env := EnvGen{
+						Env:        EnvPerc{},
+						Done:       FreeEnclosing,
+						LevelScale: p.Add("amp", 0.2),
+					}.Rate(KR)
+					return Out{Bus: C(0), Channels: WhiteNoise{}.Rate(AR).Mul(env)}.Rate(AR)
+				})
+			},
+			Expected: `SynthDef(\envelope, { |amp = 0.2|
+    var product = WhiteNoise.ar * EnvGen.kr(Env([0, 1, 0], [0.01, 1], -4), 1, amp, 0, 1, 2);
+    Out.ar(0, product);
+})
+`,
+		},
+		{
+			// Array params, lags and trigger controls can't be created with Params.
+			Name: "controls",
+			Def: func() *Synthdef {
+				return &Synthdef{
+					Name:               "controls",
+					Constants:          []float32{0.1, 0},
+					InitialParamValues: []float32{200, 300, 1, 0},
+					ParamNames: []ParamName{
+						{Name: "freqs", Index: 0},
+						{Name: "amp", Index: 2},
+						{Name: "t_trig", Index: 3},
+					},
+					Ugens: []*Ugen{
+						{Name: "LagControl", Rate: KR, Inputs: []UgenInput{{-1, 1}, {-1, 1}, {-1, 0}}, Outputs: []Output{KR, KR, KR}},
+						{Name: "TrigControl", Rate: KR, SpecialIndex: 3, Outputs: []Output{KR}},
+						{Name: "Saw", Rate: AR, Inputs: []UgenInput{{0, 0}, {0, 1}}, Outputs: []Output{AR, AR}},
+						{Name: "Decay", Rate: KR, Inputs: []UgenInput{{1, 0}, {-1, 0}}, Outputs: []Output{KR}},
+						{Name: BinOpUgenName, Rate: KR, SpecialIndex: BinOpMul, Inputs: []UgenInput{{0, 2}, {3, 0}}, Outputs: []Output{KR}},
+						{Name: "Out", Rate: AR, Inputs: []UgenInput{{-1, 1}, {2, 1}, {4, 0}}},
+					},
+				}
+			},
+			Expected: `SynthDef(\controls, { |freqs = #[200, 300], amp = 1, t_trig = 0|
+    var saw = Saw.ar(freqs);
+    Out.ar(0, [saw[1], amp * Decay.kr(t_trig, 0.1)]);
+}, rates: [0, 0.1, \tr])
+`,
+		},
+	} {
+		var buf bytes.Buffer
+		if err := testCase.Def().WriteSclang(&buf); err != nil {
+			t.Fatalf("%s: %s", testCase.Name, err)
+		}
+		if expected, got := testCase.Expected, buf.String(); expected != got {
+			t.Fatalf("%s: expected\n%s\ngot\n%s", testCase.Name, expected, got)
+		}
+	}
+}
+
+func TestWriteSclangParamName(t *testing.T) {
+	def := NewSynthdef("ParamName", func(p Params) Ugen {
+		return Out{Bus: p.Add("Out", 0), Channels: SinOsc{}.Rate(AR)}.Rate(AR)
+	})
+	if err := def.WriteSclang(ioutil.Discard); err == nil {
+		t.Fatal("expected an error for a param name that starts with an upper case letter")
+	}
+}
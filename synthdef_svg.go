@@ -0,0 +1,265 @@
+package sc
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Sizes used to lay out svg graphs, in pixels.
+const (
+	svgCharWidth  = 7  // svgCharWidth is the approximate width of a character
+	svgLineHeight = 14 // svgLineHeight is the height of a line of text
+	svgPadding    = 8  // svgPadding is the space around the text in a node
+	svgNodeGap    = 24 // svgNodeGap is the horizontal space between nodes
+	svgLayerGap   = 48 // svgLayerGap is the vertical space between layers
+	svgMargin     = 16 // svgMargin is the space around the graph
+)
+
+// svgSweeps is the number of times the nodes in each layer are reordered
+// to reduce edge crossings.
+const svgSweeps = 4
+
+// WriteSVG draws the synthdef's ugen graph as an svg image.
+// It draws the same graph as WriteDOT, but it doesn't need Graphviz:
+// nodes are arranged in layers with the params and constants at the top
+// and the signal flowing down to the ugens that are not used as inputs.
+// The output only depends on the synthdef.
+func (def *Synthdef) WriteSVG(w io.Writer) error {
+	g, err := def.graph()
+	if err != nil {
+		return err
+	}
+	l := newSVGLayout(g)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", l.width, l.height, l.width, l.height)
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(def.Name))
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z"/></marker></defs>` + "\n")
+
+	for _, e := range g.edges {
+		var (
+			from   = l.boxes[e.from]
+			to     = l.boxes[e.to]
+			x1, y1 = from.x + from.w/2, from.y + from.h
+			x2, y2 = to.x + to.w/2, to.y
+			ym     = (y1 + y2) / 2
+		)
+		fmt.Fprintf(&b, `<path d="M %d %d C %d %d, %d %d, %d %d" fill="none" stroke="black" marker-end="url(#arrow)"/>`+"\n", x1, y1, x1, ym, x2, ym, x2, y2)
+		if e.label != "" {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="10" fill="gray">%s</text>`+"\n", (x1+x2)/2+3, ym, html.EscapeString(e.label))
+		}
+	}
+	for i, n := range g.nodes {
+		box := l.boxes[i]
+		switch n.kind {
+		case ugenNode:
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="white" stroke="black"/>`+"\n", box.x, box.y, box.w, box.h)
+		case paramNode:
+			fmt.Fprintf(&b, `<ellipse cx="%d" cy="%d" rx="%d" ry="%d" fill="white" stroke="black"/>`+"\n", box.x+box.w/2, box.y+box.h/2, box.w/2, box.h/2)
+		}
+		for j, line := range n.label {
+			y := box.y + svgPadding + (j+1)*svgLineHeight - 3
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", box.x+box.w/2, y, html.EscapeString(line))
+		}
+	}
+	b.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// svgBox is the position and size of a node.
+type svgBox struct {
+	x, y, w, h int
+}
+
+// svgLayout places the nodes of a ugen graph.
+type svgLayout struct {
+	g      *ugenGraph
+	layers [][]int   // layers are the node indices in each layer, from top to bottom
+	layer  []int     // layer is the layer of each node
+	preds  [][]int   // preds are the nodes that each node reads from
+	succs  [][]int   // succs are the nodes that read from each node
+	boxes  []svgBox  // boxes are the positions of the nodes
+	order  []float64 // order is the position of each node in its layer
+	width  int
+	height int
+}
+
+// newSVGLayout lays out a ugen graph.
+func newSVGLayout(g *ugenGraph) *svgLayout {
+	l := &svgLayout{
+		g:     g,
+		layer: make([]int, len(g.nodes)),
+		preds: make([][]int, len(g.nodes)),
+		succs: make([][]int, len(g.nodes)),
+		boxes: make([]svgBox, len(g.nodes)),
+		order: make([]float64, len(g.nodes)),
+	}
+	for _, e := range g.edges {
+		l.preds[e.to] = append(l.preds[e.to], e.from)
+		l.succs[e.from] = append(l.succs[e.from], e.to)
+	}
+	l.assignLayers()
+	l.orderLayers()
+	l.place()
+	return l
+}
+
+// assignLayers puts every node in a layer below the nodes it reads from.
+// Constants go right above the ugen that reads them.
+func (l *svgLayout) assignLayers() {
+	// Nodes come after the nodes they depend on, so one pass is enough.
+	for i, n := range l.g.nodes {
+		if n.kind != ugenNode {
+			continue
+		}
+		for _, p := range l.preds[i] {
+			if l.g.nodes[p].kind == constantNode {
+				continue
+			}
+			if l.layer[p]+1 > l.layer[i] {
+				l.layer[i] = l.layer[p] + 1
+			}
+		}
+		if l.layer[i] == 0 && len(l.preds[i]) > 0 {
+			l.layer[i] = 1
+		}
+		for _, p := range l.preds[i] {
+			if l.g.nodes[p].kind == constantNode {
+				l.layer[p] = l.layer[i] - 1
+			}
+		}
+	}
+	for i, layer := range l.layer {
+		for len(l.layers) <= layer {
+			l.layers = append(l.layers, nil)
+		}
+		l.layers[layer] = append(l.layers[layer], i)
+		l.order[i] = float64(len(l.layers[layer]) - 1)
+	}
+}
+
+// orderLayers reorders the nodes in each layer to reduce edge crossings,
+// by sorting them by the average position of their neighbors.
+func (l *svgLayout) orderLayers() {
+	for sweep := 0; sweep < svgSweeps; sweep++ {
+		for i := 1; i < len(l.layers); i++ {
+			l.sortLayer(l.layers[i], l.preds)
+		}
+		for i := len(l.layers) - 2; i >= 0; i-- {
+			l.sortLayer(l.layers[i], l.succs)
+		}
+	}
+}
+
+// sortLayer sorts the nodes in a layer by the average position of their neighbors.
+// Nodes without neighbors keep their position.
+func (l *svgLayout) sortLayer(layer []int, neighbors [][]int) {
+	center := make(map[int]float64, len(layer))
+	for _, n := range layer {
+		center[n] = l.order[n]
+		if len(neighbors[n]) == 0 {
+			continue
+		}
+		var sum float64
+		for _, m := range neighbors[n] {
+			sum += l.order[m]
+		}
+		center[n] = sum / float64(len(neighbors[n]))
+	}
+	sort.SliceStable(layer, func(i, j int) bool { return center[layer[i]] < center[layer[j]] })
+
+	for i, n := range layer {
+		l.order[n] = float64(i)
+	}
+}
+
+// place sets the positions of the nodes.
+// Each node is placed below the nodes it reads from if there is room,
+// otherwise it is pushed to the right of its left neighbor.
+func (l *svgLayout) place() {
+	for i, n := range l.g.nodes {
+		longest := 0
+		for _, line := range n.label {
+			if len(line) > longest {
+				longest = len(line)
+			}
+		}
+		l.boxes[i].w = longest*svgCharWidth + 2*svgPadding
+		l.boxes[i].h = len(n.label)*svgLineHeight + svgPadding
+	}
+	y := svgMargin
+	for _, layer := range l.layers {
+		var (
+			right  = svgMargin - svgNodeGap
+			height = 0
+		)
+		for _, n := range layer {
+			box := &l.boxes[n]
+			box.x = right + svgNodeGap
+
+			if len(l.preds[n]) > 0 {
+				var sum int
+				for _, p := range l.preds[n] {
+					sum += l.boxes[p].x + l.boxes[p].w/2
+				}
+				if x := sum/len(l.preds[n]) - box.w/2; x > box.x {
+					box.x = x
+				}
+			}
+			box.y = y
+			right = box.x + box.w
+
+			if box.h > height {
+				height = box.h
+			}
+			if right+svgMargin > l.width {
+				l.width = right + svgMargin
+			}
+		}
+		// Center nodes vertically in their layer.
+		for _, n := range layer {
+			l.boxes[n].y += (height - l.boxes[n].h) / 2
+		}
+		y += height + svgLayerGap
+	}
+	l.height = y - svgLayerGap + svgMargin
+
+	// Move leaves, which were packed to the left, above the nodes that read them.
+	for i := len(l.layers) - 2; i >= 0; i-- {
+		right := svgMargin - svgNodeGap
+
+		for _, n := range l.layers[i] {
+			box := &l.boxes[n]
+			if box.x < right+svgNodeGap {
+				box.x = right + svgNodeGap
+			}
+			if len(l.preds[n]) == 0 && len(l.succs[n]) > 0 {
+				var sum int
+				for _, s := range l.succs[n] {
+					sum += l.boxes[s].x + l.boxes[s].w/2
+				}
+				if x := sum/len(l.succs[n]) - box.w/2; x > box.x {
+					box.x = x
+				}
+			}
+			right = box.x + box.w
+
+			if right+svgMargin > l.width {
+				l.width = right + svgMargin
+			}
+		}
+	}
+	// A graph without nodes is just the margins.
+	if l.width < 2*svgMargin {
+		l.width = 2 * svgMargin
+	}
+	if l.height < 2*svgMargin {
+		l.height = 2 * svgMargin
+	}
+}
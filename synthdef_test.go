@@ -1,11 +1,43 @@
 package sc
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// fixture is a synthdef in testdata.
+type fixture struct {
+	path string
+	data []byte
+	def  *Synthdef
+}
+
+// readFixtures reads every synthdef in testdata.
+func readFixtures(t *testing.T) []fixture {
+	paths, err := filepath.Glob("testdata/*.scsyndef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures := make([]fixture, len(paths))
+	for i, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		def, err := ReadSynthdef(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		fixtures[i] = fixture{path: path, data: data, def: def}
+	}
+	return fixtures
+}
+
 func TestReadSynthdef(t *testing.T) {
 	// read a synthdef file created by sclang
 	f, err := os.Open("testdata/SineTone.scsyndef")
@@ -22,6 +54,83 @@ func TestReadSynthdef(t *testing.T) {
 	}
 }
 
+// TestReadSynthdefText checks that every synthdef in testdata survives
+// a trip through json and xml.
+func TestReadSynthdefText(t *testing.T) {
+	for _, fixture := range readFixtures(t) {
+		path, def := fixture.path, fixture.def
+
+		var bin, text bytes.Buffer
+		if err := def.Write(&bin); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if err := def.WriteJSON(&text); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		fromJSON, err := ReadSynthdefJSON(&text)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		text.Reset()
+		if err := fromJSON.WriteXML(&text); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		fromXML, err := ReadSynthdefXML(&text)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		got, err := fromXML.Bytes()
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if expected := bin.Bytes(); !bytes.Equal(expected, got) {
+			t.Fatalf("%s: expected the same synthdef after reading json and xml", path)
+		}
+	}
+}
+
+func TestReadSynthdefTextErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		Name string
+		JSON string
+		XML  string
+	}{
+		{
+			Name: "missing schema",
+			JSON: `{"name":"a"}`,
+			XML:  `<Synthdef Name="a"></Synthdef>`,
+		},
+		{
+			Name: "future schema",
+			JSON: `{"schema":100,"name":"a"}`,
+			XML:  `<Synthdef schema="100" Name="a"></Synthdef>`,
+		},
+		{
+			Name: "variant",
+			JSON: `{"schema":1,"name":"a","initialParamValues":[1,2],"variants":[{"name":"b","initialParamValues":[1]}]}`,
+			XML:  `<Synthdef schema="1" Name="a"><InitialParamValues><initialParamValue>1</initialParamValue><initialParamValue>2</initialParamValue></InitialParamValues><Variants><Variant name="b"><InitialParamValues><initialParamValue>1</initialParamValue></InitialParamValues></Variant></Variants></Synthdef>`,
+		},
+		{
+			Name: "constant",
+			JSON: `{"schema":1,"name":"a","ugens":[{"name":"Out","rate":2,"inputs":[{"ugenIndex":-1,"outputIndex":0}]}]}`,
+			XML:  `<Synthdef schema="1" Name="a"><Ugens><Ugen name="Out" rate="2"><Inputs><Input ugenIndex="-1" outputIndex="0"></Input></Inputs></Ugen></Ugens></Synthdef>`,
+		},
+		{
+			Name: "root element",
+			XML:  `<Synthdefs schema="1" Name="a"></Synthdefs>`,
+		},
+	} {
+		if testCase.JSON != "" {
+			if _, err := ReadSynthdefJSON(strings.NewReader(testCase.JSON)); err == nil {
+				t.Fatalf("%s: expected an error reading json", testCase.Name)
+			}
+		}
+		if _, err := ReadSynthdefXML(strings.NewReader(testCase.XML)); err == nil {
+			t.Fatalf("%s: expected an error reading xml", testCase.Name)
+		}
+	}
+}
+
 func ExampleNewSynthdef() {
 	_ = NewSynthdef("SineTone", func(p Params) Ugen {
 		var (
@@ -31,5 +140,64 @@ func ExampleNewSynthdef() {
 		return Out{Bus: bus, Channels: sig}.Rate(AR)
 	}).WriteJSON(os.Stdout)
 	// Output:
-	// {"name":"SineTone","constants":[440,0],"ugens":[{"name":"SinOsc","rate":2,"specialIndex":0,"inputs":[{"ugenIndex":-1,"outputIndex":0},{"ugenIndex":-1,"outputIndex":1}],"outputs":[2]},{"name":"Out","rate":2,"specialIndex":0,"inputs":[{"ugenIndex":-1,"outputIndex":1},{"ugenIndex":0,"outputIndex":0}]}]}
+	// {"schema":1,"name":"SineTone","constants":[440,0],"ugens":[{"name":"SinOsc","rate":2,"specialIndex":0,"inputs":[{"ugenIndex":-1,"outputIndex":0},{"ugenIndex":-1,"outputIndex":1}],"outputs":[2],"numOutputs":1},{"name":"Out","rate":2,"specialIndex":0,"inputs":[{"ugenIndex":-1,"outputIndex":1},{"ugenIndex":0,"outputIndex":0}],"numOutputs":1}]}
+}
+
+func TestSynthdefUgenIndex(t *testing.T) {
+	def := NewSynthdef("UgenIndexTest", func(p Params) Ugen {
+		var (
+			lfo  = SinOsc{Freq: C(2)}.Rate(KR)
+			sine = SinOsc{Freq: lfo.MulAdd(C(100), C(440))}.Rate(AR)
+		)
+		return Out{Bus: C(0), Channels: sine}.Rate(AR)
+	})
+	for _, testcase := range []struct {
+		name       string
+		occurrence int
+		expected   int32
+	}{
+		{"SinOsc", 0, 0},
+		{"MulAdd", 0, 1},
+		{"SinOsc", 1, 2},
+		{"Out", 0, 3},
+	} {
+		idx, err := def.UgenIndex(testcase.name, testcase.occurrence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if idx != testcase.expected {
+			t.Fatalf("expected %s(%d) to have index %d, got %d", testcase.name, testcase.occurrence, testcase.expected, idx)
+		}
+	}
+	if _, err := def.UgenIndex("SinOsc", 2); err == nil {
+		t.Fatal("expected error for missing ugen occurrence")
+	}
+}
+
+func TestSynthdefDiff(t *testing.T) {
+	sine := func(bus, freq float32) UgenFunc {
+		return func(p Params) Ugen {
+			return Out{Bus: C(bus), Channels: SinOsc{Freq: C(freq)}.Rate(AR)}.Rate(AR)
+		}
+	}
+	def := NewSynthdef("DiffTest", sine(1, 440))
+
+	// NumOutputs is not written to synthdef files,
+	// so a synthdef that is read back is not different.
+	data, err := def.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSynthdef(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := def.Diff(read); len(diff) > 0 {
+		t.Fatalf("expected no differences after reading the synthdef, got %v", diff)
+	}
+	// Every difference is reported once.
+	diff := def.Diff(NewSynthdef("DiffTest", sine(2, 220)))
+	if expected, got := 2, len(diff); expected != got {
+		t.Fatalf("expected %d differences, got %d: %v", expected, got, diff)
+	}
 }
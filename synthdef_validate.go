@@ -0,0 +1,292 @@
+package sc
+
+import (
+	"fmt"
+)
+
+// ValidationKind is the kind of problem a ValidationIssue describes.
+type ValidationKind int
+
+// Validation issue kinds.
+const (
+	// InvalidRate means a ugen reads an input at a rate it can't use,
+	// e.g. an audio rate filter whose input is a control rate signal.
+	InvalidRate ValidationKind = iota
+
+	// InvalidIndex means something refers to a ugen, ugen output,
+	// constant, or param value that doesn't exist.
+	InvalidIndex
+
+	// InvalidOrder means a ugen reads from itself or from a ugen that comes
+	// after it, so the ugens are not topologically sorted.
+	InvalidOrder
+
+	// DuplicateParam means two params have the same name.
+	DuplicateParam
+
+	// MissingControl means the synthdef has params but no Control ugen to read them from.
+	MissingControl
+)
+
+// String returns a description of the kind of issue.
+func (k ValidationKind) String() string {
+	switch k {
+	case InvalidRate:
+		return "invalid rate"
+	case InvalidIndex:
+		return "invalid index"
+	case InvalidOrder:
+		return "invalid order"
+	case DuplicateParam:
+		return "duplicate param"
+	case MissingControl:
+		return "missing control"
+	}
+	return fmt.Sprintf("ValidationKind(%d)", int(k))
+}
+
+// ValidationIssue is a problem found by Synthdef.Validate.
+type ValidationIssue struct {
+	Kind ValidationKind
+
+	// Ugen is the index of the ugen the issue is about,
+	// or -1 if the issue is not about a particular ugen.
+	Ugen int
+
+	// Input is the index of the ugen input the issue is about,
+	// or -1 if the issue is not about a particular input.
+	Input int
+
+	// Message describes the issue.
+	Message string
+}
+
+// Error returns a description of the issue.
+func (vi ValidationIssue) Error() string {
+	switch {
+	case vi.Ugen >= 0 && vi.Input >= 0:
+		return fmt.Sprintf("%s: ugen %d input %d: %s", vi.Kind, vi.Ugen, vi.Input, vi.Message)
+	case vi.Ugen >= 0:
+		return fmt.Sprintf("%s: ugen %d: %s", vi.Kind, vi.Ugen, vi.Message)
+	}
+	return fmt.Sprintf("%s: %s", vi.Kind, vi.Message)
+}
+
+// signalInput is a range of ugen inputs that carry the signal the ugen processes.
+// If last is -1 the range includes all the inputs after first.
+type signalInput struct {
+	first, last int
+}
+
+// signalInputs are the signal inputs of ugens that must run at the rate of the ugen.
+// Audio rate ugens need audio rate signals and control rate ugens can't use
+// audio rate signals (see checkSameRateAsFirstInput and Out.checkInputs in sclang).
+var signalInputs = map[string]signalInput{
+	"AllpassC":   {0, 0},
+	"AllpassL":   {0, 0},
+	"AllpassN":   {0, 0},
+	"BAllPass":   {0, 0},
+	"BLowPass":   {0, 0},
+	"BPF":        {0, 0},
+	"BRF":        {0, 0},
+	"CombC":      {0, 0},
+	"CombL":      {0, 0},
+	"CombN":      {0, 0},
+	"Decay":      {0, 0},
+	"Decay2":     {0, 0},
+	"DelayC":     {0, 0},
+	"DelayL":     {0, 0},
+	"DelayN":     {0, 0},
+	"DiskOut":    {1, -1},
+	"Formlet":    {0, 0},
+	"FreeVerb":   {0, 0},
+	"HPF":        {0, 0},
+	"Integrator": {0, 0},
+	"LPF":        {0, 0},
+	"Lag":        {0, 0},
+	"Lag2":       {0, 0},
+	"Lag3":       {0, 0},
+	"LeakDC":     {0, 0},
+	"LocalOut":   {0, -1},
+	"OffsetOut":  {1, -1},
+	"OnePole":    {0, 0},
+	"OneZero":    {0, 0},
+	"Out":        {1, -1},
+	"RHPF":       {0, 0},
+	"RLPF":       {0, 0},
+	"ReplaceOut": {1, -1},
+	"Resonz":     {0, 0},
+	"Ringz":      {0, 0},
+	"Slew":       {0, 0},
+	"Slope":      {0, 0},
+	"XOut":       {2, -1},
+}
+
+// controlUgens are the ugens that read synthdef params.
+var controlUgens = map[string]struct{}{
+	"AudioControl": {},
+	"Control":      {},
+	"LagControl":   {},
+	"TrigControl":  {},
+}
+
+// Validate checks a synthdef for problems that would make scsynth
+// reject it, crash, or produce something other than what was intended.
+// It works the same for synthdefs created with NewSynthdef and
+// synthdefs read from disk.
+// It returns nil if no problems are found.
+func (def *Synthdef) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+
+	issues = append(issues, def.validateParams()...)
+
+	for i, u := range def.Ugens {
+		issues = append(issues, def.validateUgen(i, u)...)
+	}
+	return issues
+}
+
+// validateGraph returns the first issue that makes it impossible to walk
+// the ugen graph, i.e. an invalid index or an invalid order.
+// It returns nil if the graph can be walked.
+func (def *Synthdef) validateGraph() error {
+	for _, issue := range def.Validate() {
+		if issue.Kind == InvalidIndex || issue.Kind == InvalidOrder {
+			return issue
+		}
+	}
+	return nil
+}
+
+// validateParams checks the synthdef's params.
+func (def *Synthdef) validateParams() []ValidationIssue {
+	var (
+		issues []ValidationIssue
+		names  = map[string]bool{}
+	)
+	for _, pn := range def.ParamNames {
+		if names[pn.Name] {
+			issues = append(issues, ValidationIssue{
+				Kind:    DuplicateParam,
+				Ugen:    -1,
+				Input:   -1,
+				Message: fmt.Sprintf("param %s is defined more than once", pn.Name),
+			})
+		}
+		names[pn.Name] = true
+
+		if pn.Index < 0 || int(pn.Index) >= len(def.InitialParamValues) {
+			issues = append(issues, ValidationIssue{
+				Kind:    InvalidIndex,
+				Ugen:    -1,
+				Input:   -1,
+				Message: fmt.Sprintf("param %s has index %d, but there are %d param values", pn.Name, pn.Index, len(def.InitialParamValues)),
+			})
+		}
+	}
+	if len(def.InitialParamValues) == 0 {
+		return issues
+	}
+	for _, u := range def.Ugens {
+		if _, ok := controlUgens[u.Name]; ok {
+			return issues
+		}
+	}
+	return append(issues, ValidationIssue{
+		Kind:    MissingControl,
+		Ugen:    -1,
+		Input:   -1,
+		Message: fmt.Sprintf("there are %d params but no control ugen", len(def.InitialParamValues)),
+	})
+}
+
+// validateUgen checks the inputs of the ugen at index i.
+func (def *Synthdef) validateUgen(i int, u *Ugen) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for j, in := range u.Inputs {
+		issue := ValidationIssue{Ugen: i, Input: j}
+
+		if in.IsConstant() {
+			if in.OutputIndex < 0 || int(in.OutputIndex) >= len(def.Constants) {
+				issue.Kind = InvalidIndex
+				issue.Message = fmt.Sprintf("constant %d does not exist (there are %d constants)", in.OutputIndex, len(def.Constants))
+				issues = append(issues, issue)
+			}
+			continue
+		}
+		if in.UgenIndex < 0 || int(in.UgenIndex) >= len(def.Ugens) {
+			issue.Kind = InvalidIndex
+			issue.Message = fmt.Sprintf("ugen %d does not exist (there are %d ugens)", in.UgenIndex, len(def.Ugens))
+			issues = append(issues, issue)
+			continue
+		}
+		if int(in.UgenIndex) >= i {
+			issue.Kind = InvalidOrder
+			issue.Message = fmt.Sprintf("%s reads from ugen %d (%s) which comes after it", u.Name, in.UgenIndex, def.Ugens[in.UgenIndex].Name)
+			issues = append(issues, issue)
+		}
+		if from := def.Ugens[in.UgenIndex]; in.OutputIndex < 0 || int(in.OutputIndex) >= len(from.Outputs) {
+			issue.Kind = InvalidIndex
+			issue.Message = fmt.Sprintf("ugen %d (%s) does not have output %d (it has %d outputs)", in.UgenIndex, from.Name, in.OutputIndex, len(from.Outputs))
+			issues = append(issues, issue)
+		}
+	}
+	return append(issues, def.validateRates(i, u)...)
+}
+
+// validateRates checks the rates of the inputs of the ugen at index i.
+// Inputs with invalid indices are ignored.
+func (def *Synthdef) validateRates(i int, u *Ugen) []ValidationIssue {
+	var (
+		issues  []ValidationIssue
+		sig, ok = signalInputs[u.Name]
+	)
+	for j, in := range u.Inputs {
+		rate, valid := def.ugenInputRate(in)
+		if !valid {
+			continue
+		}
+		switch {
+		case u.Rate == IR && rate == AR:
+			issues = append(issues, ValidationIssue{
+				Kind:    InvalidRate,
+				Ugen:    i,
+				Input:   j,
+				Message: fmt.Sprintf("%s runs at initialization rate but its input is audio rate", u.Name),
+			})
+		case !ok || j < sig.first || (sig.last >= 0 && j > sig.last):
+		case u.Rate == AR && rate != AR:
+			issues = append(issues, ValidationIssue{
+				Kind:    InvalidRate,
+				Ugen:    i,
+				Input:   j,
+				Message: fmt.Sprintf("%s runs at audio rate but its input is not audio rate", u.Name),
+			})
+		case u.Rate == KR && rate == AR:
+			issues = append(issues, ValidationIssue{
+				Kind:    InvalidRate,
+				Ugen:    i,
+				Input:   j,
+				Message: fmt.Sprintf("%s runs at control rate but its input is audio rate", u.Name),
+			})
+		}
+	}
+	return issues
+}
+
+// ugenInputRate returns the rate of a ugen input and
+// whether the input refers to something that exists.
+func (def *Synthdef) ugenInputRate(in UgenInput) (int8, bool) {
+	if in.IsConstant() {
+		return IR, in.OutputIndex >= 0 && int(in.OutputIndex) < len(def.Constants)
+	}
+	if in.UgenIndex < 0 || int(in.UgenIndex) >= len(def.Ugens) {
+		return 0, false
+	}
+	from := def.Ugens[in.UgenIndex]
+	if in.OutputIndex < 0 || int(in.OutputIndex) >= len(from.Outputs) {
+		return 0, false
+	}
+	return int8(from.Outputs[in.OutputIndex]), true
+}
@@ -0,0 +1,141 @@
+package sc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValidateFixtures(t *testing.T) {
+	for _, fixture := range readFixtures(t) {
+		path, def := fixture.path, fixture.def
+
+		if issues := def.Validate(); len(issues) > 0 {
+			t.Fatalf("%s: expected no issues, got %v", path, issues)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	newDef := func() *Synthdef {
+		return NewSynthdef("ValidateTest", func(p Params) Ugen {
+			var (
+				freq = p.Add("freq", 440)
+				amp  = p.Add("amp", 0.1)
+			)
+			return Out{C(0), SinOsc{Freq: freq}.Rate(AR).Mul(amp)}.Rate(AR)
+		})
+	}
+	if issues := newDef().Validate(); len(issues) > 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+	for _, testCase := range []struct {
+		Name     string
+		Def      func() *Synthdef
+		Expected ValidationIssue
+	}{
+		{
+			Name: "audio rate filter with control rate input",
+			Def: func() *Synthdef {
+				return NewSynthdef("ValidateTest", func(p Params) Ugen {
+					return Out{C(0), LPF{In: SinOsc{}.Rate(KR)}.Rate(AR)}.Rate(AR)
+				})
+			},
+			Expected: ValidationIssue{Kind: InvalidRate, Ugen: 1, Input: 0},
+		},
+		{
+			Name: "control rate output with audio rate input",
+			Def: func() *Synthdef {
+				return NewSynthdef("ValidateTest", func(p Params) Ugen {
+					return Out{C(0), SinOsc{}.Rate(AR)}.Rate(KR)
+				})
+			},
+			Expected: ValidationIssue{Kind: InvalidRate, Ugen: 1, Input: 1},
+		},
+		{
+			Name: "constant index",
+			Def: func() *Synthdef {
+				def := newDef()
+				def.Ugens[1].Inputs[1] = UgenInput{UgenIndex: -1, OutputIndex: 7}
+				return def
+			},
+			Expected: ValidationIssue{Kind: InvalidIndex, Ugen: 1, Input: 1},
+		},
+		{
+			Name: "ugen index",
+			Def: func() *Synthdef {
+				def := newDef()
+				def.Ugens[2].Inputs[0] = UgenInput{UgenIndex: 9, OutputIndex: 0}
+				return def
+			},
+			Expected: ValidationIssue{Kind: InvalidIndex, Ugen: 2, Input: 0},
+		},
+		{
+			Name: "output index",
+			Def: func() *Synthdef {
+				def := newDef()
+				def.Ugens[1].Inputs[0] = UgenInput{UgenIndex: 0, OutputIndex: 2}
+				return def
+			},
+			Expected: ValidationIssue{Kind: InvalidIndex, Ugen: 1, Input: 0},
+		},
+		{
+			Name: "order",
+			Def: func() *Synthdef {
+				def := newDef()
+				def.Ugens[2].Inputs[0] = UgenInput{UgenIndex: 2, OutputIndex: 0}
+				return def
+			},
+			Expected: ValidationIssue{Kind: InvalidOrder, Ugen: 2, Input: 0},
+		},
+		{
+			Name: "duplicate param",
+			Def: func() *Synthdef {
+				def := newDef()
+				def.ParamNames[1].Name = def.ParamNames[0].Name
+				return def
+			},
+			Expected: ValidationIssue{Kind: DuplicateParam, Ugen: -1, Input: -1},
+		},
+		{
+			Name: "missing control",
+			Def: func() *Synthdef {
+				def := newDef()
+				def.Ugens[0].Name = "SinOsc"
+				return def
+			},
+			Expected: ValidationIssue{Kind: MissingControl, Ugen: -1, Input: -1},
+		},
+	} {
+		issues := testCase.Def().Validate()
+		if expected, got := 1, len(issues); expected != got {
+			t.Fatalf("%s: expected %d issue, got %v", testCase.Name, expected, issues)
+		}
+		got := issues[0]
+		got.Message = ""
+		if expected := testCase.Expected; expected != got {
+			t.Fatalf("%s: expected %#v, got %#v", testCase.Name, expected, got)
+		}
+	}
+}
+
+func TestValidateRead(t *testing.T) {
+	def := NewSynthdef("ValidateRead", func(p Params) Ugen {
+		return Out{C(0), LPF{In: SinOsc{}.Rate(KR)}.Rate(AR)}.Rate(AR)
+	})
+	data, err := def.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSynthdef(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issues := range [][]ValidationIssue{def.Validate(), read.Validate()} {
+		if expected, got := 1, len(issues); expected != got {
+			t.Fatalf("expected %d issue, got %v", expected, issues)
+		}
+		if expected, got := InvalidRate, issues[0].Kind; expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
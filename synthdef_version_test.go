@@ -0,0 +1,85 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestSynthdefVersionRoundTrip(t *testing.T) {
+	for _, fixture := range readFixtures(t) {
+		path, fromDisk := fixture.path, fixture.data
+
+		f, err := ReadSynthdefFile(bytes.NewReader(fromDisk))
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		v2, err := f.Bytes()
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if !bytes.Equal(fromDisk, v2) {
+			t.Fatalf("%s: version 2 round trip is not byte-identical", path)
+		}
+		f.Version = SynthdefVersion1
+		v1, err := f.Bytes()
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if expected, got := SynthdefVersion1, int32(binary.BigEndian.Uint32(v1[4:8])); expected != got {
+			t.Fatalf("%s: expected version %d, got %d", path, expected, got)
+		}
+		// Reading the version 1 file upgrades it to version 2.
+		upgraded, err := ReadSynthdefFile(bytes.NewReader(v1))
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		v2, err = upgraded.Bytes()
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if !bytes.Equal(fromDisk, v2) {
+			t.Fatalf("%s: version 1 round trip is not byte-identical", path)
+		}
+	}
+}
+
+func TestReadSynthdefVersion1(t *testing.T) {
+	v1 := &bytes.Buffer{}
+	for _, val := range []interface{}{
+		[]byte("SCgf"),
+		int32(1), // version
+		int16(1), // number of synthdefs
+		int8(8), []byte("SineTone"),
+		int16(2), float32(440), float32(0), // constants
+		int16(0), // params
+		int16(0), // param names
+		int16(2), // ugens
+		int8(6), []byte("SinOsc"), int8(2), int16(2), int16(1), int16(0),
+		int16(-1), int16(0), int16(-1), int16(1), // inputs
+		int8(2), // outputs
+		int8(3), []byte("Out"), int8(2), int16(2), int16(0), int16(0),
+		int16(-1), int16(1), int16(0), int16(0), // inputs
+		int16(0), // variants
+	} {
+		_ = binary.Write(v1, byteOrder, val)
+	}
+	def, err := ReadSynthdef(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewSynthdef("SineTone", func(p Params) Ugen {
+		return Out{Bus: C(0), Channels: SinOsc{}.Rate(AR)}.Rate(AR)
+	})
+	same, err := expected.CompareToDef(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same {
+		t.Fatal("expected the version 1 synthdef to be the same as the version 2 synthdef")
+	}
+	buf := &bytes.Buffer{}
+	if err := def.WriteVersion(buf, 3); err == nil {
+		t.Fatal("expected an error for version 3")
+	}
+}
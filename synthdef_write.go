@@ -7,33 +7,51 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"math"
 )
 
 // Write writes a binary representation of a synthdef to an io.Writer.
 // The binary representation written by this method is
 // the data that scsynth expects at its /d_recv endpoint.
 func (def *Synthdef) Write(w io.Writer) error {
-	if err := def.writeHead(w); err != nil {
+	return def.WriteVersion(w, synthdefVersion)
+}
+
+// WriteVersion writes a binary representation of a synthdef
+// using a particular version of the synthdef file format
+// (SynthdefVersion1 or SynthdefVersion2).
+func (def *Synthdef) WriteVersion(w io.Writer, version int32) error {
+	if err := checkSynthdefVersion(version); err != nil {
+		return err
+	}
+	if err := writeSynthdefHead(w, version, 1); err != nil {
 		return err
 	}
+	return def.writeBody(w, version)
+}
+
+// writeBody writes everything from the synthdef name to the
+// end of the variants to an io.Writer.
+// This is the part of a synthdef file that is repeated for every synthdef.
+func (def *Synthdef) writeBody(w io.Writer, version int32) error {
 	// write synthdef name
 	name := newPstring(def.Name)
 	if err := name.Write(w); err != nil {
 		return err
 	}
-	if err := def.writeConstants(w); err != nil {
+	if err := def.writeConstants(w, version); err != nil {
 		return err
 	}
-	if err := def.writeParams(w); err != nil {
+	if err := def.writeParams(w, version); err != nil {
 		return err
 	}
 	// write number of ugens
-	if err := binary.Write(w, byteOrder, int32(len(def.Ugens))); err != nil {
+	if err := writeSynthdefInt(w, version, int32(len(def.Ugens))); err != nil {
 		return err
 	}
 	// write ugens
 	for _, u := range def.Ugens {
-		if err := u.Write(w); err != nil {
+		if err := u.write(w, version); err != nil {
 			return err
 		}
 	}
@@ -50,9 +68,9 @@ func (def *Synthdef) Write(w io.Writer) error {
 	return nil
 }
 
-// writeHead writes everything leading up to the synthdef name
-// to an io.Writer.
-func (def *Synthdef) writeHead(w io.Writer) error {
+// writeSynthdefHead writes everything leading up to the
+// first synthdef name to an io.Writer.
+func writeSynthdefHead(w io.Writer, version int32, numDefs int) error {
 	written, err := w.Write(bytes.NewBufferString(synthdefStart).Bytes())
 	if err != nil {
 		return err
@@ -61,11 +79,11 @@ func (def *Synthdef) writeHead(w io.Writer) error {
 		return fmt.Errorf("Could not write synthdef")
 	}
 	// write synthdef version
-	if err := binary.Write(w, byteOrder, int32(synthdefVersion)); err != nil {
+	if err := binary.Write(w, byteOrder, version); err != nil {
 		return err
 	}
 	// write number of synthdefs
-	if err := binary.Write(w, byteOrder, int16(1)); err != nil {
+	if err := binary.Write(w, byteOrder, int16(numDefs)); err != nil {
 		return err
 	}
 	return nil
@@ -73,9 +91,9 @@ func (def *Synthdef) writeHead(w io.Writer) error {
 
 // writeConstants writes the number of constants and the values
 // to an io.Writer.
-func (def *Synthdef) writeConstants(w io.Writer) error {
+func (def *Synthdef) writeConstants(w io.Writer, version int32) error {
 	// write number of constants
-	if err := binary.Write(w, byteOrder, int32(len(def.Constants))); err != nil {
+	if err := writeSynthdefInt(w, version, int32(len(def.Constants))); err != nil {
 		return err
 	}
 	// write constant values
@@ -90,9 +108,9 @@ func (def *Synthdef) writeConstants(w io.Writer) error {
 // writeParams writes the number of synthdef params,
 // the initial param values, and the param names
 // to an io.Writer.
-func (def *Synthdef) writeParams(w io.Writer) error {
+func (def *Synthdef) writeParams(w io.Writer, version int32) error {
 	// write number of params
-	if err := binary.Write(w, byteOrder, int32(len(def.ParamNames))); err != nil {
+	if err := writeSynthdefInt(w, version, int32(len(def.ParamNames))); err != nil {
 		return err
 	}
 	// write initial param values
@@ -102,28 +120,51 @@ func (def *Synthdef) writeParams(w io.Writer) error {
 		}
 	}
 	// write number of param names
-	if err := binary.Write(w, byteOrder, int32(len(def.ParamNames))); err != nil {
+	if err := writeSynthdefInt(w, version, int32(len(def.ParamNames))); err != nil {
 		return err
 	}
 	// write param names
 	for _, p := range def.ParamNames {
-		if err := p.Write(w); err != nil {
+		if err := p.write(w, version); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// synthdefDoc is the json and xml representation of a synthdef.
+// It adds the schema version to the synthdef's fields.
+type synthdefDoc struct {
+	XMLName xml.Name `json:"-" xml:"Synthdef"`
+	Schema  int      `json:"schema" xml:"schema,attr"`
+
+	*Synthdef
+}
+
 // WriteJSON writes a json-formatted representation of a
 // synthdef to an io.Writer.
+// Use ReadSynthdefJSON to read it back.
 func (def *Synthdef) WriteJSON(w io.Writer) error {
 	enc := json.NewEncoder(w)
-	return enc.Encode(def)
+	return enc.Encode(synthdefDoc{Schema: SynthdefSchema, Synthdef: def})
 }
 
 // WriteXML writes an xml-formatted representation of a synthdef
 // to an io.Writer.
+// Use ReadSynthdefXML to read it back.
 func (def *Synthdef) WriteXML(w io.Writer) error {
 	enc := xml.NewEncoder(w)
-	return enc.Encode(def)
+	return enc.Encode(synthdefDoc{Schema: SynthdefSchema, Synthdef: def})
+}
+
+// writeSynthdefInt writes a count or an index to a synthdef file.
+// They are int16 in version 1 files and int32 in version 2 files.
+func writeSynthdefInt(w io.Writer, version, val int32) error {
+	if version == SynthdefVersion1 {
+		if val < math.MinInt16 || val > math.MaxInt16 {
+			return fmt.Errorf("%d does not fit in a version 1 synthdef", val)
+		}
+		return binary.Write(w, byteOrder, int16(val))
+	}
+	return binary.Write(w, byteOrder, val)
 }
@@ -0,0 +1,342 @@
+package sc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+// Traffic directions.
+const (
+	// TrafficOut is the direction of packets sent to scsynth.
+	TrafficOut = "out"
+
+	// TrafficIn is the direction of packets received from scsynth.
+	TrafficIn = "in"
+)
+
+// notificationAddresses are the addresses of messages that scsynth
+// sends to clients without the client asking for them directly.
+var notificationAddresses = []string{
+	"/fail",
+	"/late",
+	"/n_end",
+	"/n_go",
+	"/n_info",
+	"/n_move",
+	"/n_off",
+	"/n_on",
+	"/synced",
+}
+
+// TrafficEvent is a single OSC packet in a recorded session.
+//
+// Sessions are stored as JSON Lines: each line of a session file is a
+// JSON object with the following fields.
+//
+//	t       nanoseconds since the recording started (monotonic clock)
+//	dir     "out" for packets sent to scsynth, "in" for packets received from scsynth
+//	packet  base64-encoded OSC packet (a message or a bundle)
+type TrafficEvent struct {
+	Time      time.Duration `json:"t"`
+	Direction string        `json:"dir"`
+	Packet    []byte        `json:"packet"`
+}
+
+// String returns a short description of a traffic event.
+func (ev TrafficEvent) String() string {
+	return fmt.Sprintf("%s %s at %s", ev.Direction, describePacket(ev.Packet), ev.Time)
+}
+
+// TrafficRecorder writes OSC traffic to an io.Writer.
+// It is safe for concurrent use.
+type TrafficRecorder struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	enc    *json.Encoder
+	closer io.Closer
+	start  time.Time
+	err    error
+}
+
+// NewTrafficRecorder creates a recorder that writes a session to w.
+// Timestamps are relative to the time the recorder is created.
+func NewTrafficRecorder(w io.Writer) *TrafficRecorder {
+	bw := bufio.NewWriter(w)
+	tr := &TrafficRecorder{
+		w:     bw,
+		enc:   json.NewEncoder(bw),
+		start: time.Now(),
+	}
+	if closer, ok := w.(io.Closer); ok {
+		tr.closer = closer
+	}
+	return tr
+}
+
+// CreateTrafficRecorder creates a recorder that writes a session to a file.
+func CreateTrafficRecorder(path string) (*TrafficRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewTrafficRecorder(f), nil
+}
+
+// Record records an OSC packet.
+// direction should be TrafficOut or TrafficIn.
+func (tr *TrafficRecorder) Record(direction string, p osc.Packet) error {
+	return tr.recordBytes(direction, p.Bytes())
+}
+
+// recordBytes records an encoded OSC packet.
+func (tr *TrafficRecorder) recordBytes(direction string, data []byte) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.err != nil {
+		return tr.err
+	}
+	tr.err = tr.enc.Encode(TrafficEvent{
+		Time:      time.Since(tr.start),
+		Direction: direction,
+		Packet:    data,
+	})
+	return tr.err
+}
+
+// Close flushes the recorder and closes the underlying writer
+// if it is an io.Closer.
+func (tr *TrafficRecorder) Close() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if err := tr.w.Flush(); err != nil {
+		return err
+	}
+	if tr.closer != nil {
+		return tr.closer.Close()
+	}
+	return nil
+}
+
+// SetRecorder sets the recorder that is used to record all the
+// OSC packets sent and received by the client.
+// Passing nil stops recording.
+// Closing the recorder is the caller's responsibility.
+func (c *Client) SetRecorder(tr *TrafficRecorder) {
+	c.recorderMu.Lock()
+	c.recorder = tr
+	c.recorderMu.Unlock()
+}
+
+// record records a packet if the client has a recorder.
+func (c *Client) record(direction string, p osc.Packet) {
+	c.recorderMu.RLock()
+	tr := c.recorder
+	c.recorderMu.RUnlock()
+
+	if tr == nil {
+		return
+	}
+	_ = tr.Record(direction, p) // Best effort, the error is returned by subsequent calls.
+}
+
+// recordingHandler wraps an OSC message handler so that
+// every message it handles is recorded.
+func (c *Client) recordingHandler(h osc.MessageHandler) osc.MessageHandler {
+	return osc.Method(func(msg osc.Message) error {
+		c.record(TrafficIn, msg)
+		return h.Handle(msg)
+	})
+}
+
+// TrafficSession is a recorded OSC session.
+type TrafficSession []TrafficEvent
+
+// ReadTrafficSession reads a session that was written by a TrafficRecorder.
+func ReadTrafficSession(r io.Reader) (TrafficSession, error) {
+	var (
+		dec     = json.NewDecoder(r)
+		session = TrafficSession{}
+	)
+	for {
+		var ev TrafficEvent
+		if err := dec.Decode(&ev); err == io.EOF {
+			return session, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if ev.Direction != TrafficOut && ev.Direction != TrafficIn {
+			return nil, fmt.Errorf("unrecognized traffic direction %q", ev.Direction)
+		}
+		session = append(session, ev)
+	}
+}
+
+// OpenTrafficSession reads a session from a file.
+func OpenTrafficSession(path string) (TrafficSession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }() // Best effort.
+
+	return ReadTrafficSession(f)
+}
+
+// Filter returns the events in the session that have the provided direction.
+func (session TrafficSession) Filter(direction string) TrafficSession {
+	filtered := TrafficSession{}
+	for _, ev := range session {
+		if ev.Direction == direction {
+			filtered = append(filtered, ev)
+		}
+	}
+	return filtered
+}
+
+// Diff returns a diff of one session and another.
+// A diff is represented as a slice of pairs of strings.
+// The first string in each pair describes the session on the left (the receiver),
+// and the second string describes the other session.
+// Outgoing and incoming packets are compared separately, in order.
+// Timing is not compared.
+// If the returned slice is empty the sessions contain the same packets.
+func (session TrafficSession) Diff(other TrafficSession) [][2]string {
+	diffs := [][2]string{}
+	for _, dir := range []string{TrafficOut, TrafficIn} {
+		var (
+			s1 = session.Filter(dir)
+			s2 = other.Filter(dir)
+		)
+		for i := 0; i < len(s1) || i < len(s2); i++ {
+			switch {
+			case i >= len(s1):
+				diffs = append(diffs, [2]string{
+					fmt.Sprintf("%s packet %d is missing", dir, i),
+					fmt.Sprintf("%s packet %d is %s", dir, i, describePacket(s2[i].Packet)),
+				})
+			case i >= len(s2):
+				diffs = append(diffs, [2]string{
+					fmt.Sprintf("%s packet %d is %s", dir, i, describePacket(s1[i].Packet)),
+					fmt.Sprintf("%s packet %d is missing", dir, i),
+				})
+			case !bytes.Equal(s1[i].Packet, s2[i].Packet):
+				diffs = append(diffs, [2]string{
+					fmt.Sprintf("%s packet %d is %s", dir, i, describePacket(s1[i].Packet)),
+					fmt.Sprintf("%s packet %d is %s", dir, i, describePacket(s2[i].Packet)),
+				})
+			}
+		}
+	}
+	return diffs
+}
+
+// describePacket returns a short description of an encoded OSC packet.
+func describePacket(data []byte) string {
+	end := bytes.IndexByte(data, 0)
+	if end == -1 {
+		return fmt.Sprintf("invalid packet (%d bytes)", len(data))
+	}
+	if addr := string(data[:end]); addr != "#bundle" {
+		return fmt.Sprintf("%s (%d bytes)", addr, len(data))
+	}
+	return fmt.Sprintf("bundle (%d bytes)", len(data))
+}
+
+// Replayer sends the outgoing packets of a recorded session to a server.
+type Replayer struct {
+	// Session is the session that is replayed.
+	Session TrafficSession
+
+	// Recorder, if not nil, records the packets that are sent
+	// and the replies received during the replay.
+	// The recorded session can be compared to the original with Diff.
+	Recorder *TrafficRecorder
+
+	// ReplyTimeout is how long the replayer waits for replies
+	// after the last packet has been sent.
+	ReplyTimeout time.Duration
+}
+
+// NewReplayer creates a new replayer.
+func NewReplayer(session TrafficSession) *Replayer {
+	return &Replayer{
+		Session:      session,
+		ReplyTimeout: DefaultConnectTimeout,
+	}
+}
+
+// Replay sends the outgoing packets of the session to the server listening at addr (UDP),
+// with the same timing as the original session.
+// Replay returns when every packet has been sent and the reply timeout has elapsed,
+// or when ctx is done. Replies are not recorded after Replay returns.
+func (rp *Replayer) Replay(ctx context.Context, addr string) error {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+	// Stop recording replies before returning, so that nothing is
+	// recorded after Replay returns or after the recorder is closed.
+	var replies chan struct{}
+	defer func() {
+		_ = conn.Close() // Best effort.
+		if replies != nil {
+			<-replies
+		}
+	}()
+	if rp.Recorder != nil {
+		replies = make(chan struct{})
+		go func() {
+			rp.readReplies(conn)
+			close(replies)
+		}()
+	}
+	start := time.Now()
+
+	for _, ev := range rp.Session.Filter(TrafficOut) {
+		select {
+		case <-time.After(ev.Time - time.Since(start)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if _, err := conn.Write(ev.Packet); err != nil {
+			return err
+		}
+		if rp.Recorder != nil {
+			if err := rp.Recorder.recordBytes(TrafficOut, ev.Packet); err != nil {
+				return err
+			}
+		}
+	}
+	select {
+	case <-time.After(rp.ReplyTimeout):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// readReplies records every packet received on conn until conn is closed.
+func (rp *Replayer) readReplies(conn net.Conn) {
+	buf := make([]byte, 65536)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		data := make([]byte, n)
+		copy(data, buf[:n])
+		_ = rp.Recorder.recordBytes(TrafficIn, data) // Best effort.
+	}
+}
@@ -0,0 +1,109 @@
+package sc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scgolang/osc"
+)
+
+func TestTrafficRecorder(t *testing.T) {
+	var (
+		buf = &bytes.Buffer{}
+		tr  = NewTrafficRecorder(buf)
+	)
+	if err := tr.Record(TrafficOut, osc.Message{Address: statusAddress}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Record(TrafficIn, osc.Message{Address: doneOscAddress}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	session, err := ReadTrafficSession(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(session); expected != got {
+		t.Fatalf("expected %d events, got %d", expected, got)
+	}
+	if session[0].Direction != TrafficOut || session[1].Direction != TrafficIn {
+		t.Fatalf("unexpected directions %s and %s", session[0].Direction, session[1].Direction)
+	}
+	if session[1].Time < session[0].Time {
+		t.Fatalf("timestamps are not monotonic: %s < %s", session[1].Time, session[0].Time)
+	}
+	if expected, got := (osc.Message{Address: statusAddress}).Bytes(), session[0].Packet; !bytes.Equal(expected, got) {
+		t.Fatalf("expected packet %q, got %q", expected, got)
+	}
+}
+
+func TestTrafficSessionDiff(t *testing.T) {
+	var (
+		status = osc.Message{Address: statusAddress}.Bytes()
+		quit   = osc.Message{Address: "/quit"}.Bytes()
+		done   = osc.Message{Address: doneOscAddress}.Bytes()
+		s1     = TrafficSession{
+			{Direction: TrafficOut, Packet: status},
+			{Direction: TrafficIn, Packet: done},
+		}
+		s2 = TrafficSession{
+			{Direction: TrafficOut, Packet: quit, Time: time.Second},
+			{Direction: TrafficIn, Packet: done},
+			{Direction: TrafficIn, Packet: done},
+		}
+	)
+	if diffs := s1.Diff(s1); len(diffs) != 0 {
+		t.Fatalf("expected no diffs, got %v", diffs)
+	}
+	diffs := s1.Diff(s2)
+	if expected, got := 2, len(diffs); expected != got {
+		t.Fatalf("expected %d diffs, got %d: %v", expected, got, diffs)
+	}
+	if expected, got := "out packet 0 is /status (12 bytes)", diffs[0][0]; expected != got {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	if expected, got := "in packet 1 is missing", diffs[1][0]; expected != got {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	fs := newFakeServer(t)
+
+	var (
+		status = osc.Message{Address: statusAddress}.Bytes()
+		buf    = &bytes.Buffer{}
+		rp     = NewReplayer(TrafficSession{
+			{Direction: TrafficOut, Packet: status},
+			{Direction: TrafficIn, Packet: osc.Message{Address: statusReplyAddress}.Bytes()},
+			{Direction: TrafficOut, Packet: status, Time: 20 * time.Millisecond},
+		})
+	)
+	rp.Recorder = NewTrafficRecorder(buf)
+	rp.ReplyTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	if err := rp.Replay(context.Background(), fs.Addr()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("replay did not keep the original timing (took %s)", elapsed)
+	}
+	if err := rp.Recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(fs.Received()); expected != got {
+		t.Fatalf("expected the server to receive %d messages, got %d", expected, got)
+	}
+	replayed, err := ReadTrafficSession(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(replayed.Filter(TrafficIn)); expected != got {
+		t.Fatalf("expected %d replies, got %d", expected, got)
+	}
+}
@@ -35,6 +35,11 @@ type Ugen struct {
 	NumOutputs   int         `json:"numOutputs"        xml:"numOutputs,attr"`
 
 	inputs []Input
+
+	// source is set if the ugen is a single channel of a multichannel ugen (see channels),
+	// and channel is the output of source that it reads.
+	source  *Ugen
+	channel int32
 }
 
 // NewUgen is a factory function for creating new Ugen instances.
@@ -458,6 +463,11 @@ func (u *Ugen) Wrap2(val Input) Input {
 
 // Write writes a Ugen
 func (u *Ugen) Write(w io.Writer) error {
+	return u.write(w, synthdefVersion)
+}
+
+// write writes a Ugen using a particular synthdef file version.
+func (u *Ugen) write(w io.Writer, version int32) error {
 	// write the synthdef name
 	if err := newPstring(u.Name).Write(w); err != nil {
 		return err
@@ -468,12 +478,12 @@ func (u *Ugen) Write(w io.Writer) error {
 	}
 	// write inputs
 	numInputs := int32(len(u.Inputs))
-	if err := binary.Write(w, byteOrder, numInputs); err != nil {
+	if err := writeSynthdefInt(w, version, numInputs); err != nil {
 		return err
 	}
 	// write outputs
 	numOutputs := int32(len(u.Outputs))
-	if err := binary.Write(w, byteOrder, numOutputs); err != nil {
+	if err := writeSynthdefInt(w, version, numOutputs); err != nil {
 		return err
 	}
 	// special index
@@ -482,7 +492,7 @@ func (u *Ugen) Write(w io.Writer) error {
 	}
 	// inputs
 	for _, i := range u.Inputs {
-		if err := i.Write(w); err != nil {
+		if err := i.write(w, version); err != nil {
 			return err
 		}
 	}
@@ -508,10 +518,8 @@ func (u *Ugen) inputsOrdered() bool {
 }
 
 // readUgen reads a ugen from an io.Reader
-func readUgen(r io.Reader) (*Ugen, error) {
+func readUgen(r io.Reader, version int32) (*Ugen, error) {
 	var (
-		numInputs    int32
-		numOutputs   int32
 		specialIndex int16
 		rate         int8
 	)
@@ -525,11 +533,13 @@ func readUgen(r io.Reader) (*Ugen, error) {
 		return nil, err
 	}
 	// read number of inputs
-	if err := binary.Read(r, byteOrder, &numInputs); err != nil {
+	numInputs, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, err
 	}
 	// read number of outputs
-	if err := binary.Read(r, byteOrder, &numOutputs); err != nil {
+	numOutputs, err := readSynthdefInt(r, version)
+	if err != nil {
 		return nil, err
 	}
 	// read special index
@@ -542,7 +552,7 @@ func readUgen(r io.Reader) (*Ugen, error) {
 	)
 	// read inputs
 	for i := 0; int32(i) < numInputs; i++ {
-		in, err := readInput(r)
+		in, err := readInput(r, version)
 		if err != nil {
 			return nil, err
 		}
@@ -577,6 +587,40 @@ func asOutput(u *Ugen) *Ugen {
 	return u
 }
 
+// channels returns one input for each output of a ugen.
+// Each of them reads a single output, so when they are passed to another
+// ugen as a multi input every channel ends up on its own input,
+// and math operations on them are expanded once per channel.
+// A ugen with one output is returned as it is.
+func (u *Ugen) channels() []Input {
+	asOutput(u)
+
+	if u.NumOutputs == 1 {
+		return []Input{u}
+	}
+	chans := make([]Input, u.NumOutputs)
+	for i := range chans {
+		chans[i] = &Ugen{
+			Name:         u.Name,
+			Rate:         u.Rate,
+			SpecialIndex: u.SpecialIndex,
+			NumOutputs:   1,
+			source:       u,
+			channel:      int32(i),
+		}
+	}
+	return chans
+}
+
+// sourceUgen returns the ugen that a synthdef contains for u.
+// This is the multichannel ugen if u is one of its channels.
+func (u *Ugen) sourceUgen() *Ugen {
+	if u.source != nil {
+		return u.source
+	}
+	return u
+}
+
 func cloneUgen(v *Ugen) *Ugen {
 	u := *v
 	return &u
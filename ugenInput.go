@@ -77,10 +77,15 @@ func (ui UgenInput) IsConstant() bool {
 
 // Write writes an input to an io.Writer
 func (ui UgenInput) Write(w io.Writer) error {
-	if we := binary.Write(w, byteOrder, ui.UgenIndex); we != nil {
+	return ui.write(w, synthdefVersion)
+}
+
+// write writes a UgenInput using a particular synthdef file version.
+func (ui UgenInput) write(w io.Writer, version int32) error {
+	if we := writeSynthdefInt(w, version, ui.UgenIndex); we != nil {
 		return we
 	}
-	return binary.Write(w, byteOrder, ui.OutputIndex)
+	return writeSynthdefInt(w, version, ui.OutputIndex)
 }
 
 func readUgenInput(r io.Reader) (UgenInput, error) {